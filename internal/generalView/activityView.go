@@ -0,0 +1,227 @@
+package generalview
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Ceinl/Go-dashboard/internal/storage"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const activityLimit = 200
+
+// ActivityView shows the activity log of the current project or the whole
+// workspace, grouped by day.
+type ActivityView struct {
+	Width  int
+	Height int
+
+	db             *sql.DB
+	workspace      storage.Workspace
+	project        storage.Project
+	workspaceScope bool
+	events         []storage.Event
+	projectNames   map[string]string
+	viewport       viewport.Model
+}
+
+type DoneActivityMsg struct{}
+
+func NewActivityView(db *sql.DB, workspace storage.Workspace, project storage.Project) ActivityView {
+	v := ActivityView{
+		db:           db,
+		workspace:    workspace,
+		project:      project,
+		projectNames: make(map[string]string),
+		viewport:     viewport.New(0, 0),
+		// Without a project there is nothing to scope to but the workspace.
+		workspaceScope: project.ID == "",
+	}
+
+	projects, err := storage.GetAllProjectsForWorkspace(db, workspace.ID)
+	if err != nil {
+		log.Printf("Error getting projects for activity view: %v", err)
+	}
+	for _, p := range projects {
+		v.projectNames[p.ID] = p.Name
+	}
+
+	v.loadEvents()
+	return v
+}
+
+func (v ActivityView) Init() tea.Cmd {
+	return nil
+}
+
+func (v ActivityView) Update(msg tea.Msg) (ActivityView, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		v.Width = msg.Width
+		v.Height = msg.Height
+		v.viewport.Width = msg.Width - 4
+		v.viewport.Height = msg.Height - 6
+		v.viewport.SetContent(v.renderEvents())
+		return v, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc":
+			return v, func() tea.Msg { return DoneActivityMsg{} }
+		case "tab":
+			if v.project.ID != "" {
+				v.workspaceScope = !v.workspaceScope
+				v.loadEvents()
+			}
+			return v, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	v.viewport, cmd = v.viewport.Update(msg)
+	return v, cmd
+}
+
+func (v *ActivityView) loadEvents() {
+	var events []storage.Event
+	var err error
+	if v.workspaceScope {
+		events, err = storage.GetEventsForWorkspace(v.db, v.workspace.ID, activityLimit)
+	} else {
+		events, err = storage.GetEventsForProject(v.db, v.project.ID, activityLimit)
+	}
+	if err != nil {
+		log.Printf("Error loading activity: %v", err)
+	}
+	v.events = events
+	v.viewport.SetContent(v.renderEvents())
+	v.viewport.GotoTop()
+}
+
+func (v ActivityView) renderEvents() string {
+	if len(v.events) == 0 {
+		return "No activity yet."
+	}
+
+	dayStyle := lipgloss.NewStyle().Bold(true)
+	timeStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	projectStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("63"))
+
+	var s strings.Builder
+	var currentDay string
+	for _, e := range v.events {
+		local := e.CreatedAt.Local()
+		if day := dayLabel(local, time.Now()); day != currentDay {
+			if currentDay != "" {
+				s.WriteString("\n")
+			}
+			s.WriteString(dayStyle.Render(day) + "\n")
+			currentDay = day
+		}
+
+		line := timeStyle.Render(local.Format("15:04")) + "  "
+		if v.workspaceScope && e.ProjectID != "" && e.EntityType != storage.EntityProject {
+			name, ok := v.projectNames[e.ProjectID]
+			if !ok {
+				name = "deleted project"
+			}
+			line += projectStyle.Render("["+name+"]") + " "
+		}
+		s.WriteString(line + describeEvent(e) + "\n")
+	}
+	return s.String()
+}
+
+// dayLabel names the day of t relative to now, for the feed's section headers.
+func dayLabel(t, now time.Time) string {
+	y1, m1, d1 := t.Date()
+	y2, m2, d2 := now.Date()
+	today := time.Date(y2, m2, d2, 0, 0, 0, 0, now.Location())
+	day := time.Date(y1, m1, d1, 0, 0, 0, 0, now.Location())
+	switch today.Sub(day) {
+	case 0:
+		return "Today"
+	case 24 * time.Hour:
+		return "Yesterday"
+	}
+	return t.Format("Monday, 2 Jan 2006")
+}
+
+// describeEvent turns an event into a short sentence for the activity feed.
+func describeEvent(e storage.Event) string {
+	noun := e.EntityType
+	if e.EntityType == storage.EntityTweet {
+		noun = "draft"
+	}
+	subject := fmt.Sprintf("%s %q", noun, e.Summary)
+
+	switch e.EntityType {
+	case storage.EntityChecklist:
+		switch e.Action {
+		case storage.ActionCreate:
+			return fmt.Sprintf("added %q to the checklist of %q", e.NewValue, e.Summary)
+		case storage.ActionCheck:
+			return fmt.Sprintf("checked %q on %q", e.NewValue, e.Summary)
+		case storage.ActionUncheck:
+			return fmt.Sprintf("unchecked %q on %q", e.NewValue, e.Summary)
+		case storage.ActionDelete:
+			return fmt.Sprintf("removed %q from the checklist of %q", e.OldValue, e.Summary)
+		}
+		return fmt.Sprintf("edited %q on the checklist of %q", e.NewValue, e.Summary)
+	case storage.EntityBlocker:
+		if e.Action == storage.ActionDelete {
+			return fmt.Sprintf("unblocked %q from %q", e.Summary, e.NewValue)
+		}
+		return fmt.Sprintf("blocked %q by %q", e.Summary, e.NewValue)
+	case storage.EntityTimeEntry:
+		if e.Action == storage.ActionCreate {
+			return fmt.Sprintf("started tracking time on %q", e.Summary)
+		}
+		return fmt.Sprintf("tracked %s on %q", e.NewValue, e.Summary)
+	case storage.EntityWIPLimit:
+		switch {
+		case e.Summary == "" && e.NewValue == "strict":
+			return "made WIP limits strict"
+		case e.Summary == "":
+			return "made WIP limits ask for confirmation"
+		case e.Action == storage.ActionDelete:
+			return fmt.Sprintf("removed the WIP limit of %s", e.Summary)
+		}
+		return fmt.Sprintf("set the WIP limit of %s to %s", e.Summary, e.NewValue)
+	}
+
+	switch e.Action {
+	case storage.ActionCreate:
+		if e.EntityType == storage.EntityTask && e.NewValue != "" {
+			return fmt.Sprintf("added %s to %s", subject, e.NewValue)
+		}
+		return "added " + subject
+	case storage.ActionMove:
+		return fmt.Sprintf("moved %s from %s to %s", subject, e.OldValue, e.NewValue)
+	case storage.ActionTransferOut:
+		return fmt.Sprintf("moved %s to project %s", subject, e.NewValue)
+	case storage.ActionTransferIn:
+		return fmt.Sprintf("received %s from project %s", subject, e.OldValue)
+	case storage.ActionDelete:
+		return "deleted " + subject
+	default:
+		return "edited " + subject
+	}
+}
+
+func (v ActivityView) View() string {
+	scope := "Project: " + v.project.Name
+	if v.workspaceScope {
+		scope = "Workspace: " + v.workspace.Name
+	}
+	title := lipgloss.NewStyle().Bold(true).Render("Activity") + "  " + scope
+
+	help := "(tab) project/workspace, (j/k) scroll, (esc) close"
+	content := lipgloss.JoinVertical(lipgloss.Left, title, "", v.viewport.View(), "", help)
+
+	return lipgloss.NewStyle().Margin(1, 2).Render(content)
+}
@@ -3,11 +3,19 @@ package generalview
 import (
 	"testing"
 
+	"github.com/Ceinl/Go-dashboard/internal/storage"
+
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 func TestCreateWorkspaceView_TextInput(t *testing.T) {
-	v := NewCreateWorkspaceView()
+	db, err := storage.InitDB("file::memory:?cache=shared")
+	if err != nil {
+		t.Fatalf("failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	v := NewCreateWorkspaceView(db)
 	cmd := v.Init()
 	if cmd != nil {
 		cmd()
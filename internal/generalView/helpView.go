@@ -27,6 +27,9 @@ func NewHelpView() HelpView {
 		{key: ":swapp", description: "Swap a project"},
 		{key: ":help", description: "Show this help screen"},
 		{key: ":config-modules", description: "Configure modules for a workspace"},
+		{key: "ctrl+f, /, :search", description: "Search tasks, links and drafts everywhere"},
+		{key: ":activity", description: "Show recent activity for the project or workspace"},
+		{key: "ctrl+o, :overview", description: "Show the workspace overview"},
 		{key: "shift+h/l", description: "Switch between projects"},
 		{key: "ctrl+h/l", description: "Switch between modules"},
 		{key: "ctrl+s", description: "Save tweet as draft"},
@@ -34,6 +37,41 @@ func NewHelpView() HelpView {
 		{key: "d", description: "Delete an item (linksaver, kanban)"},
 		{key: "p", description: "Paste from clipboard (linksaver)"},
 		{key: "enter", description: "Open a link (linksaver)"},
+		{key: "/", description: "Filter items in the current module, esc clears (search elsewhere)"},
+		{key: "i", description: "Show task history (kanban)"},
+		{key: "t", description: "Set a task's due date (kanban)"},
+		{key: "f", description: "Start a focus session on a task (kanban)"},
+		{key: "s", description: "Start or stop tracking time on a task (kanban)"},
+		{key: "[ / ]", description: "Previous or next month (calendar)"},
+		{key: "v", description: "Toggle month grid and week agenda (calendar)"},
+		{key: "p", description: "Set the workspace's .ics file (calendar)"},
+		{key: "b", description: "Link a task to a repository branch (kanban)"},
+		{key: "M", description: "Move tasks with merged branches to Done (kanban)"},
+		{key: "enter", description: "Open a card's checklist (kanban)"},
+		{key: "D", description: "Show and edit a task's blockers (kanban)"},
+		{key: "R", description: "Set how a task repeats (kanban)"},
+		{key: "w", description: "Set the WIP limit of a column (kanban)"},
+		{key: "W", description: "Refuse or confirm moves over a WIP limit (kanban)"},
+		{key: "m", description: "Show flow metrics of the board (kanban)"},
+		{key: "x", description: "Mark a task to move or copy with others (kanban)"},
+		{key: "P", description: "Move tasks to another project (kanban)"},
+		{key: "c", description: "Copy tasks to another project (kanban)"},
+		{key: "g", description: "Group the board into swimlanes by label, priority or assignee (kanban)"},
+		{key: "z", description: "Collapse or expand a swimlane (kanban)"},
+		{key: "r", description: "Refresh repository status now (git)"},
+		{key: "enter, x", description: "Run or stop the selected command (runner)"},
+		{key: "r", description: "Read system statistics now (sysmon)"},
+		{key: "s", description: "Save a feed item to the LinkSaver (feeds)"},
+		{key: "space", description: "Check in today on the selected habit (habits)"},
+		{key: "enter, i", description: "Copy a snippet, or tweak it in $EDITOR and copy the result (snippets)"},
+		{key: "g", description: "Move a snippet between the project and global scope (snippets)"},
+		{key: "h / l, [ / ]", description: "Previous or next day, or day with an entry (journal)"},
+		{key: "m, A", description: "Toggle an item read, or mark the whole feed read (feeds)"},
+		{key: "i, x", description: "Include or exclude lines by regex (logtail)"},
+		{key: "space", description: "Pause or resume following (logtail)"},
+		{key: "n / N", description: "Next or previous search match (logtail)"},
+		{key: ":report", description: "Show tracked time over a date range, export CSV"},
+		{key: "space", description: "Start or pause the focus timer (timer)"},
 	}
 
 	var content strings.Builder
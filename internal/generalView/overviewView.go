@@ -0,0 +1,185 @@
+package generalview
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Ceinl/Go-dashboard/internal/module"
+	"github.com/Ceinl/Go-dashboard/internal/storage"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// projectStatuses are the values "s" cycles through on the overview.
+var projectStatuses = []string{"", "active", "on hold", "done"}
+
+// OverviewView is the workspace home screen: one row per project with its
+// task, link and draft counts.
+type OverviewView struct {
+	Width  int
+	Height int
+
+	db        *sql.DB
+	workspace storage.Workspace
+	summaries []storage.ProjectSummary
+	cursor    int
+}
+
+// DoneOverviewMsg closes the overview. Project is set when one was opened.
+type DoneOverviewMsg struct {
+	Project storage.Project
+}
+
+func NewOverviewView(db *sql.DB, workspace storage.Workspace, currentProjectID string) OverviewView {
+	v := OverviewView{db: db, workspace: workspace}
+	v.load()
+	for i, s := range v.summaries {
+		if s.Project.ID == currentProjectID {
+			v.cursor = i
+		}
+	}
+	return v
+}
+
+func (v *OverviewView) load() {
+	summaries, err := storage.GetProjectSummaries(v.db, v.workspace.ID, time.Now())
+	if err != nil {
+		log.Printf("Error loading workspace overview: %v", err)
+	}
+	v.summaries = summaries
+	if v.cursor >= len(v.summaries) {
+		v.cursor = 0
+	}
+}
+
+func (v OverviewView) Init() tea.Cmd {
+	return nil
+}
+
+func (v OverviewView) Update(msg tea.Msg) (OverviewView, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		v.Width = msg.Width
+		v.Height = msg.Height
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc":
+			return v, func() tea.Msg { return DoneOverviewMsg{} }
+		case "up", "k":
+			if v.cursor > 0 {
+				v.cursor--
+			}
+		case "down", "j":
+			if v.cursor < len(v.summaries)-1 {
+				v.cursor++
+			}
+		case "enter":
+			if v.cursor < len(v.summaries) {
+				project := v.summaries[v.cursor].Project
+				return v, func() tea.Msg { return DoneOverviewMsg{Project: project} }
+			}
+		case "s":
+			v.cycleStatus()
+		case "r":
+			v.load()
+		}
+	}
+	return v, nil
+}
+
+func (v *OverviewView) cycleStatus() {
+	if v.cursor >= len(v.summaries) {
+		return
+	}
+	project := v.summaries[v.cursor].Project
+	next := 0
+	for i, status := range projectStatuses {
+		if status == project.Status {
+			next = (i + 1) % len(projectStatuses)
+		}
+	}
+	project.Status = projectStatuses[next]
+	if err := storage.UpdateProject(v.db, project); err != nil {
+		log.Printf("Error updating project status: %v", err)
+		return
+	}
+	v.load()
+}
+
+func (v OverviewView) View() string {
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("63"))
+	selectedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("229")).Background(lipgloss.Color("57"))
+	overdueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+
+	kanbanColumns := module.GetKanbanColumns()
+	header := []string{padCell("Project", 24), padCell("Status", 10)}
+	for _, col := range kanbanColumns {
+		header = append(header, padCell(col, 12))
+	}
+	header = append(header, padCell("Overdue", 8), padCell("Links", 6), padCell("Drafts", 7), "Last activity")
+
+	var rows []string
+	rows = append(rows, headerStyle.Render(strings.Join(header, " ")))
+	for i, s := range v.summaries {
+		status := s.Project.Status
+		if status == "" {
+			status = "-"
+		}
+		cells := []string{padCell(s.Project.Name, 24), padCell(status, 10)}
+		for _, col := range kanbanColumns {
+			cells = append(cells, padCell(fmt.Sprint(s.TaskCounts[col]), 12))
+		}
+		overdue := padCell(fmt.Sprint(s.OverdueTasks), 8)
+		if s.OverdueTasks > 0 && i != v.cursor {
+			overdue = overdueStyle.Render(overdue)
+		}
+		cells = append(cells, overdue, padCell(fmt.Sprint(s.Links), 6), padCell(fmt.Sprint(s.Drafts), 7), timeAgo(s.LastActivity, time.Now()))
+
+		row := strings.Join(cells, " ")
+		if i == v.cursor {
+			row = selectedStyle.Render(row)
+		}
+		rows = append(rows, row)
+	}
+	if len(v.summaries) == 0 {
+		rows = append(rows, dimStyle.Render("No projects yet. Create one with :newp"))
+	}
+
+	title := lipgloss.NewStyle().Bold(true).Render(v.workspace.Name + " overview")
+	help := dimStyle.Render("(enter) open project, (j/k) navigate, (s) cycle status, (r) refresh, (esc) close")
+	content := lipgloss.JoinVertical(lipgloss.Left, title, "", strings.Join(rows, "\n"), "", help)
+
+	return lipgloss.Place(v.Width, v.Height, lipgloss.Center, lipgloss.Center, content)
+}
+
+// padCell truncates or pads s to exactly width cells.
+func padCell(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) > width {
+		return string(runes[:width-1]) + "…"
+	}
+	return s + strings.Repeat(" ", width-len(runes))
+}
+
+// timeAgo describes how long before now t happened, e.g. "5m ago".
+func timeAgo(t, now time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	case d < 30*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+	return t.Local().Format("2 Jan 2006")
+}
@@ -0,0 +1,180 @@
+package generalview
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/Ceinl/Go-dashboard/internal/storage"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const searchResultLimit = 50
+
+// searchGroups controls the order in which result types are listed.
+var searchGroups = []struct {
+	kind  string
+	title string
+}{
+	{storage.SearchKindTask, "Tasks"},
+	{storage.SearchKindLink, "Links"},
+	{storage.SearchKindTweet, "Tweet drafts"},
+}
+
+type SearchView struct {
+	Width  int
+	Height int
+
+	db      *sql.DB
+	input   textinput.Model
+	results []storage.SearchResult // grouped by kind, ranked within a group
+	cursor  int
+}
+
+// DoneSearchMsg closes the search overlay. Result is empty when the search was cancelled.
+type DoneSearchMsg struct {
+	Result storage.SearchResult
+}
+
+func NewSearchView(db *sql.DB) SearchView {
+	ti := textinput.New()
+	ti.Placeholder = "Search tasks, links and drafts"
+	ti.Prompt = "/ "
+	ti.CharLimit = 100
+	ti.Width = 50
+	ti.Focus()
+
+	return SearchView{db: db, input: ti}
+}
+
+func (v SearchView) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (v SearchView) Update(msg tea.Msg) (SearchView, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		v.Width = msg.Width
+		v.Height = msg.Height
+		v.input.Width = msg.Width / 2
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			return v, func() tea.Msg { return DoneSearchMsg{} }
+		case "enter":
+			if v.cursor < len(v.results) {
+				result := v.results[v.cursor]
+				return v, func() tea.Msg { return DoneSearchMsg{Result: result} }
+			}
+			return v, nil
+		case "up", "ctrl+k", "ctrl+p":
+			if v.cursor > 0 {
+				v.cursor--
+			}
+			return v, nil
+		case "down", "ctrl+j", "ctrl+n":
+			if v.cursor < len(v.results)-1 {
+				v.cursor++
+			}
+			return v, nil
+		}
+
+		var cmd tea.Cmd
+		previous := v.input.Value()
+		v.input, cmd = v.input.Update(msg)
+		if v.input.Value() != previous {
+			v.runSearch()
+		}
+		return v, cmd
+	}
+	return v, nil
+}
+
+func (v *SearchView) runSearch() {
+	v.cursor = 0
+	results, err := storage.Search(v.db, v.input.Value(), searchResultLimit)
+	if err != nil {
+		log.Printf("Error searching: %v", err)
+		v.results = nil
+		return
+	}
+
+	// Keep the ranking inside each group, but list the groups in a fixed order
+	// so that the cursor walks the results in the same order they're drawn.
+	grouped := make([]storage.SearchResult, 0, len(results))
+	for _, group := range searchGroups {
+		for _, r := range results {
+			if r.Kind == group.kind {
+				grouped = append(grouped, r)
+			}
+		}
+	}
+	v.results = grouped
+}
+
+func (v SearchView) View() string {
+	selectedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("229")).Background(lipgloss.Color("57"))
+	contextStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	headerStyle := lipgloss.NewStyle().Bold(true).MarginTop(1)
+
+	var s strings.Builder
+	s.WriteString(v.input.View())
+	s.WriteString("\n")
+
+	if strings.TrimSpace(v.input.Value()) != "" && len(v.results) == 0 {
+		s.WriteString("\nNo results\n")
+	}
+
+	index := 0
+	for _, group := range searchGroups {
+		var lines []string
+		for index < len(v.results) && v.results[index].Kind == group.kind {
+			r := v.results[index]
+			label := searchResultLabel(r)
+			if index == v.cursor {
+				label = selectedStyle.Render("> " + label)
+			} else {
+				label = "  " + label
+			}
+			lines = append(lines, label+" "+contextStyle.Render(r.WorkspaceName+" > "+r.ProjectName))
+			index++
+		}
+		if len(lines) > 0 {
+			s.WriteString(headerStyle.Render(fmt.Sprintf("%s (%d)", group.title, len(lines))))
+			s.WriteString("\n")
+			s.WriteString(strings.Join(lines, "\n"))
+			s.WriteString("\n")
+		}
+	}
+
+	s.WriteString("\n(enter) open, (up/down) navigate, (esc) close")
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("63")).
+		Padding(1, 2).
+		Render(s.String())
+
+	return lipgloss.Place(v.Width, v.Height, lipgloss.Center, lipgloss.Center, box)
+}
+
+func searchResultLabel(r storage.SearchResult) string {
+	var label string
+	switch r.Kind {
+	case storage.SearchKindLink:
+		label = r.Title + ": " + r.Body
+	case storage.SearchKindTweet:
+		label = strings.SplitN(r.Body, "\n", 2)[0]
+	default:
+		label = r.Title
+	}
+
+	const maxLabelWidth = 60
+	if runes := []rune(label); len(runes) > maxLabelWidth {
+		label = string(runes[:maxLabelWidth-1]) + "…"
+	}
+	return label
+}
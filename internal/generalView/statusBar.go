@@ -11,6 +11,7 @@ type StatusBar struct {
 	Command         string
 	ActiveWorkspace string
 	ActiveProject   string
+	Indicators      string // module status texts, e.g. a running focus timer
 }
 
 // New message type for newWorkspace command
@@ -25,6 +26,10 @@ type TwitterPostCommandMsg struct{}
 type DeleteProjectCommandMsg struct{}
 type ModuleSelectorCommandMsg struct{}
 type WorkspaceModuleSelectorCommandMsg struct{}
+type SearchCommandMsg struct{}
+type TimeReportCommandMsg struct{}
+type ActivityCommandMsg struct{}
+type OverviewCommandMsg struct{}
 
 func (s StatusBar) Init() tea.Cmd {
 	return nil
@@ -66,6 +71,14 @@ func (s StatusBar) Update(msg tea.Msg) (StatusBar, tea.Cmd) {
 					return s, func() tea.Msg { return ModuleSelectorCommandMsg{} }
 				case "config-modules":
 					return s, func() tea.Msg { return WorkspaceModuleSelectorCommandMsg{} }
+				case "search":
+					return s, func() tea.Msg { return SearchCommandMsg{} }
+				case "activity", "log":
+					return s, func() tea.Msg { return ActivityCommandMsg{} }
+				case "overview", "home":
+					return s, func() tea.Msg { return OverviewCommandMsg{} }
+				case "report", "time":
+					return s, func() tea.Msg { return TimeReportCommandMsg{} }
 				}
 			case tea.KeyEsc:
 				s.CommandMode = false
@@ -84,6 +97,12 @@ func (s StatusBar) Update(msg tea.Msg) (StatusBar, tea.Cmd) {
 			if msg.String() == "?" {
 				return s, func() tea.Msg { return HelpCommandMsg{} }
 			}
+			if msg.String() == "ctrl+f" {
+				return s, func() tea.Msg { return SearchCommandMsg{} }
+			}
+			if msg.String() == "ctrl+o" {
+				return s, func() tea.Msg { return OverviewCommandMsg{} }
+			}
 		}
 	}
 	return s, nil
@@ -98,6 +117,10 @@ func (s StatusBar) View() string {
 	}
 
 	right := s.ActiveWorkspace + " > " + s.ActiveProject
+	if s.Indicators != "" {
+		right = lipgloss.NewStyle().Foreground(lipgloss.Color("212")).Background(lipgloss.Color("235")).Render(s.Indicators) +
+			"  " + right
+	}
 
 	// Calculate available width for the left part
 	leftWidth := s.Width - lipgloss.Width(right)
@@ -0,0 +1,275 @@
+package generalview
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Ceinl/Go-dashboard/internal/storage"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// reportGroupings are the values "g" cycles through in the time report.
+var reportGroupings = []string{storage.GroupByTask, storage.GroupByProject, storage.GroupByWorkspace}
+
+const (
+	reportFocusFrom = iota
+	reportFocusTo
+	reportFocusTable
+)
+
+// TimeReportView sums tracked time per task, project or workspace over a
+// date range, and exports the result as CSV.
+type TimeReportView struct {
+	Width  int
+	Height int
+
+	db       *sql.DB
+	from     textinput.Model
+	to       textinput.Model
+	focus    int
+	grouping int
+	rows     []storage.TimeReportRow
+	status   string
+}
+
+type DoneTimeReportMsg struct{}
+
+func NewTimeReportView(db *sql.DB) TimeReportView {
+	now := time.Now()
+	from := textinput.New()
+	from.Placeholder = storage.DateLayout
+	from.CharLimit = len(storage.DateLayout)
+	from.Width = len(storage.DateLayout) + 1
+	from.SetValue(time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local).Format(storage.DateLayout))
+
+	to := textinput.New()
+	to.Placeholder = storage.DateLayout
+	to.CharLimit = len(storage.DateLayout)
+	to.Width = len(storage.DateLayout) + 1
+	to.SetValue(now.Format(storage.DateLayout))
+
+	v := TimeReportView{db: db, from: from, to: to, focus: reportFocusTable}
+	v.load()
+	return v
+}
+
+func (v TimeReportView) Init() tea.Cmd {
+	return nil
+}
+
+// dateRange returns the start of the "from" day and the end of the "to" day.
+func (v TimeReportView) dateRange() (time.Time, time.Time, error) {
+	from, err := time.ParseInLocation(storage.DateLayout, strings.TrimSpace(v.from.Value()), time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid start date %q", v.from.Value())
+	}
+	to, err := time.ParseInLocation(storage.DateLayout, strings.TrimSpace(v.to.Value()), time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid end date %q", v.to.Value())
+	}
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, fmt.Errorf("the end date is before the start date")
+	}
+	return from, to.AddDate(0, 0, 1), nil
+}
+
+// load reads the report for the entered dates.
+func (v *TimeReportView) load() error {
+	from, to, err := v.dateRange()
+	if err != nil {
+		v.status = err.Error()
+		v.rows = nil
+		return err
+	}
+	rows, err := storage.GetTimeReport(v.db, from, to, reportGroupings[v.grouping], time.Now())
+	if err != nil {
+		log.Printf("Error loading time report: %v", err)
+		v.status = "Loading failed: " + err.Error()
+		v.rows = nil
+		return err
+	}
+	v.rows = rows
+	v.status = ""
+	return nil
+}
+
+func (v TimeReportView) Update(msg tea.Msg) (TimeReportView, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		v.Width = msg.Width
+		v.Height = msg.Height
+		return v, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			return v, func() tea.Msg { return DoneTimeReportMsg{} }
+		case "tab":
+			return v, v.setFocus((v.focus + 1) % 3)
+		case "shift+tab":
+			return v, v.setFocus((v.focus + 2) % 3)
+		}
+
+		if v.focus != reportFocusTable {
+			if msg.Type == tea.KeyEnter {
+				v.load()
+				return v, v.setFocus(reportFocusTable)
+			}
+			var cmd tea.Cmd
+			if v.focus == reportFocusFrom {
+				v.from, cmd = v.from.Update(msg)
+			} else {
+				v.to, cmd = v.to.Update(msg)
+			}
+			return v, cmd
+		}
+
+		switch msg.String() {
+		case "q":
+			return v, func() tea.Msg { return DoneTimeReportMsg{} }
+		case "g":
+			v.grouping = (v.grouping + 1) % len(reportGroupings)
+			v.load()
+		case "w":
+			now := time.Now()
+			weekday := (int(now.Weekday()) + 6) % 7 // days since Monday
+			v.from.SetValue(now.AddDate(0, 0, -weekday).Format(storage.DateLayout))
+			v.to.SetValue(now.Format(storage.DateLayout))
+			v.load()
+		case "m":
+			now := time.Now()
+			v.from.SetValue(time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local).Format(storage.DateLayout))
+			v.to.SetValue(now.Format(storage.DateLayout))
+			v.load()
+		case "e":
+			v.export()
+		case "r":
+			v.load()
+		}
+	}
+	return v, nil
+}
+
+func (v *TimeReportView) setFocus(focus int) tea.Cmd {
+	v.focus = focus
+	v.from.Blur()
+	v.to.Blur()
+	switch focus {
+	case reportFocusFrom:
+		return v.from.Focus()
+	case reportFocusTo:
+		return v.to.Focus()
+	}
+	return nil
+}
+
+// export writes the report to a CSV file in the working directory. The
+// report is read again first, as the dates may have been edited since.
+func (v *TimeReportView) export() {
+	if err := v.load(); err != nil {
+		return
+	}
+	name := fmt.Sprintf("time-report-%s-%s.csv", strings.TrimSpace(v.from.Value()), strings.TrimSpace(v.to.Value()))
+	file, err := os.Create(name)
+	if err != nil {
+		log.Printf("Error creating %s: %v", name, err)
+		v.status = "Export failed: " + err.Error()
+		return
+	}
+	defer file.Close()
+
+	if err := writeTimeReportCSV(file, v.rows, reportGroupings[v.grouping]); err != nil {
+		log.Printf("Error writing %s: %v", name, err)
+		v.status = "Export failed: " + err.Error()
+		return
+	}
+	v.status = "Exported to " + name
+}
+
+// writeTimeReportCSV writes one line per report row with the duration in
+// decimal hours, ready for invoicing.
+func writeTimeReportCSV(w io.Writer, rows []storage.TimeReportRow, grouping string) error {
+	header := reportColumns(grouping)
+	out := csv.NewWriter(w)
+	if err := out.Write(append(header, "Hours")); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		record := reportCells(row, grouping)
+		record = append(record, fmt.Sprintf("%.2f", row.Duration.Hours()))
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+func reportColumns(grouping string) []string {
+	switch grouping {
+	case storage.GroupByTask:
+		return []string{"Workspace", "Project", "Task"}
+	case storage.GroupByProject:
+		return []string{"Workspace", "Project"}
+	}
+	return []string{"Workspace"}
+}
+
+func reportCells(row storage.TimeReportRow, grouping string) []string {
+	return []string{row.WorkspaceName, row.ProjectName, row.TaskTitle}[:len(reportColumns(grouping))]
+}
+
+// formatHours formats a duration as hours and minutes, e.g. "12:05".
+func formatHours(d time.Duration) string {
+	d = d.Round(time.Minute)
+	return fmt.Sprintf("%d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
+
+func (v TimeReportView) View() string {
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("63"))
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	grouping := reportGroupings[v.grouping]
+
+	columns := reportColumns(grouping)
+	var header []string
+	for _, col := range columns {
+		header = append(header, padCell(col, 24))
+	}
+	header = append(header, "Hours")
+
+	lines := []string{headerStyle.Render(strings.Join(header, " "))}
+	var total time.Duration
+	for _, row := range v.rows {
+		var cells []string
+		for _, cell := range reportCells(row, grouping) {
+			cells = append(cells, padCell(cell, 24))
+		}
+		cells = append(cells, formatHours(row.Duration))
+		lines = append(lines, strings.Join(cells, " "))
+		total += row.Duration
+	}
+	if len(v.rows) == 0 {
+		lines = append(lines, dimStyle.Render("No time tracked in this range."))
+	} else {
+		totalLabel := padCell("Total", 25*len(columns)-1)
+		lines = append(lines, lipgloss.NewStyle().Bold(true).Render(totalLabel+" "+formatHours(total)))
+	}
+
+	dateRange := fmt.Sprintf("From %s  To %s  Grouped by %s", v.from.View(), v.to.View(), grouping)
+	help := dimStyle.Render("(tab) edit dates, (g) grouping, (w)eek, (m)onth, (e)xport CSV, (esc) close")
+
+	parts := []string{lipgloss.NewStyle().Bold(true).Render("Time report"), "", dateRange, "", strings.Join(lines, "\n"), ""}
+	if v.status != "" {
+		parts = append(parts, v.status)
+	}
+	parts = append(parts, help)
+
+	return lipgloss.Place(v.Width, v.Height, lipgloss.Center, lipgloss.Center, lipgloss.JoinVertical(lipgloss.Left, parts...))
+}
@@ -0,0 +1,70 @@
+package generalview
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ceinl/Go-dashboard/internal/storage"
+)
+
+func TestWriteTimeReportCSV(t *testing.T) {
+	rows := []storage.TimeReportRow{
+		{WorkspaceName: "Client", ProjectName: "Website, v2", Duration: 90 * time.Minute},
+		{WorkspaceName: "Client", ProjectName: "App", Duration: 20 * time.Minute},
+	}
+
+	var out strings.Builder
+	if err := writeTimeReportCSV(&out, rows, storage.GroupByProject); err != nil {
+		t.Fatalf("failed to write CSV: %v", err)
+	}
+
+	want := "Workspace,Project,Hours\nClient,\"Website, v2\",1.50\nClient,App,0.33\n"
+	if out.String() != want {
+		t.Errorf("unexpected CSV:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func TestTimeReportExportReadsEditedDates(t *testing.T) {
+	db, err := storage.InitDB("file:timereport?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatalf("failed to initialize database: %v", err)
+	}
+	defer db.Close()
+	t.Chdir(t.TempDir())
+
+	workspace := storage.Workspace{ID: "w", Name: "Client"}
+	project := storage.Project{ID: "p", WorkspaceID: workspace.ID, Name: "Website"}
+	task := storage.Task{ID: "t", ProjectID: project.ID, Title: "Design", Status: "To Do"}
+	if err := storage.CreateWorkspace(db, workspace); err != nil {
+		t.Fatalf("failed to create workspace: %v", err)
+	}
+	if err := storage.CreateProject(db, project); err != nil {
+		t.Fatalf("failed to create project: %v", err)
+	}
+	if err := storage.CreateTask(db, task); err != nil {
+		t.Fatalf("failed to create task: %v", err)
+	}
+	start := time.Date(2024, 3, 5, 9, 0, 0, 0, time.Local)
+	if err := storage.StartTimeEntry(db, storage.TimeEntry{ID: "e", TaskID: task.ID, StartedAt: start}); err != nil {
+		t.Fatalf("failed to start time entry: %v", err)
+	}
+	if err := storage.StopTimeEntry(db, start.Add(2*time.Hour)); err != nil {
+		t.Fatalf("failed to stop time entry: %v", err)
+	}
+
+	// The dates are edited but the report isn't reloaded before exporting.
+	v := NewTimeReportView(db)
+	v.from.SetValue("2024-03-01")
+	v.to.SetValue("2024-03-31")
+	v.export()
+
+	data, err := os.ReadFile("time-report-2024-03-01-2024-03-31.csv")
+	if err != nil {
+		t.Fatalf("failed to read export: %v (%s)", err, v.status)
+	}
+	if !strings.Contains(string(data), "Client,Website,Design,2.00") {
+		t.Errorf("expected the export to cover the edited dates, got:\n%s", data)
+	}
+}
@@ -0,0 +1,460 @@
+package module
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Ceinl/Go-dashboard/internal/storage"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// OpenTaskMsg asks the app to switch to a task's project and select it on
+// the Kanban board.
+type OpenTaskMsg struct {
+	WorkspaceID string
+	ProjectID   string
+	TaskID      string
+}
+
+// calendarItem is a task due or an imported event on a given day.
+type calendarItem struct {
+	task  *storage.DueTask
+	event *icsEvent
+}
+
+func (i calendarItem) label() string {
+	if i.task != nil {
+		return i.task.Title
+	}
+	if i.event.AllDay {
+		return i.event.Summary
+	}
+	return i.event.Start.Format("15:04") + " " + i.event.Summary
+}
+
+// Calendar shows task due dates and events from the workspace's .ics file as
+// a month grid or a week agenda.
+type Calendar struct {
+	db          *sql.DB
+	workspaceID string
+	projectID   string
+
+	tasks          []storage.DueTask
+	events         []icsEvent
+	calendarPath   string
+	calendarErr    string
+	workspaceScope bool // show due tasks of every project in the workspace
+
+	selected   time.Time // local midnight of the selected day
+	weekView   bool
+	itemCursor int // index into the selected day's items
+
+	editingPath bool
+	input       textinput.Model
+
+	width  int
+	height int
+}
+
+func NewCalendar(db *sql.DB, workspaceID, projectID string) Module {
+	ti := textinput.New()
+	ti.Placeholder = "Path to an .ics file (empty removes it)"
+	ti.CharLimit = 512
+	ti.Width = 60
+
+	return &Calendar{
+		db:          db,
+		workspaceID: workspaceID,
+		projectID:   projectID,
+		selected:    startOfDay(time.Now()),
+		input:       ti,
+	}
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// startOfWeek returns the Monday of the week containing day.
+func startOfWeek(day time.Time) time.Time {
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+func (m *Calendar) Init() tea.Cmd {
+	m.loadTasks()
+	m.loadEvents()
+	return nil
+}
+
+func (m *Calendar) loadTasks() {
+	projectID := m.projectID
+	if m.workspaceScope {
+		projectID = ""
+	}
+	tasks, err := storage.GetDueTasks(m.db, m.workspaceID, projectID)
+	if err != nil {
+		log.Printf("Error loading due tasks: %v", err)
+	}
+	m.tasks = tasks
+}
+
+func (m *Calendar) loadEvents() {
+	m.events = nil
+	m.calendarErr = ""
+
+	path, err := storage.GetWorkspaceCalendarPath(m.db, m.workspaceID)
+	if err != nil {
+		log.Printf("Error loading calendar path: %v", err)
+		return
+	}
+	m.calendarPath = path
+	if path == "" {
+		return
+	}
+
+	file, err := os.Open(expandHome(path))
+	if err != nil {
+		m.calendarErr = err.Error()
+		return
+	}
+	defer file.Close()
+
+	events, err := parseICS(file)
+	if err != nil {
+		m.calendarErr = err.Error()
+		return
+	}
+	m.events = events
+}
+
+func expandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return path
+}
+
+// CapturingInput reports whether the module needs every key press.
+func (m *Calendar) CapturingInput() bool {
+	return m.editingPath
+}
+
+func (m *Calendar) Update(msg tea.Msg) (Module, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+	case TasksChangedMsg:
+		m.loadTasks()
+		return m, nil
+	case tea.KeyMsg:
+		if m.editingPath {
+			return m.updateEditingPath(msg)
+		}
+		return m.updateBrowsing(msg)
+	}
+	return m, nil
+}
+
+func (m *Calendar) updateEditingPath(msg tea.KeyMsg) (Module, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		path := strings.TrimSpace(m.input.Value())
+		if err := storage.SetWorkspaceCalendarPath(m.db, m.workspaceID, path); err != nil {
+			log.Printf("Error saving calendar path: %v", err)
+		}
+		m.editingPath = false
+		m.input.Blur()
+		m.loadEvents()
+		return m, nil
+	case tea.KeyEsc:
+		m.editingPath = false
+		m.input.Blur()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m *Calendar) updateBrowsing(msg tea.KeyMsg) (Module, tea.Cmd) {
+	switch msg.String() {
+	case "left", "h":
+		m.moveSelection(m.selected.AddDate(0, 0, -1))
+	case "right", "l":
+		m.moveSelection(m.selected.AddDate(0, 0, 1))
+	case "up", "k":
+		m.moveSelection(m.selected.AddDate(0, 0, -7))
+	case "down", "j":
+		m.moveSelection(m.selected.AddDate(0, 0, 7))
+	case "[":
+		m.moveSelection(m.selected.AddDate(0, -1, 0))
+	case "]":
+		m.moveSelection(m.selected.AddDate(0, 1, 0))
+	case "t":
+		m.moveSelection(startOfDay(time.Now()))
+	case "v":
+		m.weekView = !m.weekView
+	case "tab":
+		m.workspaceScope = !m.workspaceScope
+		m.loadTasks()
+		m.itemCursor = 0
+	case "J":
+		if m.itemCursor < len(m.itemsOn(m.selected))-1 {
+			m.itemCursor++
+		}
+	case "K":
+		if m.itemCursor > 0 {
+			m.itemCursor--
+		}
+	case "enter":
+		items := m.itemsOn(m.selected)
+		if m.itemCursor < len(items) && items[m.itemCursor].task != nil {
+			task := items[m.itemCursor].task
+			return m, func() tea.Msg {
+				return OpenTaskMsg{WorkspaceID: task.WorkspaceID, ProjectID: task.ProjectID, TaskID: task.ID}
+			}
+		}
+	case "p":
+		m.editingPath = true
+		m.input.SetValue(m.calendarPath)
+		m.input.CursorEnd()
+		return m, m.input.Focus()
+	case "r":
+		m.loadTasks()
+		m.loadEvents()
+	}
+	return m, nil
+}
+
+func (m *Calendar) moveSelection(day time.Time) {
+	m.selected = startOfDay(day)
+	m.itemCursor = 0
+}
+
+// itemsOn returns the tasks due and the events on day: tasks first, then
+// all-day events, then timed events by start time.
+func (m *Calendar) itemsOn(day time.Time) []calendarItem {
+	var items []calendarItem
+	date := day.Format(storage.DateLayout)
+	for i := range m.tasks {
+		if m.tasks[i].DueDate == date {
+			items = append(items, calendarItem{task: &m.tasks[i]})
+		}
+	}
+	for _, allDay := range []bool{true, false} {
+		for i := range m.events {
+			if m.events[i].AllDay == allDay && m.events[i].occursOn(day) {
+				items = append(items, calendarItem{event: &m.events[i]})
+			}
+		}
+	}
+	return items
+}
+
+func (m *Calendar) itemStyle(item calendarItem, today time.Time) lipgloss.Style {
+	style := lipgloss.NewStyle()
+	switch {
+	case item.event != nil:
+		return style.Foreground(lipgloss.Color("39"))
+	case item.task.Status == storage.DoneStatus:
+		return style.Foreground(lipgloss.Color("240")).Strikethrough(true)
+	case item.task.Overdue(today):
+		return style.Foreground(lipgloss.Color("196"))
+	}
+	return style.Foreground(lipgloss.Color("212"))
+}
+
+func (m *Calendar) View() string {
+	if m.width == 0 {
+		return "loading..."
+	}
+	if m.editingPath {
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.input.View())
+	}
+
+	scope := "project"
+	if m.workspaceScope {
+		scope = "workspace"
+	}
+	titleText := m.selected.Format("January 2006")
+	if m.weekView {
+		monday := startOfWeek(m.selected)
+		titleText = fmt.Sprintf("Week of %s", monday.Format("2 Jan 2006"))
+	}
+	title := lipgloss.NewStyle().Bold(true).Render(titleText) +
+		lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("  tasks due in this "+scope)
+
+	var body string
+	if m.weekView {
+		body = m.weekAgenda()
+	} else {
+		gridWidth := m.width * 2 / 3
+		body = lipgloss.JoinHorizontal(lipgloss.Top, m.monthGrid(gridWidth), m.dayAgenda(m.width-gridWidth-2))
+	}
+
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	var footer []string
+	if m.calendarErr != "" {
+		footer = append(footer, lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("Calendar file: "+m.calendarErr))
+	} else if m.calendarPath != "" {
+		footer = append(footer, dimStyle.Render(fmt.Sprintf("%d events from %s", len(m.events), m.calendarPath)))
+	}
+	footer = append(footer, dimStyle.Render("(h/l) day, (j/k) week, ([/]) month, (t)oday, (v) month/week, (tab) project/workspace, (J/K) select, (enter) open task, (p) .ics path"))
+
+	return lipgloss.JoinVertical(lipgloss.Left, title, "", body, strings.Join(footer, "\n"))
+}
+
+func (m *Calendar) monthGrid(width int) string {
+	today := startOfDay(time.Now())
+	cellWidth := width/7 - 1
+	if cellWidth < 6 {
+		cellWidth = 6
+	}
+	cellHeight := (m.height - 14) / 6
+	if cellHeight < 2 {
+		cellHeight = 2
+	}
+
+	headerStyle := lipgloss.NewStyle().Bold(true).Width(cellWidth + 1)
+	var header []string
+	for _, name := range []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"} {
+		header = append(header, headerStyle.Render(name))
+	}
+	rows := []string{lipgloss.JoinHorizontal(lipgloss.Top, header...)}
+
+	first := time.Date(m.selected.Year(), m.selected.Month(), 1, 0, 0, 0, 0, time.Local)
+	day := startOfWeek(first)
+	for week := 0; week < 6; week++ {
+		var cells []string
+		for i := 0; i < 7; i++ {
+			cells = append(cells, m.monthCell(day, today, cellWidth, cellHeight))
+			day = day.AddDate(0, 0, 1)
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, cells...))
+		if day.Month() != m.selected.Month() {
+			break
+		}
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
+func (m *Calendar) monthCell(day, today time.Time, width, height int) string {
+	numberStyle := lipgloss.NewStyle()
+	if day.Month() != m.selected.Month() {
+		numberStyle = numberStyle.Foreground(lipgloss.Color("240"))
+	}
+	if day.Equal(today) {
+		numberStyle = numberStyle.Bold(true).Underline(true)
+	}
+
+	lines := []string{numberStyle.Render(fmt.Sprint(day.Day()))}
+	items := m.itemsOn(day)
+	for i, item := range items {
+		if len(lines) == height-1 && len(items)-i > 1 {
+			lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(fmt.Sprintf("+%d more", len(items)-i)))
+			break
+		}
+		if len(lines) >= height {
+			break
+		}
+		lines = append(lines, m.itemStyle(item, today).Render(truncate(item.label(), width)))
+	}
+
+	cellStyle := lipgloss.NewStyle().Width(width).Height(height).MarginRight(1)
+	if day.Equal(m.selected) {
+		cellStyle = cellStyle.Background(lipgloss.Color("236"))
+	}
+	return cellStyle.Render(strings.Join(lines, "\n"))
+}
+
+// dayAgenda lists the selected day's items with the item cursor.
+func (m *Calendar) dayAgenda(width int) string {
+	today := startOfDay(time.Now())
+	lines := []string{lipgloss.NewStyle().Bold(true).Render(m.selected.Format("Monday, 2 January"))}
+	lines = append(lines, m.agendaLines(m.selected, today, width, true)...)
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("63")).
+		Padding(0, 1).
+		Width(width - 2).
+		Height(m.height - 14).
+		Render(strings.Join(lines, "\n"))
+}
+
+func (m *Calendar) agendaLines(day, today time.Time, width int, selectable bool) []string {
+	items := m.itemsOn(day)
+	if len(items) == 0 {
+		return []string{lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("Nothing scheduled")}
+	}
+
+	var lines []string
+	for i, item := range items {
+		text := item.label()
+		if item.task != nil && m.workspaceScope {
+			text += " · " + item.task.ProjectName
+		}
+		if item.event != nil && item.event.Location != "" {
+			text += " @ " + item.event.Location
+		}
+		style := m.itemStyle(item, today)
+		if selectable && i == m.itemCursor {
+			style = style.Background(lipgloss.Color("57"))
+		}
+		lines = append(lines, style.Render(truncate(text, width-4)))
+	}
+	return lines
+}
+
+// weekAgenda shows the seven days of the selected week side by side.
+func (m *Calendar) weekAgenda() string {
+	today := startOfDay(time.Now())
+	columnWidth := m.width/7 - 1
+	if columnWidth < 8 {
+		columnWidth = 8
+	}
+
+	var columns []string
+	day := startOfWeek(m.selected)
+	for i := 0; i < 7; i++ {
+		headerStyle := lipgloss.NewStyle().Bold(true)
+		if day.Equal(today) {
+			headerStyle = headerStyle.Underline(true)
+		}
+		selected := day.Equal(m.selected)
+		lines := []string{headerStyle.Render(day.Format("Mon 2 Jan"))}
+		lines = append(lines, m.agendaLines(day, today, columnWidth+2, selected)...)
+
+		style := lipgloss.NewStyle().Width(columnWidth).Height(m.height - 12).MarginRight(1)
+		if selected {
+			style = style.Background(lipgloss.Color("236"))
+		}
+		columns = append(columns, style.Render(strings.Join(lines, "\n")))
+		day = day.AddDate(0, 0, 1)
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, columns...)
+}
+
+// truncate shortens s to at most width cells.
+func truncate(s string, width int) string {
+	runes := []rune(s)
+	if width <= 0 {
+		return ""
+	}
+	if len(runes) <= width {
+		return s
+	}
+	return string(runes[:width-1]) + "…"
+}
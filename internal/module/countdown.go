@@ -0,0 +1,354 @@
+package module
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Ceinl/Go-dashboard/internal/storage"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/google/uuid"
+)
+
+// countdownFields are the steps of the add/edit form.
+var countdownFields = []string{
+	"Name, e.g. Demo day",
+	"Target date, YYYY-MM-DD or YYYY-MM-DD HH:MM",
+}
+
+// countdownLayouts are the accepted target formats, in local time. A date
+// alone means the start of that day.
+var countdownLayouts = []string{"2006-01-02 15:04", storage.DateLayout}
+
+// countdownWarning is how close a milestone has to be to show up in the
+// status bar.
+const countdownWarning = 24 * time.Hour
+
+// Urgency colors, from overdue to far away.
+var (
+	countdownPassedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	countdownDueStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Bold(true)
+	countdownSoonStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	countdownWeekStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("226"))
+	countdownLaterStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+)
+
+// countdownTickMsg refreshes the time remaining.
+type countdownTickMsg struct {
+	owner *Countdown
+}
+
+// Countdown lists a project's milestones with the time left until each,
+// colored by urgency. Milestones due within a day are also shown in the
+// status bar.
+type Countdown struct {
+	db         *sql.DB
+	projectID  string
+	now        func() time.Time
+	milestones []storage.Milestone
+	cursor     int
+
+	form       bool
+	formStep   int
+	formValues []string
+	editingID  string // milestone being edited, empty when adding
+	input      textinput.Model
+	message    string
+
+	width  int
+	height int
+}
+
+func NewCountdown(db *sql.DB, projectID string) Module {
+	ti := textinput.New()
+	ti.CharLimit = 100
+	ti.Width = 50
+
+	return &Countdown{
+		db:        db,
+		projectID: projectID,
+		now:       time.Now,
+		input:     ti,
+	}
+}
+
+func (m *Countdown) Init() tea.Cmd {
+	m.loadMilestones()
+	return m.tick()
+}
+
+func (m *Countdown) tick() tea.Cmd {
+	return tea.Tick(time.Minute, func(time.Time) tea.Msg {
+		return countdownTickMsg{owner: m}
+	})
+}
+
+func (m *Countdown) loadMilestones() {
+	milestones, err := storage.GetMilestonesForProject(m.db, m.projectID)
+	if err != nil {
+		log.Printf("Error loading milestones: %v", err)
+		return
+	}
+	m.milestones = milestones
+	if m.cursor >= len(m.milestones) {
+		m.cursor = max(len(m.milestones)-1, 0)
+	}
+}
+
+func (m *Countdown) selectedMilestone() (storage.Milestone, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.milestones) {
+		return storage.Milestone{}, false
+	}
+	return m.milestones[m.cursor], true
+}
+
+// CapturingInput reports whether the module needs every key press.
+func (m *Countdown) CapturingInput() bool {
+	return m.form
+}
+
+// StatusText warns about the milestones due within a day, soonest first.
+func (m *Countdown) StatusText() string {
+	now := m.now()
+	var due []storage.Milestone
+	for _, milestone := range m.milestones {
+		if left := milestone.Target.Sub(now); left > 0 && left <= countdownWarning {
+			due = append(due, milestone)
+		}
+	}
+	if len(due) == 0 {
+		return ""
+	}
+	text := fmt.Sprintf("⚠ %s in %s", due[0].Name, formatRemaining(due[0].Target.Sub(now)))
+	if len(due) > 1 {
+		text += fmt.Sprintf(" (+%d)", len(due)-1)
+	}
+	return text
+}
+
+func (m *Countdown) Update(msg tea.Msg) (Module, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+	case countdownTickMsg:
+		if msg.owner != m {
+			return m, nil
+		}
+		return m, m.tick()
+	case tea.KeyMsg:
+		if m.form {
+			return m.updateForm(msg)
+		}
+		return m.updateBrowsing(msg)
+	}
+	return m, nil
+}
+
+func (m *Countdown) updateBrowsing(msg tea.KeyMsg) (Module, tea.Cmd) {
+	m.message = ""
+	switch msg.String() {
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.milestones)-1 {
+			m.cursor++
+		}
+	case "a":
+		return m, m.startForm(storage.Milestone{})
+	case "e", "enter":
+		if milestone, ok := m.selectedMilestone(); ok {
+			return m, m.startForm(milestone)
+		}
+	case "d":
+		if milestone, ok := m.selectedMilestone(); ok {
+			if err := storage.DeleteMilestone(m.db, milestone.ID); err != nil {
+				log.Printf("Error deleting milestone: %v", err)
+			}
+			m.loadMilestones()
+		}
+	}
+	return m, nil
+}
+
+func (m *Countdown) startForm(milestone storage.Milestone) tea.Cmd {
+	m.form = true
+	m.formStep = 0
+	m.editingID = milestone.ID
+	target := ""
+	if !milestone.Target.IsZero() {
+		target = formatTarget(milestone.Target)
+	}
+	m.formValues = []string{milestone.Name, target}
+	return m.showFormStep()
+}
+
+func (m *Countdown) showFormStep() tea.Cmd {
+	m.input.Placeholder = countdownFields[m.formStep]
+	m.input.SetValue(m.formValues[m.formStep])
+	m.input.CursorEnd()
+	return m.input.Focus()
+}
+
+func (m *Countdown) updateForm(msg tea.KeyMsg) (Module, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.form = false
+		m.input.Blur()
+		return m, nil
+	case tea.KeyEnter:
+		value := strings.TrimSpace(m.input.Value())
+		if value == "" {
+			return m, nil // both fields are required
+		}
+		m.formValues[m.formStep] = value
+		if m.formStep < len(countdownFields)-1 {
+			m.formStep++
+			return m, m.showFormStep()
+		}
+		target, err := parseTarget(value)
+		if err != nil {
+			m.message = err.Error()
+			return m, nil
+		}
+		m.form = false
+		m.message = ""
+		m.input.Blur()
+		m.saveForm(target)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m *Countdown) saveForm(target time.Time) {
+	milestone := storage.Milestone{
+		ID:        m.editingID,
+		ProjectID: m.projectID,
+		Name:      m.formValues[0],
+		Target:    target,
+	}
+
+	var err error
+	if milestone.ID == "" {
+		milestone.ID = uuid.New().String()
+		err = storage.CreateMilestone(m.db, milestone)
+	} else {
+		err = storage.UpdateMilestone(m.db, milestone)
+	}
+	if err != nil {
+		log.Printf("Error saving milestone: %v", err)
+		return
+	}
+
+	m.loadMilestones()
+	for i, ms := range m.milestones {
+		if ms.ID == milestone.ID {
+			m.cursor = i
+		}
+	}
+}
+
+// parseTarget reads a target date typed in the form, in local time.
+func parseTarget(value string) (time.Time, error) {
+	for _, layout := range countdownLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("can't read %q as a date, use YYYY-MM-DD or YYYY-MM-DD HH:MM", value)
+}
+
+// formatTarget is the inverse of parseTarget, leaving out midnight.
+func formatTarget(t time.Time) string {
+	t = t.Local()
+	if t.Equal(startOfDay(t)) {
+		return t.Format(storage.DateLayout)
+	}
+	return t.Format(countdownLayouts[0])
+}
+
+// formatRemaining describes a positive duration in its two largest units,
+// e.g. "3d 4h", "5h 12m" or "12m".
+func formatRemaining(d time.Duration) string {
+	d = d.Round(time.Minute)
+	days := int(d / (24 * time.Hour))
+	hours := int(d/time.Hour) % 24
+	minutes := int(d/time.Minute) % 60
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	}
+	return fmt.Sprintf("%dm", minutes)
+}
+
+// urgencyStyle colors a milestone by the time left until it.
+func urgencyStyle(left time.Duration) lipgloss.Style {
+	switch {
+	case left <= 0:
+		return countdownPassedStyle
+	case left <= countdownWarning:
+		return countdownDueStyle
+	case left <= 3*24*time.Hour:
+		return countdownSoonStyle
+	case left <= 7*24*time.Hour:
+		return countdownWeekStyle
+	}
+	return countdownLaterStyle
+}
+
+func (m *Countdown) View() string {
+	if m.width == 0 {
+		return "loading..."
+	}
+	if m.form {
+		step := gitDimStyle.Render(fmt.Sprintf("Step %d of %d", m.formStep+1, len(countdownFields)))
+		lines := []string{countdownFields[m.formStep], m.input.View(), step}
+		if m.message != "" {
+			lines = append(lines, gitErrorStyle.Render(m.message))
+		}
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center,
+			lipgloss.JoinVertical(lipgloss.Left, lines...))
+	}
+
+	var s strings.Builder
+	s.WriteString("Countdown\n\n")
+
+	now := m.now()
+	nameWidth := max(m.width/3, 20)
+	for i, milestone := range m.milestones {
+		cursor := " "
+		if i == m.cursor {
+			cursor = ">"
+		}
+		left := milestone.Target.Sub(now)
+		remaining := "passed " + formatRemaining(-left) + " ago"
+		if left > 0 {
+			remaining = formatRemaining(left)
+		}
+		line := fmt.Sprintf("%s %-*s %-16s", cursor, nameWidth, truncate(milestone.Name, nameWidth), remaining)
+		s.WriteString(urgencyStyle(left).Render(line) + " " +
+			gitDimStyle.Render(milestone.Target.Local().Format("Mon Jan 2 2006 15:04")) + "\n")
+	}
+	if len(m.milestones) == 0 {
+		s.WriteString(gitDimStyle.Render("No milestones. Press a to add one.") + "\n")
+	}
+
+	footer := "(a)dd, (e)dit, (d)elete"
+	if m.message != "" {
+		footer = m.message
+	}
+	s.WriteString("\n" + gitDimStyle.Render(footer))
+	return s.String()
+}
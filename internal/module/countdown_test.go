@@ -0,0 +1,67 @@
+package module
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ceinl/Go-dashboard/internal/storage"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestFormatRemaining(t *testing.T) {
+	for _, tt := range []struct {
+		d    time.Duration
+		want string
+	}{
+		{3*24*time.Hour + 4*time.Hour + 20*time.Minute, "3d 4h"},
+		{5*time.Hour + 12*time.Minute, "5h 12m"},
+		{12*time.Minute + 40*time.Second, "13m"},
+	} {
+		if got := formatRemaining(tt.d); got != tt.want {
+			t.Errorf("formatRemaining(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestCountdownWarnsInStatusBar(t *testing.T) {
+	db, projectID := setupTestDB(t)
+	now := time.Date(2024, 3, 14, 12, 0, 0, 0, time.Local)
+
+	m := NewCountdown(db, projectID)
+	countdown := m.(*Countdown)
+	countdown.now = func() time.Time { return now }
+	m.Init()
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	m = typeKeys(m, "a", "D", "e", "m", "o", "enter", "s", "o", "o", "n", "enter")
+	if !countdown.form || !strings.Contains(countdown.message, "can't read") {
+		t.Fatalf("expected an invalid date to keep the form open, got %q", countdown.message)
+	}
+	countdown.input.SetValue("2024-03-15 09:30")
+	m = typeKeys(m, "enter")
+	if countdown.form || len(countdown.milestones) != 1 {
+		t.Fatalf("expected the milestone to be saved, got %+v", countdown.milestones)
+	}
+	if got := countdown.StatusText(); got != "⚠ Demo in 21h 30m" {
+		t.Errorf("unexpected status text %q", got)
+	}
+
+	storage.CreateMilestone(db, storage.Milestone{ID: "launch", ProjectID: projectID, Name: "Launch", Target: now.AddDate(0, 0, 10)})
+	storage.CreateMilestone(db, storage.Milestone{ID: "kickoff", ProjectID: projectID, Name: "Kickoff", Target: now.AddDate(0, 0, -2)})
+	countdown.loadMilestones()
+	view := ansi.Strip(m.View())
+	for _, want := range []string{"passed 2d 0h ago", "21h 30m", "10d 0h"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in the view, got:\n%s", want, view)
+		}
+	}
+	if got := countdown.StatusText(); got != "⚠ Demo in 21h 30m" {
+		t.Errorf("expected only the milestone due within a day in the status bar, got %q", got)
+	}
+	if urgencyStyle(-time.Hour).GetForeground() != countdownPassedStyle.GetForeground() ||
+		urgencyStyle(2*24*time.Hour).GetForeground() != countdownSoonStyle.GetForeground() {
+		t.Error("unexpected urgency colors")
+	}
+}
@@ -0,0 +1,48 @@
+package module
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// editExternally writes content to a temporary file, named after pattern as
+// in os.CreateTemp, and suspends the dashboard while $EDITOR (or vi) edits
+// it. Once the editor exits the file is read back and removed, and done
+// turns the edited content, or what went wrong, into the message to send.
+func editExternally(content, pattern string, done func(edited string, err error) tea.Msg) tea.Cmd {
+	file, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return func() tea.Msg { return done("", fmt.Errorf("creating temp file: %w", err)) }
+	}
+	path := file.Name()
+	_, err = file.WriteString(content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return func() tea.Msg { return done("", fmt.Errorf("writing temp file: %w", err)) }
+	}
+
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+	// $EDITOR may carry arguments, e.g. "code --wait".
+	args := append(strings.Fields(editor), path)
+	return tea.ExecProcess(exec.Command(args[0], args[1:]...), func(err error) tea.Msg {
+		defer os.Remove(path)
+		if err != nil {
+			return done("", fmt.Errorf("running editor: %w", err))
+		}
+		edited, err := os.ReadFile(path)
+		if err != nil {
+			return done("", fmt.Errorf("reading edited file: %w", err))
+		}
+		return done(string(edited), nil)
+	})
+}
@@ -0,0 +1,282 @@
+package module
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Ceinl/Go-dashboard/internal/storage"
+)
+
+// feedMaxBytes limits how much of a feed is read.
+const feedMaxBytes = 10 << 20
+
+var feedClient = &http.Client{Timeout: 20 * time.Second}
+
+type parsedFeed struct {
+	Title string
+	Items []parsedItem
+}
+
+type parsedItem struct {
+	GUID      string
+	Title     string
+	Link      string
+	Published time.Time
+}
+
+type rssDocument struct {
+	Channel struct {
+		Title string `xml:"title"`
+		Items []struct {
+			Title   string `xml:"title"`
+			Link    string `xml:"link"`
+			GUID    string `xml:"guid"`
+			PubDate string `xml:"pubDate"`
+			Date    string `xml:"http://purl.org/dc/elements/1.1/ date"`
+		} `xml:"item"`
+	} `xml:"channel"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+}
+
+type atomDocument struct {
+	Title   string `xml:"title"`
+	Entries []struct {
+		ID        string     `xml:"id"`
+		Title     string     `xml:"title"`
+		Links     []atomLink `xml:"link"`
+		Published string     `xml:"published"`
+		Updated   string     `xml:"updated"`
+	} `xml:"entry"`
+}
+
+// feedDateLayouts are the date formats seen in the wild: RFC 822 variants
+// in RSS and RFC 3339 in Atom and Dublin Core.
+var feedDateLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	time.RFC822Z,
+	time.RFC822,
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+func parseFeedDate(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range feedDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// latin1Reader decodes ISO-8859-1, the only legacy charset common enough in
+// feeds to be worth supporting.
+func latin1Reader(charset string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(charset) {
+	case "iso-8859-1", "latin1", "latin-1", "us-ascii":
+		data, err := io.ReadAll(input)
+		if err != nil {
+			return nil, err
+		}
+		var b bytes.Buffer
+		for _, c := range data {
+			b.WriteRune(rune(c))
+		}
+		return &b, nil
+	}
+	return nil, fmt.Errorf("unsupported charset %q", charset)
+}
+
+func newFeedDecoder(data []byte) *xml.Decoder {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.CharsetReader = latin1Reader
+	decoder.Strict = false
+	return decoder
+}
+
+// parseFeed reads an RSS 2.0 or Atom document.
+func parseFeed(data []byte) (parsedFeed, error) {
+	var root string
+	decoder := newFeedDecoder(data)
+	for root == "" {
+		token, err := decoder.Token()
+		if err != nil {
+			return parsedFeed{}, fmt.Errorf("not a feed: %w", err)
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			root = start.Name.Local
+		}
+	}
+
+	switch root {
+	case "rss":
+		var doc rssDocument
+		if err := newFeedDecoder(data).Decode(&doc); err != nil {
+			return parsedFeed{}, err
+		}
+		feed := parsedFeed{Title: cleanFeedText(doc.Channel.Title)}
+		for _, item := range doc.Channel.Items {
+			published := parseFeedDate(item.PubDate)
+			if published.IsZero() {
+				published = parseFeedDate(item.Date)
+			}
+			feed.Items = append(feed.Items, newParsedItem(item.GUID, item.Title, item.Link, published))
+		}
+		return feed, nil
+	case "feed":
+		var doc atomDocument
+		if err := newFeedDecoder(data).Decode(&doc); err != nil {
+			return parsedFeed{}, err
+		}
+		feed := parsedFeed{Title: cleanFeedText(doc.Title)}
+		for _, entry := range doc.Entries {
+			link := ""
+			for _, l := range entry.Links {
+				if l.Rel == "" || l.Rel == "alternate" {
+					link = l.Href
+					break
+				}
+			}
+			published := parseFeedDate(entry.Published)
+			if published.IsZero() {
+				published = parseFeedDate(entry.Updated)
+			}
+			feed.Items = append(feed.Items, newParsedItem(entry.ID, entry.Title, link, published))
+		}
+		return feed, nil
+	}
+	return parsedFeed{}, fmt.Errorf("not an RSS or Atom feed: <%s>", root)
+}
+
+// newParsedItem falls back to the link, then the title and date, for items
+// without an ID.
+func newParsedItem(guid, title, link string, published time.Time) parsedItem {
+	item := parsedItem{
+		GUID:      strings.TrimSpace(guid),
+		Title:     cleanFeedText(title),
+		Link:      strings.TrimSpace(link),
+		Published: published,
+	}
+	if item.GUID == "" {
+		item.GUID = item.Link
+	}
+	if item.GUID == "" {
+		item.GUID = item.Title + "|" + published.Format(time.RFC3339)
+	}
+	if item.Title == "" {
+		item.Title = "(untitled)"
+	}
+	return item
+}
+
+// cleanFeedText collapses the whitespace of a title.
+func cleanFeedText(s string) string {
+	if !utf8.ValidString(s) {
+		s = strings.ToValidUTF8(s, "?")
+	}
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// feedFetch is the result of fetching one feed. When the source hasn't
+// changed since the last fetch, NotModified is set and Parsed is empty.
+type feedFetch struct {
+	Feed        storage.Feed // with updated validators
+	Parsed      parsedFeed
+	NotModified bool
+	Err         error
+}
+
+func isURL(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+// fetchFeed reads a feed from its URL or file. URLs are requested
+// conditionally with the ETag and Last-Modified of the previous response;
+// files are only parsed again when their modification time changed.
+func fetchFeed(client *http.Client, feed storage.Feed, now time.Time) feedFetch {
+	result := feedFetch{Feed: feed}
+	result.Feed.FetchedAt = now
+
+	var data []byte
+	if isURL(feed.Source) {
+		req, err := http.NewRequest(http.MethodGet, feed.Source, nil)
+		if err != nil {
+			result.Err = err
+			return result
+		}
+		req.Header.Set("User-Agent", "Go-dashboard feed reader")
+		if feed.ETag != "" {
+			req.Header.Set("If-None-Match", feed.ETag)
+		}
+		if feed.LastModified != "" {
+			req.Header.Set("If-Modified-Since", feed.LastModified)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			result.Err = err
+			return result
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode == http.StatusNotModified {
+			result.NotModified = true
+			return result
+		}
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			result.Err = errors.New(resp.Status)
+			return result
+		}
+		if data, err = io.ReadAll(io.LimitReader(resp.Body, feedMaxBytes)); err != nil {
+			result.Err = err
+			return result
+		}
+		result.Feed.ETag = resp.Header.Get("ETag")
+		result.Feed.LastModified = resp.Header.Get("Last-Modified")
+	} else {
+		path := expandHome(feed.Source)
+		info, err := os.Stat(path)
+		if err != nil {
+			result.Err = err
+			return result
+		}
+		modified := info.ModTime().UTC().Format(time.RFC3339Nano)
+		if modified == feed.LastModified {
+			result.NotModified = true
+			return result
+		}
+		if data, err = os.ReadFile(path); err != nil {
+			result.Err = err
+			return result
+		}
+		result.Feed.ETag = ""
+		result.Feed.LastModified = modified
+	}
+
+	parsed, err := parseFeed(data)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Parsed = parsed
+	if parsed.Title != "" {
+		result.Feed.Title = parsed.Title
+	}
+	return result
+}
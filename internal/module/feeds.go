@@ -0,0 +1,432 @@
+package module
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/Ceinl/Go-dashboard/internal/storage"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/google/uuid"
+)
+
+const feedRefreshInterval = 15 * time.Minute
+
+// feedsFetchedMsg carries the results of fetching every feed of a project.
+type feedsFetchedMsg struct {
+	owner      *Feeds
+	generation int
+	results    []feedFetch
+}
+
+type feedsRefreshMsg struct {
+	owner      *Feeds
+	generation int
+}
+
+// Feeds reads RSS and Atom feeds. Items are cached in the database with
+// their read state, so the module works offline and only fetches in the
+// background.
+type Feeds struct {
+	db        *sql.DB
+	projectID string
+	feeds     []storage.Feed
+	unread    map[string]int
+	errors    map[string]error // by feed ID, from the last fetch
+	items     []storage.FeedItem
+	cursor    int // selected feed
+	itemRow   int
+	onItems   bool // the item list has focus
+	loading   bool
+	notice    string
+
+	generation int
+
+	adding bool
+	input  textinput.Model
+
+	width  int
+	height int
+}
+
+func NewFeeds(db *sql.DB, projectID string) Module {
+	ti := textinput.New()
+	ti.Placeholder = "Feed URL or path to a feed file"
+	ti.CharLimit = 512
+	ti.Width = 60
+
+	return &Feeds{
+		db:        db,
+		projectID: projectID,
+		unread:    make(map[string]int),
+		errors:    make(map[string]error),
+		input:     ti,
+	}
+}
+
+func (m *Feeds) Init() tea.Cmd {
+	m.loadFeeds()
+	return m.refresh()
+}
+
+func (m *Feeds) loadFeeds() {
+	feeds, err := storage.GetFeedsForProject(m.db, m.projectID)
+	if err != nil {
+		log.Printf("Error loading feeds: %v", err)
+	}
+	m.feeds = feeds
+	if m.cursor >= len(m.feeds) {
+		m.cursor = max(len(m.feeds)-1, 0)
+	}
+
+	unread, err := storage.GetUnreadCounts(m.db, m.projectID)
+	if err != nil {
+		log.Printf("Error counting unread feed items: %v", err)
+	}
+	m.unread = unread
+	m.loadItems()
+}
+
+func (m *Feeds) loadItems() {
+	m.items = nil
+	if feed, ok := m.selectedFeed(); ok {
+		items, err := storage.GetFeedItems(m.db, feed.ID)
+		if err != nil {
+			log.Printf("Error loading feed items: %v", err)
+		}
+		m.items = items
+	}
+	if m.itemRow >= len(m.items) {
+		m.itemRow = max(len(m.items)-1, 0)
+	}
+}
+
+func (m *Feeds) selectedFeed() (storage.Feed, bool) {
+	if m.cursor < len(m.feeds) {
+		return m.feeds[m.cursor], true
+	}
+	return storage.Feed{}, false
+}
+
+func (m *Feeds) selectedItem() (storage.FeedItem, bool) {
+	if m.itemRow < len(m.items) {
+		return m.items[m.itemRow], true
+	}
+	return storage.FeedItem{}, false
+}
+
+// refresh fetches every feed in the background.
+func (m *Feeds) refresh() tea.Cmd {
+	m.generation++
+	m.loading = len(m.feeds) > 0
+	owner, generation := m, m.generation
+	feeds := append([]storage.Feed(nil), m.feeds...)
+	return func() tea.Msg {
+		results := make([]feedFetch, len(feeds))
+		for i, feed := range feeds {
+			results[i] = fetchFeed(feedClient, feed, time.Now())
+		}
+		return feedsFetchedMsg{owner: owner, generation: generation, results: results}
+	}
+}
+
+// saveFetches stores fetched items and cache validators.
+func (m *Feeds) saveFetches(results []feedFetch) {
+	m.errors = make(map[string]error)
+	for _, result := range results {
+		if result.Err != nil {
+			m.errors[result.Feed.ID] = result.Err
+			continue
+		}
+		if !result.NotModified {
+			items := make([]storage.FeedItem, 0, len(result.Parsed.Items))
+			for _, item := range result.Parsed.Items {
+				items = append(items, storage.FeedItem{
+					ID:          uuid.New().String(),
+					GUID:        item.GUID,
+					Title:       item.Title,
+					Link:        item.Link,
+					PublishedAt: item.Published,
+				})
+			}
+			if err := storage.SaveFeedItems(m.db, result.Feed.ID, items); err != nil {
+				log.Printf("Error saving feed items: %v", err)
+			}
+		}
+		if err := storage.UpdateFeedFetch(m.db, result.Feed); err != nil {
+			log.Printf("Error saving feed: %v", err)
+		}
+	}
+	m.loadFeeds()
+}
+
+// CapturingInput reports whether the module needs every key press.
+func (m *Feeds) CapturingInput() bool {
+	return m.adding
+}
+
+func (m *Feeds) Update(msg tea.Msg) (Module, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	case feedsFetchedMsg:
+		if msg.owner != m || msg.generation != m.generation {
+			return m, nil
+		}
+		m.loading = false
+		m.saveFetches(msg.results)
+		return m, tea.Tick(feedRefreshInterval, func(time.Time) tea.Msg {
+			return feedsRefreshMsg{owner: msg.owner, generation: msg.generation}
+		})
+	case feedsRefreshMsg:
+		if msg.owner == m && msg.generation == m.generation {
+			return m, m.refresh()
+		}
+	case tea.KeyMsg:
+		if m.adding {
+			return m.updateAdding(msg)
+		}
+		m.notice = ""
+		return m.updateKeys(msg)
+	}
+	return m, nil
+}
+
+func (m *Feeds) updateKeys(msg tea.KeyMsg) (Module, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if m.onItems {
+			if m.itemRow > 0 {
+				m.itemRow--
+			}
+		} else if m.cursor > 0 {
+			m.cursor--
+			m.itemRow = 0
+			m.loadItems()
+		}
+	case "down", "j":
+		if m.onItems {
+			if m.itemRow < len(m.items)-1 {
+				m.itemRow++
+			}
+		} else if m.cursor < len(m.feeds)-1 {
+			m.cursor++
+			m.itemRow = 0
+			m.loadItems()
+		}
+	case "tab", "right", "l", "left", "h":
+		m.onItems = !m.onItems && len(m.feeds) > 0
+	case "enter":
+		if !m.onItems {
+			m.onItems = len(m.feeds) > 0
+			return m, nil
+		}
+		if item, ok := m.selectedItem(); ok {
+			if item.Link != "" {
+				exec.Command("open", item.Link).Start()
+			}
+			m.setRead(item, true)
+		}
+	case "m":
+		if item, ok := m.selectedItem(); ok && m.onItems {
+			m.setRead(item, !item.Read)
+		}
+	case "A":
+		if feed, ok := m.selectedFeed(); ok {
+			if err := storage.MarkFeedRead(m.db, feed.ID); err != nil {
+				log.Printf("Error marking feed read: %v", err)
+			}
+			m.loadFeeds()
+		}
+	case "s":
+		if item, ok := m.selectedItem(); ok && m.onItems {
+			m.saveToLinks(item)
+		}
+	case "a":
+		m.adding = true
+		m.input.SetValue("")
+		return m, m.input.Focus()
+	case "d":
+		if feed, ok := m.selectedFeed(); ok && !m.onItems {
+			if err := storage.DeleteFeed(m.db, feed.ID); err != nil {
+				log.Printf("Error deleting feed: %v", err)
+			}
+			m.loadFeeds()
+		}
+	case "r":
+		return m, m.refresh()
+	}
+	return m, nil
+}
+
+func (m *Feeds) setRead(item storage.FeedItem, read bool) {
+	if err := storage.SetFeedItemRead(m.db, item.ID, read); err != nil {
+		log.Printf("Error marking feed item: %v", err)
+		return
+	}
+	m.loadFeeds()
+}
+
+// saveToLinks adds an item to the project's LinkSaver.
+func (m *Feeds) saveToLinks(item storage.FeedItem) {
+	if item.Link == "" {
+		m.notice = "This item has no link."
+		return
+	}
+	link := storage.Link{ID: uuid.New().String(), ProjectID: m.projectID, Title: item.Title, URL: item.Link}
+	if err := storage.CreateLink(m.db, link); err != nil {
+		log.Printf("Error saving link: %v", err)
+		return
+	}
+	m.notice = "Saved to LinkSaver: " + item.Title
+}
+
+func (m *Feeds) updateAdding(msg tea.KeyMsg) (Module, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.adding = false
+		m.input.Blur()
+		source := strings.TrimSpace(m.input.Value())
+		if source == "" {
+			return m, nil
+		}
+		feed := storage.Feed{ID: uuid.New().String(), ProjectID: m.projectID, Source: source}
+		if err := storage.CreateFeed(m.db, feed); err != nil {
+			log.Printf("Error adding feed: %v", err)
+			return m, nil
+		}
+		m.loadFeeds()
+		m.cursor = len(m.feeds) - 1
+		m.loadItems()
+		return m, m.refresh()
+	case tea.KeyEsc:
+		m.adding = false
+		m.input.Blur()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+var (
+	feedUnreadStyle = lipgloss.NewStyle().Bold(true)
+	feedReadStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
+	feedDimStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	feedErrorStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+)
+
+func feedName(feed storage.Feed) string {
+	if feed.Title != "" {
+		return feed.Title
+	}
+	return feed.Source
+}
+
+func (m *Feeds) View() string {
+	if m.width == 0 {
+		return "loading..."
+	}
+	if m.adding {
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.input.View())
+	}
+
+	listWidth := m.width / 3
+	paneHeight := m.height - 10
+	highlight := func(selected, focused bool) lipgloss.Style {
+		style := lipgloss.NewStyle()
+		if selected && focused {
+			style = style.Background(lipgloss.Color("57"))
+		} else if selected {
+			style = style.Background(lipgloss.Color("237"))
+		}
+		return style
+	}
+
+	var list []string
+	for i, feed := range m.feeds {
+		name := feedName(feed)
+		badge := ""
+		if n := m.unread[feed.ID]; n > 0 {
+			badge = fmt.Sprintf(" (%d)", n)
+		}
+		if m.errors[feed.ID] != nil {
+			badge += " ⚠"
+		}
+		style := highlight(i == m.cursor, !m.onItems).Width(listWidth - 4)
+		if m.unread[feed.ID] > 0 {
+			style = style.Bold(true)
+		}
+		list = append(list, style.Render(truncate(name, listWidth-4-lipgloss.Width(badge))+badge))
+	}
+	if len(m.feeds) == 0 {
+		list = append(list, feedDimStyle.Render("No feeds. Press a to add one."))
+	}
+
+	itemWidth := m.width - listWidth - 8
+	var detail []string
+	if feed, ok := m.selectedFeed(); ok {
+		detail = append(detail, lipgloss.NewStyle().Bold(true).Render(truncate(feedName(feed), itemWidth)))
+		if err := m.errors[feed.ID]; err != nil {
+			detail = append(detail, feedErrorStyle.Render(truncate(err.Error(), itemWidth)))
+		} else if !feed.FetchedAt.IsZero() {
+			detail = append(detail, feedDimStyle.Render("fetched "+feed.FetchedAt.Local().Format("Jan 2 15:04")))
+		}
+		detail = append(detail, "")
+
+		// Keep the selected item on screen.
+		rows := max(paneHeight-3, 1)
+		start := max(m.itemRow-rows+1, 0)
+		for i := start; i < len(m.items) && i < start+rows; i++ {
+			item := m.items[i]
+			date := ""
+			if !item.PublishedAt.IsZero() {
+				date = item.PublishedAt.Local().Format("Jan 02") + " "
+			}
+			style := feedReadStyle
+			marker := "  "
+			if !item.Read {
+				style = feedUnreadStyle
+				marker = "● "
+			}
+			line := marker + feedDimStyle.Render(date) + style.Render(truncate(item.Title, itemWidth-len(marker)-len(date)))
+			detail = append(detail, highlight(i == m.itemRow, m.onItems).Width(itemWidth).Render(line))
+		}
+		if len(m.items) == 0 {
+			detail = append(detail, feedDimStyle.Render("No items yet."))
+		}
+	}
+
+	listPane := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		Padding(0, 1).
+		Width(listWidth - 2).
+		Height(paneHeight).
+		Render(strings.Join(list, "\n"))
+	detailPane := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("63")).
+		Padding(0, 1).
+		Width(m.width - listWidth - 4).
+		Height(paneHeight).
+		Render(strings.Join(detail, "\n"))
+
+	footer := "(a)dd feed, (d)elete, (tab) switch pane, (enter) open, (m)ark read/unread, (A)ll read, (s)ave to LinkSaver, (r)efresh"
+	if m.loading {
+		footer = "fetching...  " + footer
+	}
+	if m.notice != "" {
+		footer = m.notice + "  " + footer
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.JoinHorizontal(lipgloss.Top, listPane, detailPane),
+		feedDimStyle.Render(footer))
+}
@@ -0,0 +1,164 @@
+package module
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Ceinl/Go-dashboard/internal/storage"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/uuid"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	return data
+}
+
+func TestParseRSS(t *testing.T) {
+	feed, err := parseFeed(readFixture(t, "rss.xml"))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if feed.Title != "Example Engineering Blog" {
+		t.Errorf("unexpected title %q", feed.Title)
+	}
+	if len(feed.Items) != 3 {
+		t.Fatalf("expected 3 items, got %d", len(feed.Items))
+	}
+
+	first := feed.Items[0]
+	if first.GUID != "post-2" || first.Title != "Shipping the new & improved parser" || first.Link != "https://example.com/blog/parser" {
+		t.Errorf("unexpected first item %+v", first)
+	}
+	if !first.Published.Equal(time.Date(2024, 3, 5, 10, 30, 0, 0, time.UTC)) {
+		t.Errorf("unexpected pubDate %v", first.Published)
+	}
+	second := feed.Items[1]
+	if second.GUID != second.Link || second.Title != "Why we <3 SQLite" || !second.Published.Equal(time.Date(2024, 2, 20, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the link as GUID and the dc:date, got %+v", second)
+	}
+	if third := feed.Items[2]; third.GUID == "" || third.Link != "" || !third.Published.IsZero() {
+		t.Errorf("expected a fallback GUID, got %+v", third)
+	}
+}
+
+func TestParseAtom(t *testing.T) {
+	feed, err := parseFeed(readFixture(t, "atom.xml"))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if feed.Title != "Release notes" || len(feed.Items) != 2 {
+		t.Fatalf("unexpected feed %+v", feed)
+	}
+	first := feed.Items[0]
+	if first.GUID != "tag:example.org,2024:v2" || first.Link != "https://example.org/v2" {
+		t.Errorf("expected the alternate link, got %+v", first)
+	}
+	if !first.Published.Equal(time.Date(2024, 3, 1, 11, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the published date, got %v", first.Published)
+	}
+	if second := feed.Items[1]; !second.Published.Equal(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the updated date as fallback, got %v", second.Published)
+	}
+
+	if _, err := parseFeed([]byte("<html><body>nope</body></html>")); err == nil {
+		t.Error("expected an error for a non-feed document")
+	}
+}
+
+func TestFetchFeedUsesConditionalRequests(t *testing.T) {
+	data := readFixture(t, "atom.xml")
+	requests, notModified := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Fri, 01 Mar 2024 12:00:00 GMT")
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	feed := storage.Feed{ID: "feed", Source: server.URL}
+	now := time.Date(2024, 3, 3, 9, 0, 0, 0, time.UTC)
+	first := fetchFeed(server.Client(), feed, now)
+	if first.Err != nil || first.NotModified || len(first.Parsed.Items) != 2 {
+		t.Fatalf("unexpected first fetch %+v", first)
+	}
+	if first.Feed.ETag != `"v1"` || first.Feed.Title != "Release notes" || !first.Feed.FetchedAt.Equal(now) {
+		t.Errorf("expected the validators and title to be kept, got %+v", first.Feed)
+	}
+
+	second := fetchFeed(server.Client(), first.Feed, now.Add(time.Hour))
+	if second.Err != nil || !second.NotModified || notModified != 1 || requests != 2 {
+		t.Errorf("expected a 304 on the second fetch, got %+v after %d requests", second, requests)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "gone", http.StatusGone)
+	}))
+	defer failing.Close()
+	if result := fetchFeed(failing.Client(), storage.Feed{Source: failing.URL}, now); result.Err == nil {
+		t.Error("expected an error for a failing server")
+	}
+}
+
+func TestFeedsModule(t *testing.T) {
+	db, projectID := setupTestDB(t)
+	path := filepath.Join(t.TempDir(), "feed.xml")
+	if err := os.WriteFile(path, readFixture(t, "rss.xml"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.CreateFeed(db, storage.Feed{ID: uuid.New().String(), ProjectID: projectID, Source: path}); err != nil {
+		t.Fatalf("failed to add feed: %v", err)
+	}
+
+	m := NewFeeds(db, projectID)
+	cmd := m.Init()
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m, _ = m.Update(cmd())
+	feeds := m.(*Feeds)
+	if len(feeds.items) != 3 || feeds.unread[feeds.feeds[0].ID] != 3 {
+		t.Fatalf("expected 3 unread items, got %d items and %v", len(feeds.items), feeds.unread)
+	}
+	if feeds.feeds[0].Title != "Example Engineering Blog" {
+		t.Errorf("expected the feed title to be saved, got %q", feeds.feeds[0].Title)
+	}
+
+	// Mark the first item read, then fetch again: the unchanged file isn't
+	// parsed again and the read state is kept.
+	m = typeKeys(m, "l", "m")
+	if feeds.unread[feeds.feeds[0].ID] != 2 || !feeds.items[0].Read {
+		t.Errorf("expected the first item to be read, got %v", feeds.unread)
+	}
+	result := fetchFeed(feedClient, feeds.feeds[0], time.Now())
+	if !result.NotModified {
+		t.Error("expected an unchanged file not to be parsed again")
+	}
+	m, _ = m.Update(feeds.refresh()())
+	if len(feeds.items) != 3 || !feeds.items[0].Read {
+		t.Errorf("expected read state to survive a refresh, got %+v", feeds.items)
+	}
+
+	m = typeKeys(m, "j", "s")
+	links, err := storage.GetLinksForProject(db, projectID)
+	if err != nil || len(links) != 1 || links[0].URL != "https://example.com/blog/sqlite" || links[0].Title != "Why we <3 SQLite" {
+		t.Errorf("expected the item in LinkSaver, got %+v (%v)", links, err)
+	}
+
+	m = typeKeys(m, "A")
+	if feeds.unread[feeds.feeds[0].ID] != 0 {
+		t.Errorf("expected every item to be read, got %v", feeds.unread)
+	}
+}
@@ -0,0 +1,142 @@
+package module
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var matchStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("212")).Underline(true)
+
+// itemFilter is the inline "/" filter shared by the modules that render their
+// own item lists. It mirrors the behaviour of the filter in bubbles/list:
+// "/" starts typing, enter keeps the filter applied, esc clears it.
+type itemFilter struct {
+	input  textinput.Model
+	typing bool
+}
+
+func newItemFilter() itemFilter {
+	ti := textinput.New()
+	ti.Prompt = "/"
+	ti.CharLimit = 64
+	ti.Width = 30
+	return itemFilter{input: ti}
+}
+
+// Start focuses the filter input so that key presses refine the filter.
+func (f *itemFilter) Start() tea.Cmd {
+	f.typing = true
+	return f.input.Focus()
+}
+
+// Reset clears the filter and leaves typing mode.
+func (f *itemFilter) Reset() {
+	f.typing = false
+	f.input.Blur()
+	f.input.Reset()
+}
+
+// Typing reports whether the filter input currently has focus.
+func (f itemFilter) Typing() bool {
+	return f.typing
+}
+
+// Active reports whether a non-empty filter narrows the visible items.
+func (f itemFilter) Active() bool {
+	return strings.TrimSpace(f.input.Value()) != ""
+}
+
+// Update handles a key press while typing. The returned bool is true when
+// the filter value changed, so callers know to clamp their cursor.
+func (f *itemFilter) Update(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch msg.String() {
+	case "enter":
+		f.typing = false
+		f.input.Blur()
+		if !f.Active() {
+			f.input.Reset()
+		}
+		return nil, false
+	case "esc":
+		f.Reset()
+		return nil, true
+	}
+
+	previous := f.input.Value()
+	var cmd tea.Cmd
+	f.input, cmd = f.input.Update(msg)
+	return cmd, f.input.Value() != previous
+}
+
+// Matches reports whether any of the given fields contains every term of the
+// filter, ignoring case. An empty filter matches everything.
+func (f itemFilter) Matches(fields ...string) bool {
+	terms := strings.Fields(strings.ToLower(f.input.Value()))
+	if len(terms) == 0 {
+		return true
+	}
+	haystack := strings.ToLower(strings.Join(fields, "\n"))
+	for _, term := range terms {
+		if !strings.Contains(haystack, term) {
+			return false
+		}
+	}
+	return true
+}
+
+// Highlight renders text with base, underlining the parts that match the
+// filter terms.
+func (f itemFilter) Highlight(text string, base lipgloss.Style) string {
+	terms := strings.Fields(strings.ToLower(f.input.Value()))
+	if len(terms) == 0 || text == "" {
+		return base.Render(text)
+	}
+
+	// Mark every rune covered by a match, then render runs of marked and
+	// unmarked runes with the matching style.
+	runes := []rune(text)
+	lower := []rune(strings.ToLower(text))
+	if len(lower) != len(runes) {
+		// Lower-casing changed the rune count; skip highlighting rather than
+		// underline the wrong characters.
+		return base.Render(text)
+	}
+	marked := make([]bool, len(runes))
+	for _, term := range terms {
+		t := []rune(term)
+		for i := 0; i+len(t) <= len(lower); i++ {
+			if string(lower[i:i+len(t)]) == term {
+				for j := i; j < i+len(t); j++ {
+					marked[j] = true
+				}
+			}
+		}
+	}
+
+	highlight := matchStyle.Inherit(base)
+	var s strings.Builder
+	start := 0
+	for i := 1; i <= len(runes); i++ {
+		if i == len(runes) || marked[i] != marked[start] {
+			segment := string(runes[start:i])
+			if marked[start] {
+				s.WriteString(highlight.Render(segment))
+			} else {
+				s.WriteString(base.Render(segment))
+			}
+			start = i
+		}
+	}
+	return s.String()
+}
+
+// View renders the filter prompt, or nothing when no filter is set.
+func (f itemFilter) View() string {
+	if !f.typing && !f.Active() {
+		return ""
+	}
+	return f.input.View()
+}
@@ -0,0 +1,325 @@
+package module
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Ceinl/Go-dashboard/internal/storage"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/google/uuid"
+)
+
+const gitRefreshInterval = 30 * time.Second
+
+// gitStatusMsg carries freshly read repository statuses, keyed by repository
+// ID. Messages for another Git module or an older refresh are ignored.
+type gitStatusMsg struct {
+	owner      *Git
+	generation int
+	statuses   map[string]repoStatus
+}
+
+// gitRefreshMsg asks for the next periodic refresh.
+type gitRefreshMsg struct {
+	owner      *Git
+	generation int
+}
+
+// Git shows the status of the local repositories associated with a project,
+// refreshing every gitRefreshInterval.
+type Git struct {
+	db        *sql.DB
+	projectID string
+	repos     []storage.Repository
+	statuses  map[string]repoStatus
+	cursor    int
+	loading   bool
+	updatedAt time.Time
+
+	// generation is bumped on every refresh so that a manual refresh
+	// replaces the pending periodic one instead of adding a second loop.
+	generation int
+
+	adding bool
+	input  textinput.Model
+
+	width  int
+	height int
+}
+
+func NewGit(db *sql.DB, projectID string) Module {
+	ti := textinput.New()
+	ti.Placeholder = "Path to a local git repository"
+	ti.CharLimit = 512
+	ti.Width = 60
+
+	return &Git{
+		db:        db,
+		projectID: projectID,
+		statuses:  make(map[string]repoStatus),
+		input:     ti,
+	}
+}
+
+func (m *Git) Init() tea.Cmd {
+	m.loadRepos()
+	return m.refresh()
+}
+
+func (m *Git) loadRepos() {
+	repos, err := storage.GetRepositoriesForProject(m.db, m.projectID)
+	if err != nil {
+		log.Printf("Error loading repositories: %v", err)
+	}
+	m.repos = repos
+	if m.cursor >= len(m.repos) {
+		m.cursor = max(len(m.repos)-1, 0)
+	}
+}
+
+// refresh reads every repository in the background.
+func (m *Git) refresh() tea.Cmd {
+	m.generation++
+	m.loading = true
+	owner, generation := m, m.generation
+	repos := append([]storage.Repository(nil), m.repos...)
+	return func() tea.Msg {
+		statuses := make(map[string]repoStatus, len(repos))
+		for _, repo := range repos {
+			statuses[repo.ID] = readRepoStatus(repo.Path)
+		}
+		return gitStatusMsg{owner: owner, generation: generation, statuses: statuses}
+	}
+}
+
+// CapturingInput reports whether the module needs every key press.
+func (m *Git) CapturingInput() bool {
+	return m.adding
+}
+
+func (m *Git) Update(msg tea.Msg) (Module, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	case gitStatusMsg:
+		if msg.owner != m || msg.generation != m.generation {
+			return m, nil
+		}
+		m.statuses = msg.statuses
+		m.loading = false
+		m.updatedAt = time.Now()
+		return m, tea.Tick(gitRefreshInterval, func(time.Time) tea.Msg {
+			return gitRefreshMsg{owner: msg.owner, generation: msg.generation}
+		})
+	case gitRefreshMsg:
+		if msg.owner == m && msg.generation == m.generation {
+			return m, m.refresh()
+		}
+	case tea.KeyMsg:
+		if m.adding {
+			return m.updateAdding(msg)
+		}
+		switch msg.String() {
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.repos)-1 {
+				m.cursor++
+			}
+		case "a":
+			m.adding = true
+			m.input.SetValue("")
+			return m, m.input.Focus()
+		case "d":
+			if m.cursor < len(m.repos) {
+				if err := storage.DeleteRepository(m.db, m.repos[m.cursor].ID); err != nil {
+					log.Printf("Error deleting repository: %v", err)
+				}
+				m.loadRepos()
+			}
+		case "r":
+			return m, m.refresh()
+		}
+	}
+	return m, nil
+}
+
+func (m *Git) updateAdding(msg tea.KeyMsg) (Module, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.adding = false
+		m.input.Blur()
+		path := strings.TrimSpace(m.input.Value())
+		if path == "" {
+			return m, nil
+		}
+		repo := storage.Repository{ID: uuid.New().String(), ProjectID: m.projectID, Path: path}
+		if err := storage.CreateRepository(m.db, repo); err != nil {
+			log.Printf("Error adding repository: %v", err)
+			return m, nil
+		}
+		m.loadRepos()
+		m.cursor = len(m.repos) - 1
+		return m, m.refresh()
+	case tea.KeyEsc:
+		m.adding = false
+		m.input.Blur()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+var (
+	gitBranchStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("212")).Bold(true)
+	gitDimStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	gitAddedStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	gitDirtyStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	gitErrorStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+)
+
+// summary is the one-line state of a repository shown in the list.
+func (s repoStatus) summary() string {
+	if s.Err != nil {
+		return gitErrorStyle.Render("error")
+	}
+	parts := []string{gitBranchStyle.Render(s.Branch)}
+	if s.Ahead > 0 {
+		parts = append(parts, fmt.Sprintf("↑%d", s.Ahead))
+	}
+	if s.Behind > 0 {
+		parts = append(parts, fmt.Sprintf("↓%d", s.Behind))
+	}
+	if len(s.Files) > 0 {
+		parts = append(parts, gitDirtyStyle.Render(fmt.Sprintf("●%d", len(s.Files))))
+	} else {
+		parts = append(parts, gitAddedStyle.Render("clean"))
+	}
+	return strings.Join(parts, " ")
+}
+
+func (m *Git) View() string {
+	if m.width == 0 {
+		return "loading..."
+	}
+	if m.adding {
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.input.View())
+	}
+
+	listWidth := m.width / 3
+	paneHeight := m.height - 10
+
+	var list []string
+	for i, repo := range m.repos {
+		line := truncate(repo.Path, listWidth-4)
+		if status, ok := m.statuses[repo.ID]; ok {
+			line += "\n  " + status.summary()
+		} else {
+			line += "\n  " + gitDimStyle.Render("loading...")
+		}
+		style := lipgloss.NewStyle().Width(listWidth - 4)
+		if i == m.cursor {
+			style = style.Background(lipgloss.Color("57"))
+		}
+		list = append(list, style.Render(line))
+	}
+	if len(m.repos) == 0 {
+		list = append(list, gitDimStyle.Render("No repositories. Press a to add one."))
+	}
+
+	listPane := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		Padding(0, 1).
+		Width(listWidth - 2).
+		Height(paneHeight).
+		Render(strings.Join(list, "\n"))
+	detailPane := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("63")).
+		Padding(0, 1).
+		Width(m.width - listWidth - 4).
+		Height(paneHeight).
+		Render(m.detailView(m.width - listWidth - 8))
+
+	footer := "(a)dd repository, (d)elete, (j/k) select, (r)efresh"
+	if m.loading {
+		footer = "refreshing...  " + footer
+	} else if !m.updatedAt.IsZero() {
+		footer = "updated " + m.updatedAt.Format("15:04:05") + "  " + footer
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.JoinHorizontal(lipgloss.Top, listPane, detailPane),
+		gitDimStyle.Render(footer))
+}
+
+func (m *Git) detailView(width int) string {
+	if m.cursor >= len(m.repos) {
+		return ""
+	}
+	repo := m.repos[m.cursor]
+	status, ok := m.statuses[repo.ID]
+	if !ok {
+		return gitDimStyle.Render("Reading " + repo.Path + "...")
+	}
+	if status.Err != nil {
+		return gitErrorStyle.Render(status.Err.Error())
+	}
+
+	heading := lipgloss.NewStyle().Bold(true)
+	lines := []string{heading.Render(repo.Path), ""}
+
+	branch := "On " + gitBranchStyle.Render(status.Branch)
+	if status.Upstream != "" {
+		branch += gitDimStyle.Render(fmt.Sprintf("  tracking %s, %d ahead, %d behind", status.Upstream, status.Ahead, status.Behind))
+	} else {
+		branch += gitDimStyle.Render("  no upstream")
+	}
+	lines = append(lines, branch, "")
+
+	lines = append(lines, heading.Render(fmt.Sprintf("Changes (%d)", len(status.Files))))
+	for i, file := range status.Files {
+		if i == 10 {
+			lines = append(lines, gitDimStyle.Render(fmt.Sprintf("…and %d more", len(status.Files)-i)))
+			break
+		}
+		style := gitDirtyStyle
+		if file.Status == "??" {
+			style = gitDimStyle
+		} else if file.Status[1] == ' ' {
+			style = gitAddedStyle // fully staged
+		}
+		lines = append(lines, style.Render(file.Status)+" "+truncate(file.Path, width-3))
+	}
+	if len(status.Files) == 0 {
+		lines = append(lines, gitDimStyle.Render("Working tree clean"))
+	}
+
+	lines = append(lines, "", heading.Render("Recent commits"))
+	for _, commit := range status.Commits {
+		meta := gitDimStyle.Render(fmt.Sprintf(" %s, %s", commit.Author, commit.When))
+		lines = append(lines, gitBranchStyle.Render(commit.Hash)+" "+truncate(commit.Subject, width-lipgloss.Width(meta)-len(commit.Hash)-1)+meta)
+	}
+	if len(status.Commits) == 0 {
+		lines = append(lines, gitDimStyle.Render("No commits yet"))
+	}
+
+	if len(status.Stashes) > 0 {
+		lines = append(lines, "", heading.Render(fmt.Sprintf("Stashes (%d)", len(status.Stashes))))
+		for _, stash := range status.Stashes {
+			lines = append(lines, gitDimStyle.Render(stash.Ref)+" "+truncate(stash.Subject, width-len(stash.Ref)-1))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
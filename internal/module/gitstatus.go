@@ -0,0 +1,243 @@
+package module
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+const (
+	gitLogLimit   = 10
+	gitFieldDelim = "\x1f"
+)
+
+type gitFile struct {
+	Status string // two-letter index/worktree status, "??" for untracked files
+	Path   string
+}
+
+type gitCommit struct {
+	Hash    string
+	Subject string
+	Author  string
+	When    string // relative, e.g. "2 hours ago"
+}
+
+type gitStash struct {
+	Ref     string
+	Subject string
+}
+
+// repoStatus is a snapshot of a repository as shown by the git module.
+type repoStatus struct {
+	Branch   string
+	Upstream string
+	Ahead    int
+	Behind   int
+	Files    []gitFile
+	Commits  []gitCommit
+	Stashes  []gitStash
+	Err      error
+}
+
+// runGit runs git in dir and returns its standard output. Errors include what
+// git printed on standard error.
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s", msg)
+		}
+		return "", err
+	}
+	return stdout.String(), nil
+}
+
+// readRepoStatus collects the branch, ahead/behind counts, changed files,
+// recent commits and stashes of the repository at path.
+func readRepoStatus(path string) repoStatus {
+	dir := expandHome(path)
+
+	out, err := runGit(dir, "status", "--porcelain=v2", "--branch")
+	if err != nil {
+		return repoStatus{Err: err}
+	}
+	status, hasCommits := parseGitStatus(out)
+
+	if hasCommits {
+		out, err = runGit(dir, "log", "-n", strconv.Itoa(gitLogLimit),
+			"--format=%h"+gitFieldDelim+"%s"+gitFieldDelim+"%an"+gitFieldDelim+"%cr")
+		if err != nil {
+			status.Err = err
+			return status
+		}
+		status.Commits = parseGitLog(out)
+	}
+
+	out, err = runGit(dir, "stash", "list", "--format=%gd"+gitFieldDelim+"%s")
+	if err != nil {
+		status.Err = err
+		return status
+	}
+	status.Stashes = parseGitStashes(out)
+	return status
+}
+
+// parseGitStatus reads the output of "git status --porcelain=v2 --branch".
+// It also reports whether the current branch has any commits.
+func parseGitStatus(out string) (repoStatus, bool) {
+	var status repoStatus
+	hasCommits := true
+	for _, line := range strings.Split(out, "\n") {
+		switch {
+		case strings.HasPrefix(line, "# branch.oid "):
+			hasCommits = strings.TrimPrefix(line, "# branch.oid ") != "(initial)"
+		case strings.HasPrefix(line, "# branch.head "):
+			status.Branch = strings.TrimPrefix(line, "# branch.head ")
+		case strings.HasPrefix(line, "# branch.upstream "):
+			status.Upstream = strings.TrimPrefix(line, "# branch.upstream ")
+		case strings.HasPrefix(line, "# branch.ab "):
+			fmt.Sscanf(strings.TrimPrefix(line, "# branch.ab "), "+%d -%d", &status.Ahead, &status.Behind)
+		case strings.HasPrefix(line, "1 "):
+			if fields := strings.SplitN(line, " ", 9); len(fields) == 9 {
+				status.Files = append(status.Files, gitFile{Status: gitXY(fields[1]), Path: fields[8]})
+			}
+		case strings.HasPrefix(line, "2 "):
+			// Renames and copies end in "path<TAB>original path".
+			if fields := strings.SplitN(line, " ", 10); len(fields) == 10 {
+				path, orig, _ := strings.Cut(fields[9], "\t")
+				status.Files = append(status.Files, gitFile{Status: gitXY(fields[1]), Path: orig + " → " + path})
+			}
+		case strings.HasPrefix(line, "u "):
+			if fields := strings.SplitN(line, " ", 11); len(fields) == 11 {
+				status.Files = append(status.Files, gitFile{Status: gitXY(fields[1]), Path: fields[10]})
+			}
+		case strings.HasPrefix(line, "? "):
+			status.Files = append(status.Files, gitFile{Status: "??", Path: strings.TrimPrefix(line, "? ")})
+		}
+	}
+	return status, hasCommits
+}
+
+// gitXY turns porcelain v2's "." for unchanged into a space, as in the short
+// status format.
+func gitXY(xy string) string {
+	return strings.ReplaceAll(xy, ".", " ")
+}
+
+func parseGitLog(out string) []gitCommit {
+	var commits []gitCommit
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.Split(line, gitFieldDelim)
+		if len(fields) != 4 {
+			continue
+		}
+		commits = append(commits, gitCommit{Hash: fields[0], Subject: fields[1], Author: fields[2], When: fields[3]})
+	}
+	return commits
+}
+
+func parseGitStashes(out string) []gitStash {
+	var stashes []gitStash
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		ref, subject, ok := strings.Cut(line, gitFieldDelim)
+		if !ok {
+			continue
+		}
+		stashes = append(stashes, gitStash{Ref: ref, Subject: subject})
+	}
+	return stashes
+}
+
+// branchState is what the Kanban board shows for a task linked to a branch.
+type branchState struct {
+	Exists     bool
+	Unpushed   int  // commits not on the upstream, or not on the main branch if there is no upstream
+	Upstream   bool // whether the branch has an upstream
+	Merged     bool // the branch has work and all of it is in the main branch
+	MainBranch string
+	Err        error
+}
+
+// mainBranch guesses the repository's main branch: the remote's default
+// branch if known, otherwise a local "main" or "master".
+func mainBranch(dir string) string {
+	if out, err := runGit(dir, "symbolic-ref", "--quiet", "--short", "refs/remotes/origin/HEAD"); err == nil {
+		return strings.TrimSpace(out)
+	}
+	for _, name := range []string{"main", "master"} {
+		if _, err := runGit(dir, "rev-parse", "--verify", "--quiet", "refs/heads/"+name); err == nil {
+			return name
+		}
+	}
+	return ""
+}
+
+func revParse(dir, rev string) (string, error) {
+	out, err := runGit(dir, "rev-parse", "--verify", "--quiet", rev)
+	return strings.TrimSpace(out), err
+}
+
+func countCommits(dir, revRange string) int {
+	out, err := runGit(dir, "rev-list", "--count", revRange)
+	if err != nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(strings.TrimSpace(out))
+	return n
+}
+
+// readBranchState checks a branch of the repository at path. base is the
+// main branch's tip when the branch was linked: the branch counts as merged
+// once it has commits beyond base and its tip is contained in the main
+// branch, so that a freshly created branch doesn't look merged but a
+// fast-forwarded one does. Without base, as for tasks linked before it was
+// recorded, the branch counts as merged once its tip is contained in the
+// main branch without being the main branch's tip itself.
+func readBranchState(path, branch, base string) branchState {
+	dir := expandHome(path)
+	var state branchState
+
+	if _, err := runGit(dir, "rev-parse", "--git-dir"); err != nil {
+		state.Err = err
+		return state
+	}
+	tip, err := revParse(dir, "refs/heads/"+branch)
+	if err != nil {
+		return state
+	}
+	state.Exists = true
+	state.MainBranch = mainBranch(dir)
+
+	if _, err := revParse(dir, branch+"@{upstream}"); err == nil {
+		state.Upstream = true
+		state.Unpushed = countCommits(dir, branch+"@{upstream}.."+branch)
+	} else if state.MainBranch != "" {
+		state.Unpushed = countCommits(dir, state.MainBranch+".."+branch)
+	}
+
+	if state.MainBranch == "" || state.MainBranch == branch || strings.HasSuffix(state.MainBranch, "/"+branch) {
+		return state
+	}
+	mainTip, err := revParse(dir, state.MainBranch)
+	if err != nil {
+		return state
+	}
+	if _, err := revParse(dir, base+"^{commit}"); base != "" && err == nil {
+		if countCommits(dir, base+".."+tip) == 0 {
+			return state
+		}
+	} else if mainTip == tip {
+		return state
+	}
+	if _, err := runGit(dir, "merge-base", "--is-ancestor", tip, mainTip); err == nil {
+		state.Merged = true
+		state.Unpushed = 0
+	}
+	return state
+}
@@ -0,0 +1,141 @@
+package module
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// gitIn runs git in dir and fails the test if it doesn't succeed.
+func gitIn(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	if _, err := runGit(dir, args...); err != nil {
+		t.Fatalf("git %v: %v", args, err)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+// setupGit skips the test without git and isolates it from the user's git
+// configuration.
+func setupGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_AUTHOR_NAME", "Tester")
+	t.Setenv("GIT_AUTHOR_EMAIL", "tester@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Tester")
+	t.Setenv("GIT_COMMITTER_EMAIL", "tester@example.com")
+}
+
+func TestReadRepoStatus(t *testing.T) {
+	setupGit(t)
+
+	origin := t.TempDir()
+	gitIn(t, origin, "init", "-q", "-b", "main")
+	if status := readRepoStatus(origin); status.Err != nil || status.Branch != "main" || len(status.Commits) != 0 {
+		t.Errorf("unexpected status of an empty repository: %+v", status)
+	}
+	writeFile(t, filepath.Join(origin, "README"), "hello\n")
+	gitIn(t, origin, "add", "README")
+	gitIn(t, origin, "commit", "-q", "-m", "first")
+
+	clone := filepath.Join(t.TempDir(), "clone")
+	gitIn(t, origin, "clone", "-q", origin, clone)
+	writeFile(t, filepath.Join(clone, "README"), "hello again\n")
+	gitIn(t, clone, "commit", "-q", "-am", "second")
+	writeFile(t, filepath.Join(clone, "README"), "stashed\n")
+	gitIn(t, clone, "stash", "-q")
+	writeFile(t, filepath.Join(clone, "README"), "dirty\n")
+	writeFile(t, filepath.Join(clone, "new file.txt"), "untracked\n")
+
+	status := readRepoStatus(clone)
+	if status.Err != nil {
+		t.Fatalf("readRepoStatus returned error: %v", status.Err)
+	}
+	if status.Branch != "main" || status.Upstream != "origin/main" || status.Ahead != 1 || status.Behind != 0 {
+		t.Errorf("unexpected branch state: %s tracking %s, +%d -%d", status.Branch, status.Upstream, status.Ahead, status.Behind)
+	}
+	want := []gitFile{{Status: " M", Path: "README"}, {Status: "??", Path: "new file.txt"}}
+	if len(status.Files) != len(want) {
+		t.Fatalf("expected files %v, got %v", want, status.Files)
+	}
+	for i := range want {
+		if status.Files[i] != want[i] {
+			t.Errorf("file %d: expected %v, got %v", i, want[i], status.Files[i])
+		}
+	}
+	if len(status.Commits) != 2 || status.Commits[0].Subject != "second" || status.Commits[0].Author != "Tester" {
+		t.Errorf("unexpected commits %+v", status.Commits)
+	}
+	if len(status.Stashes) != 1 || status.Stashes[0].Ref != "stash@{0}" {
+		t.Errorf("unexpected stashes %+v", status.Stashes)
+	}
+
+	if status := readRepoStatus(t.TempDir()); status.Err == nil {
+		t.Error("expected an error for a directory that isn't a repository")
+	}
+}
+
+func TestReadBranchState(t *testing.T) {
+	setupGit(t)
+
+	repo := t.TempDir()
+	gitIn(t, repo, "init", "-q", "-b", "main")
+	writeFile(t, filepath.Join(repo, "README"), "hello\n")
+	gitIn(t, repo, "add", "README")
+	gitIn(t, repo, "commit", "-q", "-m", "first")
+
+	base := linkBase(repo)
+	gitIn(t, repo, "branch", "fresh")
+	if state := readBranchState(repo, "fresh", base); !state.Exists || state.Merged || state.Unpushed != 0 {
+		t.Errorf("a new branch shouldn't look merged: %+v", state)
+	}
+
+	gitIn(t, repo, "checkout", "-q", "-b", "feature")
+	writeFile(t, filepath.Join(repo, "feature.txt"), "work\n")
+	gitIn(t, repo, "add", "feature.txt")
+	gitIn(t, repo, "commit", "-q", "-m", "feature work")
+	state := readBranchState(repo, "feature", base)
+	if !state.Exists || state.Merged || state.Upstream || state.Unpushed != 1 || state.MainBranch != "main" {
+		t.Errorf("unexpected state before merging: %+v", state)
+	}
+
+	gitIn(t, repo, "checkout", "-q", "main")
+	gitIn(t, repo, "merge", "-q", "--no-ff", "-m", "merge feature", "feature")
+	if state := readBranchState(repo, "feature", base); !state.Merged || state.Unpushed != 0 {
+		t.Errorf("expected feature to be merged: %+v", state)
+	}
+	if state := readBranchState(repo, "feature", ""); !state.Merged {
+		t.Errorf("expected feature to be merged without a base: %+v", state)
+	}
+
+	base = linkBase(repo)
+	gitIn(t, repo, "branch", "quick")
+	if state := readBranchState(repo, "quick", base); state.Merged {
+		t.Errorf("a new branch shouldn't look merged: %+v", state)
+	}
+	gitIn(t, repo, "checkout", "-q", "quick")
+	writeFile(t, filepath.Join(repo, "quick.txt"), "fix\n")
+	gitIn(t, repo, "add", "quick.txt")
+	gitIn(t, repo, "commit", "-q", "-m", "quick fix")
+	gitIn(t, repo, "checkout", "-q", "main")
+	gitIn(t, repo, "merge", "-q", "--ff-only", "quick")
+	if state := readBranchState(repo, "quick", base); !state.Merged || state.Unpushed != 0 {
+		t.Errorf("expected a fast-forwarded branch to be merged: %+v", state)
+	}
+
+	if state := readBranchState(repo, "gone", ""); state.Exists || state.Err != nil {
+		t.Errorf("expected a missing branch without error: %+v", state)
+	}
+	if state := readBranchState(t.TempDir(), "main", ""); state.Err == nil {
+		t.Error("expected an error outside a repository")
+	}
+}
@@ -0,0 +1,413 @@
+package module
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Ceinl/Go-dashboard/internal/storage"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/google/uuid"
+)
+
+// Uses of the Habits text input.
+const (
+	habitInputNone = iota
+	habitInputAdd
+	habitInputRename
+)
+
+// Heatmap colors from no activity to full, as on GitHub.
+var heatmapColors = []lipgloss.Color{"237", "22", "28", "34", "40"}
+
+// Habits tracks daily and weekly habits of a workspace with streaks and a
+// contribution heatmap.
+type Habits struct {
+	db          *sql.DB
+	workspaceID string
+	habits      []storage.Habit
+	checkIns    map[string]map[string]bool // days by habit ID
+	cursor      int
+	showAll     bool // the heatmap sums up every habit
+
+	inputMode int
+	input     textinput.Model
+
+	width  int
+	height int
+}
+
+func NewHabits(db *sql.DB, workspaceID string) Module {
+	ti := textinput.New()
+	ti.CharLimit = 100
+	ti.Width = 40
+
+	return &Habits{
+		db:          db,
+		workspaceID: workspaceID,
+		checkIns:    make(map[string]map[string]bool),
+		input:       ti,
+	}
+}
+
+func (m *Habits) Init() tea.Cmd {
+	m.loadHabits()
+	return nil
+}
+
+func (m *Habits) loadHabits() {
+	habits, err := storage.GetHabitsForWorkspace(m.db, m.workspaceID)
+	if err != nil {
+		log.Printf("Error loading habits: %v", err)
+	}
+	m.habits = habits
+	if m.cursor >= len(m.habits) {
+		m.cursor = max(len(m.habits)-1, 0)
+	}
+
+	checkIns, err := storage.GetCheckIns(m.db, m.workspaceID)
+	if err != nil {
+		log.Printf("Error loading check-ins: %v", err)
+	}
+	m.checkIns = checkIns
+}
+
+func (m *Habits) selectedHabit() (storage.Habit, bool) {
+	if m.cursor < len(m.habits) {
+		return m.habits[m.cursor], true
+	}
+	return storage.Habit{}, false
+}
+
+// CapturingInput reports whether the module needs every key press.
+func (m *Habits) CapturingInput() bool {
+	return m.inputMode != habitInputNone
+}
+
+func (m *Habits) Update(msg tea.Msg) (Module, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	case tea.KeyMsg:
+		if m.inputMode != habitInputNone {
+			return m.updateInput(msg)
+		}
+		switch msg.String() {
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.habits)-1 {
+				m.cursor++
+			}
+		case " ", "enter", "x":
+			if habit, ok := m.selectedHabit(); ok {
+				if _, err := storage.ToggleCheckIn(m.db, habit.ID, startOfDay(time.Now())); err != nil {
+					log.Printf("Error checking in: %v", err)
+				}
+				m.loadHabits()
+			}
+		case "w":
+			if habit, ok := m.selectedHabit(); ok {
+				if habit.Frequency == storage.HabitWeekly {
+					habit.Frequency = storage.HabitDaily
+				} else {
+					habit.Frequency = storage.HabitWeekly
+				}
+				if err := storage.UpdateHabit(m.db, habit); err != nil {
+					log.Printf("Error updating habit: %v", err)
+				}
+				m.loadHabits()
+			}
+		case "v":
+			m.showAll = !m.showAll
+		case "a":
+			return m, m.startInput(habitInputAdd, "")
+		case "e":
+			if habit, ok := m.selectedHabit(); ok {
+				return m, m.startInput(habitInputRename, habit.Name)
+			}
+		case "d":
+			if habit, ok := m.selectedHabit(); ok {
+				if err := storage.DeleteHabit(m.db, habit.ID); err != nil {
+					log.Printf("Error deleting habit: %v", err)
+				}
+				m.loadHabits()
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m *Habits) startInput(mode int, value string) tea.Cmd {
+	m.inputMode = mode
+	m.input.Placeholder = "Habit name"
+	m.input.SetValue(value)
+	m.input.CursorEnd()
+	return m.input.Focus()
+}
+
+func (m *Habits) updateInput(msg tea.KeyMsg) (Module, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		name := strings.TrimSpace(m.input.Value())
+		if name != "" {
+			if m.inputMode == habitInputAdd {
+				habit := storage.Habit{ID: uuid.New().String(), WorkspaceID: m.workspaceID, Name: name, Frequency: storage.HabitDaily, CreatedAt: time.Now()}
+				if err := storage.CreateHabit(m.db, habit); err != nil {
+					log.Printf("Error adding habit: %v", err)
+				}
+				m.loadHabits()
+				m.cursor = max(len(m.habits)-1, 0)
+			} else if habit, ok := m.selectedHabit(); ok {
+				habit.Name = name
+				if err := storage.UpdateHabit(m.db, habit); err != nil {
+					log.Printf("Error renaming habit: %v", err)
+				}
+				m.loadHabits()
+			}
+		}
+		fallthrough
+	case tea.KeyEsc:
+		m.inputMode = habitInputNone
+		m.input.Blur()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// checkedInWeek reports whether there is a check-in in the week starting on
+// monday.
+func checkedInWeek(days map[string]bool, monday time.Time) bool {
+	for i := 0; i < 7; i++ {
+		if days[monday.AddDate(0, 0, i).Format(storage.DateLayout)] {
+			return true
+		}
+	}
+	return false
+}
+
+// habitStreak counts the consecutive days, or weeks for a weekly habit, with
+// a check-in up to today. A habit not yet checked in today, or this week,
+// keeps the streak it had.
+func habitStreak(days map[string]bool, frequency string, today time.Time) int {
+	today = startOfDay(today)
+	streak := 0
+	if frequency == storage.HabitWeekly {
+		week := startOfWeek(today)
+		if !checkedInWeek(days, week) {
+			week = week.AddDate(0, 0, -7)
+		}
+		for checkedInWeek(days, week) {
+			streak++
+			week = week.AddDate(0, 0, -7)
+		}
+		return streak
+	}
+
+	day := today
+	if !days[day.Format(storage.DateLayout)] {
+		day = day.AddDate(0, 0, -1)
+	}
+	for days[day.Format(storage.DateLayout)] {
+		streak++
+		day = day.AddDate(0, 0, -1)
+	}
+	return streak
+}
+
+// habitBestStreak returns the longest streak ever.
+func habitBestStreak(days map[string]bool, frequency string) int {
+	var periods []time.Time
+	seen := make(map[time.Time]bool)
+	for date := range days {
+		day, err := time.ParseInLocation(storage.DateLayout, date, time.Local)
+		if err != nil {
+			continue
+		}
+		if frequency == storage.HabitWeekly {
+			day = startOfWeek(day)
+		}
+		if !seen[day] {
+			seen[day] = true
+			periods = append(periods, day)
+		}
+	}
+	sort.Slice(periods, func(i, j int) bool { return periods[i].Before(periods[j]) })
+
+	step := 1
+	if frequency == storage.HabitWeekly {
+		step = 7
+	}
+	best, run := 0, 0
+	for i, period := range periods {
+		if i > 0 && periods[i-1].AddDate(0, 0, step).Equal(period) {
+			run++
+		} else {
+			run = 1
+		}
+		best = max(best, run)
+	}
+	return best
+}
+
+// heatmapLevel maps the share of habits done on a day to a heatmap color.
+func heatmapLevel(done, total int) int {
+	switch {
+	case done == 0 || total == 0:
+		return 0
+	case done >= total:
+		return len(heatmapColors) - 1
+	}
+	return 1 + (done-1)*(len(heatmapColors)-2)/(total-1)
+}
+
+// renderHeatmap draws the last weeks up to today as a grid of weekdays by
+// weeks, with month names above. level returns the color index of a day.
+func renderHeatmap(today time.Time, weeks int, level func(day time.Time) int) string {
+	today = startOfDay(today)
+	first := startOfWeek(today).AddDate(0, 0, -7*(weeks-1))
+
+	// Name each month above the week it starts in.
+	months := []rune(strings.Repeat(" ", 4+2*weeks))
+	free := 0 // first column not taken by the previous name
+	for w := 0; w < weeks; w++ {
+		monday := first.AddDate(0, 0, 7*w)
+		col := 4 + 2*w
+		if monday.Day() <= 7 && col >= free && col+3 <= len(months) {
+			copy(months[col:], []rune(monday.Format("Jan")))
+			free = col + 4
+		}
+	}
+
+	labels := []string{"Mon", "", "Wed", "", "Fri", "", "Sun"}
+	lines := []string{string(months)}
+	for weekday := 0; weekday < 7; weekday++ {
+		var row strings.Builder
+		row.WriteString(fmt.Sprintf("%-4s", labels[weekday]))
+		for w := 0; w < weeks; w++ {
+			day := first.AddDate(0, 0, 7*w+weekday)
+			if day.After(today) {
+				row.WriteString("  ")
+				continue
+			}
+			style := lipgloss.NewStyle().Foreground(heatmapColors[level(day)])
+			cell := "■"
+			if day.Equal(today) {
+				cell = "◆"
+			}
+			row.WriteString(style.Render(cell) + " ")
+		}
+		lines = append(lines, row.String())
+	}
+
+	var legend strings.Builder
+	legend.WriteString("    Less ")
+	for _, color := range heatmapColors {
+		legend.WriteString(lipgloss.NewStyle().Foreground(color).Render("■") + " ")
+	}
+	legend.WriteString("More")
+	lines = append(lines, legend.String())
+	return strings.Join(lines, "\n")
+}
+
+var (
+	habitDimStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	habitDoneStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("40"))
+	habitStreakStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+)
+
+func (m *Habits) View() string {
+	if m.width == 0 {
+		return "loading..."
+	}
+	if m.inputMode != habitInputNone {
+		return lipgloss.Place(m.width, m.height-10, lipgloss.Center, lipgloss.Center, m.input.View())
+	}
+	if len(m.habits) == 0 {
+		return habitDimStyle.Render("No habits. Press a to add one.")
+	}
+
+	today := startOfDay(time.Now())
+	todayKey := today.Format(storage.DateLayout)
+	nameWidth := 10
+	for _, habit := range m.habits {
+		nameWidth = max(nameWidth, min(lipgloss.Width(habit.Name), 30))
+	}
+
+	var rows []string
+	for i, habit := range m.habits {
+		days := m.checkIns[habit.ID]
+		done := days[todayKey]
+		if habit.Frequency == storage.HabitWeekly {
+			done = checkedInWeek(days, startOfWeek(today))
+		}
+		box := "[ ]"
+		if done {
+			box = habitDoneStyle.Render("[✓]")
+		}
+		streak := habitStreak(days, habit.Frequency, today)
+		unit := "day"
+		if habit.Frequency == storage.HabitWeekly {
+			unit = "week"
+		}
+		if streak != 1 {
+			unit += "s"
+		}
+		streakText := habitDimStyle.Render(fmt.Sprintf("%d %s", streak, unit))
+		if streak > 0 {
+			streakText = habitStreakStyle.Render(fmt.Sprintf("🔥 %d %s", streak, unit))
+		}
+		line := fmt.Sprintf("%s %-*s %-7s %s %s", box, nameWidth, truncate(habit.Name, nameWidth), habit.Frequency, streakText,
+			habitDimStyle.Render(fmt.Sprintf("(best %d)", habitBestStreak(days, habit.Frequency))))
+		style := lipgloss.NewStyle()
+		if i == m.cursor {
+			style = style.Background(lipgloss.Color("57"))
+		}
+		rows = append(rows, style.Render(line))
+	}
+
+	weeks := max(min((m.width-8)/2, 53), 4)
+	var title string
+	var level func(time.Time) int
+	if habit, ok := m.selectedHabit(); ok && !m.showAll {
+		title = habit.Name
+		days := m.checkIns[habit.ID]
+		level = func(day time.Time) int {
+			if days[day.Format(storage.DateLayout)] {
+				return len(heatmapColors) - 1
+			}
+			return 0
+		}
+	} else {
+		title = "All habits"
+		level = func(day time.Time) int {
+			key := day.Format(storage.DateLayout)
+			done := 0
+			for _, habit := range m.habits {
+				if m.checkIns[habit.ID][key] {
+					done++
+				}
+			}
+			return heatmapLevel(done, len(m.habits))
+		}
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		strings.Join(rows, "\n"),
+		"",
+		lipgloss.NewStyle().Bold(true).Render(title),
+		renderHeatmap(today, weeks, level),
+		"",
+		habitDimStyle.Render("(space) check in today, (a)dd, (e)dit, (d)elete, (w)eekly/daily, (v) selected/all habits"))
+}
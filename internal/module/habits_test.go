@@ -0,0 +1,94 @@
+package module
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ceinl/Go-dashboard/internal/storage"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/uuid"
+)
+
+func checkInDays(today time.Time, offsets ...int) map[string]bool {
+	days := make(map[string]bool)
+	for _, offset := range offsets {
+		days[today.AddDate(0, 0, -offset).Format(storage.DateLayout)] = true
+	}
+	return days
+}
+
+func TestHabitStreaks(t *testing.T) {
+	// A Thursday.
+	today := time.Date(2024, 3, 14, 0, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name      string
+		frequency string
+		offsets   []int // days before today with a check-in
+		streak    int
+		best      int
+	}{
+		{"checked in today", storage.HabitDaily, []int{0, 1, 2, 5, 6, 7, 8}, 3, 4},
+		{"not yet today", storage.HabitDaily, []int{1, 2}, 2, 2},
+		{"missed yesterday", storage.HabitDaily, []int{2, 3}, 0, 2},
+		{"no check-ins", storage.HabitDaily, nil, 0, 0},
+		// This week, last week (Monday) and the week before; then a gap.
+		{"weekly", storage.HabitWeekly, []int{1, 10, 14, 28}, 3, 3},
+		{"weekly, not yet this week", storage.HabitWeekly, []int{5, 12}, 2, 2},
+		{"weekly, missed last week", storage.HabitWeekly, []int{12}, 0, 1},
+	}
+	for _, tt := range tests {
+		days := checkInDays(today, tt.offsets...)
+		if got := habitStreak(days, tt.frequency, today); got != tt.streak {
+			t.Errorf("%s: expected a streak of %d, got %d", tt.name, tt.streak, got)
+		}
+		if got := habitBestStreak(days, tt.frequency); got != tt.best {
+			t.Errorf("%s: expected a best streak of %d, got %d", tt.name, tt.best, got)
+		}
+	}
+}
+
+func TestHeatmapLevel(t *testing.T) {
+	top := len(heatmapColors) - 1
+	for _, tt := range []struct{ done, total, level int }{
+		{0, 3, 0}, {1, 1, top}, {1, 4, 1}, {3, 4, 3}, {4, 4, top},
+	} {
+		if got := heatmapLevel(tt.done, tt.total); got != tt.level {
+			t.Errorf("%d of %d: expected level %d, got %d", tt.done, tt.total, tt.level, got)
+		}
+	}
+}
+
+func TestHabitsCheckIn(t *testing.T) {
+	db, _ := setupTestDB(t)
+	workspaceID := uuid.New().String()
+
+	m := NewHabits(db, workspaceID)
+	m.Init()
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = typeKeys(m, "a", "R", "e", "a", "d", "enter")
+	habits := m.(*Habits)
+	if len(habits.habits) != 1 || habits.habits[0].Name != "Read" || habits.habits[0].Frequency != storage.HabitDaily {
+		t.Fatalf("expected a daily habit, got %+v", habits.habits)
+	}
+
+	m = typeKeys(m, " ")
+	today := startOfDay(time.Now())
+	if habitStreak(habits.checkIns[habits.habits[0].ID], storage.HabitDaily, today) != 1 {
+		t.Errorf("expected a streak of 1 after checking in, got %v", habits.checkIns)
+	}
+	if view := m.View(); !strings.Contains(view, "[✓]") || !strings.Contains(view, "1 day") {
+		t.Errorf("expected the habit to show as done, got:\n%s", view)
+	}
+
+	m = typeKeys(m, " ")
+	if len(habits.checkIns[habits.habits[0].ID]) != 0 {
+		t.Errorf("expected the check-in to be toggled off, got %v", habits.checkIns)
+	}
+
+	m = typeKeys(m, "w")
+	if habits.habits[0].Frequency != storage.HabitWeekly {
+		t.Errorf("expected the habit to become weekly, got %q", habits.habits[0].Frequency)
+	}
+}
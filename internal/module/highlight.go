@@ -0,0 +1,222 @@
+package module
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	codeKeywordStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("170")).Bold(true)
+	codeStringStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("114"))
+	codeNumberStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("209"))
+	codeCommentStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("244")).Italic(true)
+)
+
+// syntax describes just enough of a language to colour keywords, strings,
+// numbers and comments. It is not a parser: a quote inside a comment or a
+// keyword used as a field name may be coloured wrongly, which is fine for a
+// preview.
+type syntax struct {
+	keywords        []string
+	caseInsensitive bool // SQL keywords are matched regardless of case
+	lineComments    []string
+	blockComment    [2]string // start and end, empty when unsupported
+	quotes          string
+}
+
+var syntaxes = map[string]syntax{
+	"go": {
+		keywords: []string{"break", "case", "chan", "const", "continue", "default", "defer", "else", "fallthrough",
+			"for", "func", "go", "goto", "if", "import", "interface", "map", "package", "range", "return", "select",
+			"struct", "switch", "type", "var", "nil", "true", "false"},
+		lineComments: []string{"//"},
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       "\"'`",
+	},
+	"sh": {
+		keywords: []string{"if", "then", "else", "elif", "fi", "for", "while", "until", "do", "done", "case", "esac",
+			"in", "function", "return", "export", "local", "set", "unset", "echo", "exit", "source"},
+		lineComments: []string{"#"},
+		quotes:       "\"'",
+	},
+	"sql": {
+		keywords: []string{"select", "from", "where", "and", "or", "not", "insert", "into", "values", "update", "set",
+			"delete", "create", "table", "index", "drop", "alter", "join", "left", "right", "inner", "outer", "on",
+			"as", "group", "by", "order", "having", "limit", "offset", "distinct", "union", "all", "null", "is", "in",
+			"like", "between", "case", "when", "then", "else", "end", "asc", "desc", "with", "exists", "primary", "key"},
+		caseInsensitive: true,
+		lineComments:    []string{"--"},
+		blockComment:    [2]string{"/*", "*/"},
+		quotes:          "'\"",
+	},
+	"python": {
+		keywords: []string{"and", "as", "assert", "break", "class", "continue", "def", "del", "elif", "else", "except",
+			"finally", "for", "from", "global", "if", "import", "in", "is", "lambda", "not", "or", "pass", "raise",
+			"return", "try", "while", "with", "yield", "None", "True", "False"},
+		lineComments: []string{"#"},
+		quotes:       "\"'",
+	},
+	"javascript": {
+		keywords: []string{"async", "await", "break", "case", "catch", "class", "const", "continue", "default",
+			"delete", "do", "else", "export", "extends", "finally", "for", "function", "if", "import", "in",
+			"instanceof", "let", "new", "return", "switch", "this", "throw", "try", "typeof", "var", "while",
+			"null", "undefined", "true", "false"},
+		lineComments: []string{"//"},
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       "\"'`",
+	},
+	"json": {
+		keywords: []string{"true", "false", "null"},
+		quotes:   "\"",
+	},
+	"yaml": {
+		keywords:     []string{"true", "false", "null", "yes", "no"},
+		lineComments: []string{"#"},
+		quotes:       "\"'",
+	},
+}
+
+// languageAliases maps alternative names and file extensions to the keys of
+// syntaxes.
+var languageAliases = map[string]string{
+	"golang": "go",
+	"bash":   "sh", "zsh": "sh", "shell": "sh", "console": "sh",
+	"postgres": "sql", "postgresql": "sql", "mysql": "sql", "sqlite": "sql",
+	"py": "python",
+	"js": "javascript", "ts": "javascript", "typescript": "javascript", "node": "javascript",
+	"yml": "yaml",
+}
+
+// canonicalLanguage normalises a language name or file extension, e.g.
+// "Bash" or ".sh" to "sh". Unknown languages are returned lower-cased.
+func canonicalLanguage(name string) string {
+	name = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(name), "."))
+	if alias, ok := languageAliases[name]; ok {
+		return alias
+	}
+	return name
+}
+
+// highlightCode colours code for the terminal. Languages without a syntax
+// still get strings and numbers highlighted.
+func highlightCode(code, language string) string {
+	lang, ok := syntaxes[canonicalLanguage(language)]
+	if !ok {
+		lang = syntax{quotes: "\"'"}
+	}
+	keywords := make(map[string]bool, len(lang.keywords))
+	for _, keyword := range lang.keywords {
+		keywords[keyword] = true
+	}
+
+	var out strings.Builder
+	inBlock := false
+	for n, line := range strings.Split(strings.ReplaceAll(code, "\t", "    "), "\n") {
+		if n > 0 {
+			out.WriteString("\n")
+		}
+		inBlock = lang.highlightLine(&out, line, keywords, inBlock)
+	}
+	return out.String()
+}
+
+// highlightLine writes one highlighted line and reports whether a block
+// comment is still open at its end.
+func (lang syntax) highlightLine(out *strings.Builder, line string, keywords map[string]bool, inBlock bool) bool {
+	i := 0
+	if inBlock {
+		end := strings.Index(line, lang.blockComment[1])
+		if end < 0 {
+			out.WriteString(codeCommentStyle.Render(line))
+			return true
+		}
+		i = end + len(lang.blockComment[1])
+		out.WriteString(codeCommentStyle.Render(line[:i]))
+	}
+
+	for i < len(line) {
+		rest := line[i:]
+		if hasAnyPrefix(rest, lang.lineComments) {
+			out.WriteString(codeCommentStyle.Render(rest))
+			return false
+		}
+		if start := lang.blockComment[0]; start != "" && strings.HasPrefix(rest, start) {
+			end := strings.Index(rest[len(start):], lang.blockComment[1])
+			if end < 0 {
+				out.WriteString(codeCommentStyle.Render(rest))
+				return true
+			}
+			end += len(start) + len(lang.blockComment[1])
+			out.WriteString(codeCommentStyle.Render(rest[:end]))
+			i += end
+			continue
+		}
+
+		c := line[i]
+		switch {
+		case strings.IndexByte(lang.quotes, c) >= 0:
+			end := closingQuote(rest)
+			out.WriteString(codeStringStyle.Render(rest[:end]))
+			i += end
+		case isDigit(c) && (i == 0 || !isWordByte(line[i-1])):
+			end := 1
+			for end < len(rest) && (isWordByte(rest[end]) || rest[end] == '.') {
+				end++
+			}
+			out.WriteString(codeNumberStyle.Render(rest[:end]))
+			i += end
+		case isWordByte(c):
+			end := 1
+			for end < len(rest) && isWordByte(rest[end]) {
+				end++
+			}
+			word := rest[:end]
+			if lang.caseInsensitive {
+				word = strings.ToLower(word)
+			}
+			if keywords[word] {
+				out.WriteString(codeKeywordStyle.Render(rest[:end]))
+			} else {
+				out.WriteString(rest[:end])
+			}
+			i += end
+		default:
+			out.WriteByte(c)
+			i++
+		}
+	}
+	return false
+}
+
+// closingQuote returns the length of the quoted string at the start of s,
+// skipping escaped quotes. An unterminated string runs to the end of s.
+func closingQuote(s string) int {
+	quote := s[0]
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case quote:
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || isDigit(c) || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
@@ -0,0 +1,136 @@
+package module
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// icsEvent is a read-only event imported from an iCalendar file. For
+// all-day events End is exclusive, as in the file format.
+type icsEvent struct {
+	Summary  string
+	Location string
+	Start    time.Time
+	End      time.Time
+	AllDay   bool
+}
+
+// occursOn reports whether the event takes place on the local day starting
+// at day.
+func (e icsEvent) occursOn(day time.Time) bool {
+	next := day.AddDate(0, 0, 1)
+	if e.End.IsZero() || !e.End.After(e.Start) {
+		return !e.Start.Before(day) && e.Start.Before(next)
+	}
+	return e.Start.Before(next) && e.End.After(day)
+}
+
+// parseICS reads the VEVENTs of an iCalendar (.ics) file, ordered by start
+// time. Recurrence rules are not expanded; only the first occurrence of a
+// recurring event is returned. Events without a valid DTSTART are skipped.
+func parseICS(r io.Reader) ([]icsEvent, error) {
+	lines, err := unfoldICS(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []icsEvent
+	var current *icsEvent
+	for _, line := range lines {
+		name, params, value := splitICSLine(line)
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			current = &icsEvent{}
+		case name == "END" && value == "VEVENT":
+			if current != nil && !current.Start.IsZero() {
+				events = append(events, *current)
+			}
+			current = nil
+		case current == nil:
+		case name == "SUMMARY":
+			current.Summary = unescapeICS(value)
+		case name == "LOCATION":
+			current.Location = unescapeICS(value)
+		case name == "DTSTART":
+			current.Start, current.AllDay = parseICSTime(value, params)
+		case name == "DTEND":
+			current.End, _ = parseICSTime(value, params)
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Start.Before(events[j].Start) })
+	return events, nil
+}
+
+// unfoldICS joins continuation lines, which start with a space or a tab.
+func unfoldICS(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// splitICSLine splits "NAME;PARAM=x;PARAM=y:value" into its parts.
+func splitICSLine(line string) (string, map[string]string, string) {
+	head, value, found := strings.Cut(line, ":")
+	if !found {
+		return "", nil, ""
+	}
+	parts := strings.Split(head, ";")
+	params := make(map[string]string)
+	for _, param := range parts[1:] {
+		if k, v, ok := strings.Cut(param, "="); ok {
+			params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+		}
+	}
+	return strings.ToUpper(parts[0]), params, value
+}
+
+// parseICSTime understands dates, UTC times and local times with an optional
+// TZID. Unknown time zones fall back to the local zone.
+func parseICSTime(value string, params map[string]string) (time.Time, bool) {
+	if params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		t, err := time.ParseInLocation("20060102", value, time.Local)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return t, true
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return t.Local(), false
+	}
+
+	loc := time.Local
+	if tzid := params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t.Local(), false
+}
+
+var icsUnescaper = strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`)
+
+func unescapeICS(s string) string {
+	return icsUnescaper.Replace(s)
+}
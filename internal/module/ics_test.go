@@ -0,0 +1,59 @@
+package module
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const testICS = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Team standup\\, daily\r\n" +
+	"DTSTART:20260302T090000Z\r\n" +
+	"DTEND:20260302T091500Z\r\n" +
+	"LOCATION:Room 4\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Conference with a very long\r\n" +
+	"  name\r\n" +
+	"DTSTART;VALUE=DATE:20260301\r\n" +
+	"DTEND;VALUE=DATE:20260303\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:No start\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParseICS(t *testing.T) {
+	events, err := parseICS(strings.NewReader(testICS))
+	if err != nil {
+		t.Fatalf("parseICS returned error: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d: %+v", len(events), events)
+	}
+
+	conference, standup := events[0], events[1]
+	if conference.Summary != "Conference with a very long name" || !conference.AllDay {
+		t.Errorf("unexpected all-day event %+v", conference)
+	}
+	if standup.Summary != "Team standup, daily" || standup.Location != "Room 4" || standup.AllDay {
+		t.Errorf("unexpected timed event %+v", standup)
+	}
+	if want := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC); !standup.Start.Equal(want) {
+		t.Errorf("expected standup at %v, got %v", want, standup.Start)
+	}
+
+	// DTEND of an all-day event is exclusive.
+	for day, want := range map[int]bool{28: false, 1: true, 2: true, 3: false} {
+		month := time.March
+		if day == 28 {
+			month = time.February
+		}
+		date := time.Date(2026, month, day, 0, 0, 0, 0, time.Local)
+		if got := conference.occursOn(date); got != want {
+			t.Errorf("conference on %s: got %v, want %v", date.Format("Jan 2"), got, want)
+		}
+	}
+}
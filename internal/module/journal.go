@@ -0,0 +1,300 @@
+package module
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Ceinl/Go-dashboard/internal/storage"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+var journalHeadingStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212"))
+
+// journalEditedMsg is sent when the external editor opened with "E" exits.
+type journalEditedMsg struct {
+	day     time.Time
+	content string
+	err     error
+}
+
+// Journal keeps one dated entry per day for a workspace, next to a summary
+// of what got done that day: tasks completed and links added.
+type Journal struct {
+	db          *sql.DB
+	workspaceID string
+	now         func() time.Time
+
+	day      time.Time // start of the displayed day
+	entry    storage.JournalEntry
+	activity storage.DayActivity
+	days     []string // days with an entry, oldest first
+
+	editing bool
+	editor  textarea.Model
+	view    viewport.Model
+
+	width  int
+	height int
+}
+
+func NewJournal(db *sql.DB, workspaceID string) Module {
+	editor := textarea.New()
+	editor.Placeholder = "What did you work on today?"
+	editor.MaxHeight = 0
+	editor.ShowLineNumbers = false
+
+	return &Journal{
+		db:          db,
+		workspaceID: workspaceID,
+		now:         time.Now,
+		editor:      editor,
+		view:        viewport.New(0, 0),
+	}
+}
+
+func (m *Journal) Init() tea.Cmd {
+	m.showDay(startOfDay(m.now()))
+	return nil
+}
+
+// CapturingInput reports whether the module needs every key press.
+func (m *Journal) CapturingInput() bool {
+	return m.editing
+}
+
+// showDay loads the entry and activity of day.
+func (m *Journal) showDay(day time.Time) {
+	m.day = day
+	entry, err := storage.GetJournalEntry(m.db, m.workspaceID, day)
+	if err != nil {
+		log.Printf("Error loading journal entry: %v", err)
+	}
+	m.entry = entry
+
+	activity, err := storage.GetDayActivity(m.db, m.workspaceID, day)
+	if err != nil {
+		log.Printf("Error loading journal summary: %v", err)
+	}
+	m.activity = activity
+
+	days, err := storage.GetJournalDays(m.db, m.workspaceID)
+	if err != nil {
+		log.Printf("Error loading journal days: %v", err)
+	}
+	m.days = days
+	m.refreshView()
+}
+
+func (m *Journal) refreshView() {
+	content := m.entry.Content
+	if content == "" {
+		content = gitDimStyle.Render("Nothing written for this day. Press e to write.")
+	} else {
+		content = renderMarkdown(content, m.view.Width)
+	}
+	m.view.SetContent(content + "\n\n" + m.renderSummary())
+	m.view.GotoTop()
+}
+
+// renderSummary lists the day's completed tasks and added links.
+func (m *Journal) renderSummary() string {
+	var s strings.Builder
+	s.WriteString(journalHeadingStyle.Render("Summary") + "\n")
+
+	sections := []struct {
+		title string
+		items []storage.ActivityItem
+		empty string
+	}{
+		{"Tasks completed", m.activity.CompletedTasks, "No tasks completed."},
+		{"Links added", m.activity.AddedLinks, "No links added."},
+	}
+	for _, section := range sections {
+		s.WriteString(fmt.Sprintf("\n%s (%d)\n", lipgloss.NewStyle().Bold(true).Render(section.title), len(section.items)))
+		if len(section.items) == 0 {
+			s.WriteString(gitDimStyle.Render("  "+section.empty) + "\n")
+		}
+		for _, item := range section.items {
+			line := "  • " + item.Title
+			if item.Detail != "" {
+				line += gitDimStyle.Render("  " + item.Detail)
+			}
+			if item.Project != "" {
+				line += gitDimStyle.Render("  [" + item.Project + "]")
+			}
+			s.WriteString(ansi.Truncate(line, max(m.view.Width, 20), "…") + "\n")
+		}
+	}
+	return strings.TrimSuffix(s.String(), "\n")
+}
+
+func (m *Journal) Update(msg tea.Msg) (Module, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.editor.SetWidth(m.width - 4)
+		m.editor.SetHeight(max(m.height-12, 3))
+		m.view.Width = m.width - 6
+		m.view.Height = max(m.height-12, 3)
+		m.refreshView()
+		return m, nil
+	case journalEditedMsg:
+		m.finishExternalEdit(msg)
+		return m, nil
+	}
+
+	if m.editing {
+		return m.updateEditing(msg)
+	}
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	today := startOfDay(m.now())
+	switch keyMsg.String() {
+	case "left", "h":
+		m.showDay(m.day.AddDate(0, 0, -1))
+	case "right", "l":
+		if m.day.Before(today) {
+			m.showDay(m.day.AddDate(0, 0, 1))
+		}
+	case "t":
+		m.showDay(today)
+	case "[":
+		if day, ok := m.adjacentEntry(-1); ok {
+			m.showDay(day)
+		}
+	case "]":
+		if day, ok := m.adjacentEntry(1); ok {
+			m.showDay(day)
+		}
+	case "down", "j":
+		m.view.LineDown(1)
+	case "up", "k":
+		m.view.LineUp(1)
+	case "ctrl+d", "pgdown":
+		m.view.HalfPageDown()
+	case "ctrl+u", "pgup":
+		m.view.HalfPageUp()
+	case "enter", "e":
+		m.editing = true
+		m.editor.SetValue(m.entry.Content)
+		return m, m.editor.Focus()
+	case "E":
+		return m, m.openExternalEditor()
+	}
+	return m, nil
+}
+
+// adjacentEntry finds the closest day before (direction -1) or after
+// (direction 1) the displayed one that has an entry.
+func (m *Journal) adjacentEntry(direction int) (time.Time, bool) {
+	current := m.day.Format(storage.DateLayout)
+	i := sort.SearchStrings(m.days, current)
+	if direction < 0 {
+		i--
+	} else if i < len(m.days) && m.days[i] == current {
+		i++
+	}
+	if i < 0 || i >= len(m.days) {
+		return time.Time{}, false
+	}
+	day, err := time.ParseInLocation(storage.DateLayout, m.days[i], time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return day, true
+}
+
+func (m *Journal) updateEditing(msg tea.Msg) (Module, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "ctrl+s":
+			m.save(m.day, m.editor.Value())
+			m.editor.Blur()
+			m.editing = false
+			return m, nil
+		case "esc":
+			m.editor.Blur()
+			m.editing = false
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.editor, cmd = m.editor.Update(msg)
+	return m, cmd
+}
+
+func (m *Journal) save(day time.Time, content string) {
+	entry := storage.JournalEntry{WorkspaceID: m.workspaceID, Day: day, Content: strings.TrimRight(content, "\n")}
+	if err := storage.SaveJournalEntry(m.db, entry); err != nil {
+		log.Printf("Error saving journal entry: %v", err)
+		return
+	}
+	m.showDay(m.day)
+}
+
+// openExternalEditor suspends the dashboard while $EDITOR (or vi) edits the
+// entry.
+func (m *Journal) openExternalEditor() tea.Cmd {
+	day := m.day
+	pattern := "dashboard-journal-" + day.Format(storage.DateLayout) + "-*.md"
+	return editExternally(m.entry.Content, pattern, func(content string, err error) tea.Msg {
+		return journalEditedMsg{day: day, content: content, err: err}
+	})
+}
+
+func (m *Journal) finishExternalEdit(msg journalEditedMsg) {
+	if msg.err != nil {
+		log.Printf("Error editing journal entry: %v", msg.err)
+		return
+	}
+	m.save(msg.day, msg.content)
+}
+
+func (m *Journal) View() string {
+	if m.width == 0 {
+		return "loading..."
+	}
+
+	title := m.day.Format("Monday, January 2 2006")
+	today := startOfDay(m.now())
+	switch days := int(today.Sub(m.day).Hours()/24 + 0.5); days {
+	case 0:
+		title += gitDimStyle.Render("  today")
+	case 1:
+		title += gitDimStyle.Render("  yesterday")
+	default:
+		title += gitDimStyle.Render(fmt.Sprintf("  %d days ago", days))
+	}
+	header := journalHeadingStyle.Render("Journal") + "  " + lipgloss.NewStyle().Bold(true).Render(title)
+
+	if m.editing {
+		return lipgloss.JoinVertical(lipgloss.Left,
+			header, "",
+			m.editor.View(),
+			gitDimStyle.Render("(ctrl+s) save, (esc) cancel"))
+	}
+
+	pane := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("63")).
+		Padding(0, 1).
+		Width(m.width - 4).
+		Height(max(m.height-10, 3)).
+		Render(m.view.View())
+
+	footer := "(h/l) previous/next day, ([/]) previous/next entry, (t)oday, (e)dit, (E) edit in $EDITOR"
+	return lipgloss.JoinVertical(lipgloss.Left, header, pane, gitDimStyle.Render(footer))
+}
@@ -0,0 +1,67 @@
+package module
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ceinl/Go-dashboard/internal/storage"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/google/uuid"
+)
+
+func TestJournalNavigationAndSummary(t *testing.T) {
+	db, projectID := setupTestDB(t)
+	project, err := storage.GetProject(db, projectID)
+	if err != nil {
+		t.Fatalf("failed to get project: %v", err)
+	}
+	if err := storage.CreateLink(db, storage.Link{ID: uuid.New().String(), ProjectID: projectID, Title: "Retro board", URL: "https://example.com/retro"}); err != nil {
+		t.Fatalf("failed to create link: %v", err)
+	}
+	today := startOfDay(time.Now())
+	earlier := today.AddDate(0, 0, -5)
+	if err := storage.SaveJournalEntry(db, storage.JournalEntry{WorkspaceID: project.WorkspaceID, Day: earlier, Content: "Planning day"}); err != nil {
+		t.Fatalf("failed to save entry: %v", err)
+	}
+
+	m := NewJournal(db, project.WorkspaceID)
+	m.Init()
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	journal := m.(*Journal)
+	if !journal.day.Equal(today) {
+		t.Fatalf("expected today's entry to open, got %v", journal.day)
+	}
+	if view := ansi.Strip(m.View()); !strings.Contains(view, "Retro board") || !strings.Contains(view, "today") {
+		t.Errorf("expected today's link in the summary, got:\n%s", view)
+	}
+
+	m = typeKeys(m, "e")
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("Wrote the journal")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if journal.entry.Content != "Wrote the journal" || len(journal.days) != 2 {
+		t.Errorf("expected today's entry to be saved, got %+v and %v", journal.entry, journal.days)
+	}
+
+	m = typeKeys(m, "l")
+	if !journal.day.Equal(today) {
+		t.Error("expected not to move past today")
+	}
+	m = typeKeys(m, "[")
+	if !journal.day.Equal(earlier) || journal.entry.Content != "Planning day" {
+		t.Errorf("expected to jump to the previous entry, got %v", journal.day)
+	}
+	m = typeKeys(m, "h")
+	if !journal.day.Equal(earlier.AddDate(0, 0, -1)) || journal.entry.Content != "" {
+		t.Errorf("expected the day before, got %v", journal.day)
+	}
+	m = typeKeys(m, "]", "]")
+	if !journal.day.Equal(today) {
+		t.Errorf("expected to jump forward to today, got %v", journal.day)
+	}
+	m = typeKeys(m, "h", "h", "t")
+	if !journal.day.Equal(today) {
+		t.Errorf("expected t to go back to today, got %v", journal.day)
+	}
+}
@@ -2,8 +2,11 @@ package module
 
 import (
 	"database/sql"
+	"fmt"
 	"log"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Ceinl/Go-dashboard/internal/storage"
 	"github.com/charmbracelet/bubbles/textinput"
@@ -15,21 +18,74 @@ import (
 const (
 	ToDo       = "To Do"
 	InProgress = "In Progress"
-	Done       = "Done"
+	Done       = storage.DoneStatus
 )
 
 var columns = []string{ToDo, InProgress, Done}
 
 type Kanban struct {
-	db        *sql.DB
-	projectID string
-	tasks     map[string][]storage.Task
-	input     textinput.Model
-	editing   bool
-	cursorCol int
-	cursorRow int
-	width     int
-	height    int
+	db         *sql.DB
+	projectID  string
+	tasks      map[string][]storage.Task
+	input      textinput.Model
+	editing    bool
+	settingDue bool // editing the due date of the selected task instead of adding one
+	repeating  bool // editing the schedule of the selected task instead of adding one
+	labeling   bool // editing the labels of the selected task instead of adding one
+	assigning  bool // editing the assignee of the selected task instead of adding one
+	limiting   bool // editing the WIP limit of the cursor's column instead of adding a task
+	filter     itemFilter
+	cursorCol  int
+	cursorRow  int             // index into the filtered column of the cursor's lane, see visibleTasks
+	cursorLane int             // index into lanes when the board is grouped
+	grouping   int             // field the board is grouped into swimlanes by, see groupNone
+	collapsed  map[string]bool // collapsed lanes, see collapseKey
+	width      int
+	height     int
+
+	showHistory bool
+	history     []storage.Event
+
+	focusCounts map[string]int // completed focus sessions per task ID
+
+	tracked        map[string]time.Duration // tracked time per task ID
+	trackingTaskID string                   // task with the running time entry, if it is on this board
+
+	detail         bool         // the card detail of detailTask is open
+	detailTask     storage.Task // task shown in the card detail
+	checklist      []storage.ChecklistItem
+	checkCursor    int
+	checklistInput int // what the text input edits in the card detail, see checklistInputAdd
+	progress       map[string]storage.ChecklistProgress
+
+	blockers     map[string][]string // titles of unfinished blockers per task ID
+	deps         bool                // the dependency view of depsTask is open
+	depsTask     storage.Task
+	depsBlockers []storage.LinkedTask
+	depsCursor   int
+	picking      bool // choosing a blocker for depsTask
+	pickTasks    []storage.LinkedTask
+	pickCursor   int
+
+	limits       map[string]int // WIP limit per column that has one
+	strictLimits bool           // refuse moves over a WIP limit instead of asking to confirm
+
+	marked          map[string]bool // IDs of the tasks marked for the next move or copy
+	transferring    int             // the project picker is open to move or copy tasks, see transferMove
+	transferTargets []transferTarget
+	transferCursor  int
+
+	metrics      bool // the flow metrics view is open
+	metricsWeeks int
+	transitions  []storage.TaskTransition
+
+	message     string // warning shown under the board until the next key
+	forceMoveID string // blocked task whose move, or action over a WIP limit, was just refused once
+
+	linking          int    // step of linking the selected task to a branch, see linkPath
+	linkRepoPath     string // repository entered in the first linking step
+	branches         map[string]branchState
+	branchGeneration int
 }
 
 func NewKanban(db *sql.DB, projectID string) Module {
@@ -43,22 +99,71 @@ func NewKanban(db *sql.DB, projectID string) Module {
 		projectID: projectID,
 		tasks:     make(map[string][]storage.Task),
 		input:     ti,
+		filter:    newItemFilter(),
+		collapsed: make(map[string]bool),
+		marked:    make(map[string]bool),
 	}
 }
 
 func (m *Kanban) Init() tea.Cmd {
 	m.loadTasks()
-	return nil
+	return m.refreshBranches()
 }
 
 func (m *Kanban) Update(msg tea.Msg) (Module, tea.Cmd) {
+	if _, ok := msg.(TimeTrackingMsg); ok {
+		m.loadTracking()
+		return m, nil
+	}
+	if _, ok := msg.(TasksChangedMsg); ok {
+		m.reloadTasks()
+		return m, nil
+	}
+	if cmd, ok := m.updateBranches(msg); ok {
+		return m, cmd
+	}
 	if m.editing {
 		return m.updateEditing(msg)
 	}
+	if m.detail {
+		return m.updateDetail(msg)
+	}
+	if m.deps {
+		return m.updateDependencies(msg)
+	}
+	if m.metrics {
+		return m.updateMetrics(msg)
+	}
+	if m.transferring != transferNone {
+		return m.updateTransfer(msg)
+	}
+	if m.filter.Typing() {
+		return m.updateFiltering(msg)
+	}
 
 	return m.updateBrowsing(msg)
 }
 
+// CapturingInput reports whether the module needs every key press.
+func (m *Kanban) CapturingInput() bool {
+	return m.editing || m.picking || m.transferring != transferNone || m.filter.Typing()
+}
+
+func (m *Kanban) Filters() {}
+
+func (m *Kanban) updateFiltering(msg tea.Msg) (Module, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m.updateBrowsing(msg)
+	}
+
+	cmd, changed := m.filter.Update(keyMsg)
+	if changed {
+		m.cursorRow = 0
+	}
+	return m, cmd
+}
+
 func (m *Kanban) updateEditing(msg tea.Msg) (Module, tea.Cmd) {
 	var cmd tea.Cmd
 
@@ -66,7 +171,32 @@ func (m *Kanban) updateEditing(msg tea.Msg) (Module, tea.Cmd) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "enter":
-			if m.projectID != "" {
+			if m.linking != linkNone {
+				cmd, done := m.submitLink()
+				if done {
+					m.stopEditing()
+				}
+				return m, cmd
+			}
+			if m.checklistInput != checklistInputNone {
+				m.submitChecklistItem(m.input.Value())
+				m.stopEditing()
+				return m, nil
+			}
+			if m.repeating {
+				cmd := m.setRecurrence(m.input.Value())
+				m.stopEditing()
+				return m, cmd
+			}
+			if m.settingDue {
+				m.setDueDate(m.input.Value())
+			} else if m.labeling {
+				m.setLabels(m.input.Value())
+			} else if m.assigning {
+				m.setAssignee(m.input.Value())
+			} else if m.limiting {
+				m.setLimit(m.input.Value())
+			} else if m.projectID != "" {
 				newTask := storage.Task{
 					ID:        uuid.New().String(),
 					ProjectID: m.projectID,
@@ -79,12 +209,10 @@ func (m *Kanban) updateEditing(msg tea.Msg) (Module, tea.Cmd) {
 					m.tasks[newTask.Status] = append(m.tasks[newTask.Status], newTask)
 				}
 			}
-			m.input.Reset()
-			m.editing = false
+			m.stopEditing()
 			return m, nil
 		case "esc":
-			m.input.Reset()
-			m.editing = false
+			m.stopEditing()
 			return m, nil
 		}
 	}
@@ -93,12 +221,27 @@ func (m *Kanban) updateEditing(msg tea.Msg) (Module, tea.Cmd) {
 	return m, cmd
 }
 
+func (m *Kanban) stopEditing() {
+	m.input.Reset()
+	m.input.Placeholder = "New Task"
+	m.editing = false
+	m.settingDue = false
+	m.repeating = false
+	m.labeling = false
+	m.assigning = false
+	m.limiting = false
+	m.checklistInput = checklistInputNone
+	m.linking = linkNone
+}
+
 func (m *Kanban) updateBrowsing(msg tea.Msg) (Module, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
 	case tea.KeyMsg:
+		confirmed := m.forceMoveID
+		m.forceMoveID, m.message = "", ""
 		switch msg.String() {
 		case "left", "h":
 			if m.cursorCol > 0 {
@@ -113,21 +256,137 @@ func (m *Kanban) updateBrowsing(msg tea.Msg) (Module, tea.Cmd) {
 		case "up", "k":
 			if m.cursorRow > 0 {
 				m.cursorRow--
+			} else {
+				m.moveLane(-1, true)
 			}
 		case "down", "j":
-			if m.cursorRow < len(m.tasks[columns[m.cursorCol]])-1 {
+			if m.cursorRow < len(m.visibleTasks(columns[m.cursorCol]))-1 {
 				m.cursorRow++
+			} else {
+				m.moveLane(1, false)
+			}
+		case "[":
+			m.moveLane(-1, false)
+		case "]":
+			m.moveLane(1, false)
+		case "w":
+			m.editing = true
+			m.limiting = true
+			m.input.Placeholder = fmt.Sprintf("WIP limit for %s (empty removes it)", columns[m.cursorCol])
+			if limit, ok := m.limits[columns[m.cursorCol]]; ok {
+				m.input.SetValue(strconv.Itoa(limit))
+			}
+			m.input.Focus()
+			return m, textinput.Blink
+		case "W":
+			m.toggleStrictLimits()
+		case "m":
+			m.openMetrics()
+		case "x":
+			m.toggleMark()
+		case "P":
+			return m, m.startTransfer(transferMove)
+		case "c":
+			return m, m.startTransfer(transferCopy)
+		case "g":
+			m.cycleGrouping()
+		case "z":
+			m.toggleLane()
+		case "p":
+			m.cyclePriority()
+		case "#":
+			if task, ok := m.selectedTask(); ok {
+				m.editing = true
+				m.labeling = true
+				m.input.Placeholder = "Labels, comma-separated (empty clears)"
+				m.input.SetValue(strings.Join(task.Labels, ", "))
+				m.input.Focus()
+				return m, textinput.Blink
+			}
+		case "@":
+			if task, ok := m.selectedTask(); ok {
+				m.editing = true
+				m.assigning = true
+				m.input.Placeholder = "Assignee (empty clears)"
+				m.input.SetValue(task.Assignee)
+				m.input.Focus()
+				return m, textinput.Blink
 			}
 		case "H":
-			m.moveTask(-1)
+			return m, m.moveTask(-1, confirmed)
 		case "L":
-			m.moveTask(1)
+			return m, m.moveTask(1, confirmed)
 		case "a":
+			colName := columns[m.cursorCol]
+			if !m.admit(m.overLimit(colName, 1), m.strictLimits, "add to "+colName, confirmed, "Press a again to add a task") {
+				return m, nil
+			}
 			m.editing = true
 			m.input.Focus()
 			return m, textinput.Blink
 		case "d":
+			if task, ok := m.selectedTask(); ok && task.ID == m.trackingTaskID {
+				// Don't leave the status bar counting time on a deleted task.
+				cmd := m.toggleTracking(task)
+				m.deleteTask()
+				return m, cmd
+			}
 			m.deleteTask()
+		case "t":
+			if task, ok := m.selectedTask(); ok {
+				m.editing = true
+				m.settingDue = true
+				m.input.Placeholder = "Due date: YYYY-MM-DD, today, tomorrow or +3d (empty clears)"
+				m.input.SetValue(task.DueDate)
+				m.input.Focus()
+				return m, textinput.Blink
+			}
+		case "R":
+			if task, ok := m.selectedTask(); ok {
+				m.editing = true
+				m.repeating = true
+				m.input.Placeholder = "Repeat: daily, weekly mon,thu, monthly 15 or an RRULE (empty stops)"
+				m.input.SetValue(task.Recurrence)
+				m.input.Focus()
+				return m, textinput.Blink
+			}
+		case "/":
+			m.cursorRow = 0
+			return m, m.filter.Start()
+		case "esc":
+			if m.filter.Active() {
+				m.filter.Reset()
+				m.cursorRow = 0
+			}
+		case "i":
+			m.showHistory = !m.showHistory
+		case "f":
+			if task, ok := m.selectedTask(); ok {
+				return m, func() tea.Msg { return StartFocusMsg{Task: task} }
+			}
+		case "s":
+			if task, ok := m.selectedTask(); ok {
+				return m, m.toggleTracking(task)
+			}
+		case "b":
+			if task, ok := m.selectedTask(); ok {
+				return m, m.startLinking(task)
+			}
+		case "M":
+			return m, m.moveMergedToDone(confirmed)
+		case "enter":
+			if task, ok := m.selectedTask(); ok {
+				m.openDetail(task)
+			}
+		case "D":
+			if task, ok := m.selectedTask(); ok {
+				m.openDependencies(task)
+			}
+		}
+
+		m.clampLane()
+		if m.showHistory {
+			m.loadHistory()
 		}
 	}
 	return m, nil
@@ -138,17 +397,62 @@ func (m *Kanban) View() string {
 		return "loading..."
 	}
 
-	var colViews []string
-	columnWidth := m.width / len(columns)
+	if m.editing {
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.input.View())
+	}
+	if m.detail {
+		return m.detailView()
+	}
+	if m.deps {
+		return m.dependencyView()
+	}
+	if m.metrics {
+		return m.metricsView()
+	}
+	if m.transferring != transferNone {
+		return m.transferView()
+	}
+
+	boardWidth := m.width
+	if m.showHistory {
+		boardWidth = m.width * 2 / 3
+	}
+	var mainView string
+	if m.grouping == groupNone {
+		mainView = m.columnsView(boardWidth)
+	} else {
+		mainView = m.laneView(boardWidth)
+	}
+	if m.showHistory {
+		mainView = lipgloss.JoinHorizontal(lipgloss.Top, mainView, m.historyView(m.width-boardWidth))
+	}
+	help := "\n(a)dd, (d)elete, (enter) details, (D)ependencies, (h/j/k/l) navigate, (H/L) move task, (/) filter, (i) history, (m)etrics, (t) due date, (R)epeat, (p)riority, (#) labels, (@) assignee, (w)ip limit, (W) strict limits, (g)roup into swimlanes, (x) mark, (P) move to project, (c)opy to project, (f)ocus, (s)tart/stop tracking, (b)ranch"
+	if m.grouping != groupNone {
+		help += ", (z) collapse lane, ([/]) previous/next lane"
+	}
+	helpView := lipgloss.NewStyle().Width(m.width).Align(lipgloss.Center).Render(help)
 
+	views := []string{mainView}
+	if filterView := m.filter.View(); filterView != "" {
+		views = append(views, filterView)
+	}
+	if m.message != "" {
+		views = append(views, blockedStyle.Width(m.width).Align(lipgloss.Center).Render(m.message))
+	}
+	if banner := m.mergedBanner(); banner != "" {
+		views = append(views, banner)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, append(views, helpView)...)
+}
+
+// columnsView renders the board as three columns.
+func (m *Kanban) columnsView(boardWidth int) string {
+	var colViews []string
+	columnWidth := boardWidth / len(columns)
 	for i, colName := range columns {
 		var tasksInCol []string
-		for j, task := range m.tasks[colName] {
-			taskStyle := lipgloss.NewStyle().Padding(0, 1).Width(columnWidth - 4)
-			if i == m.cursorCol && j == m.cursorRow {
-				taskStyle = taskStyle.Background(lipgloss.Color("57"))
-			}
-			tasksInCol = append(tasksInCol, taskStyle.Render(task.Title))
+		for j, task := range m.visibleTasks(colName) {
+			tasksInCol = append(tasksInCol, m.cardView(task, i == m.cursorCol && j == m.cursorRow, columnWidth))
 		}
 
 		colStyle := lipgloss.NewStyle().
@@ -156,26 +460,57 @@ func (m *Kanban) View() string {
 			Padding(1).
 			Width(columnWidth - 2).
 			Height(m.height - 10)
-		if i == m.cursorCol {
+		titleStyle := lipgloss.NewStyle().Bold(true)
+		title, over := m.columnTitle(colName)
+		if over {
+			colStyle = colStyle.BorderForeground(overLimitColor)
+			titleStyle = titleStyle.Foreground(overLimitColor)
+		} else if i == m.cursorCol {
 			colStyle = colStyle.BorderForeground(lipgloss.Color("57"))
 		}
 
 		colViews = append(colViews, colStyle.Render(
 			lipgloss.JoinVertical(lipgloss.Left,
-				lipgloss.NewStyle().Bold(true).Render(colName),
+				titleStyle.Render(title),
 				strings.Join(tasksInCol, "\n"),
 			),
 		))
 	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, colViews...)
+}
 
-	if m.editing {
-		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.input.View())
+// cardView renders a task card for a column of the given width.
+func (m *Kanban) cardView(task storage.Task, selected bool, width int) string {
+	taskStyle := lipgloss.NewStyle().Padding(0, 1).Width(width - 4)
+	textStyle := lipgloss.NewStyle()
+	if selected {
+		taskStyle = taskStyle.Background(lipgloss.Color("57"))
+		textStyle = textStyle.Background(lipgloss.Color("57"))
 	}
-
-	mainView := lipgloss.JoinHorizontal(lipgloss.Top, colViews...)
-	helpView := lipgloss.NewStyle().Width(m.width).Align(lipgloss.Center).Render("\n(a)dd, (d)elete, (h/j/k/l) navigate, (H/L) move task")
-
-	return lipgloss.JoinVertical(lipgloss.Left, mainView, helpView)
+	card := m.markBadge(task, textStyle) + m.filter.Highlight(task.Title, textStyle)
+	card += m.checklistBadge(task, textStyle)
+	card += m.blockedBadge(task, textStyle)
+	card += m.recurrenceBadge(task, textStyle)
+	card += m.attributeBadges(task, textStyle)
+	if n := m.focusCounts[task.ID]; n > 0 {
+		card += textStyle.Render(" ") + textStyle.Foreground(lipgloss.Color("212")).Render(fmt.Sprintf("◷%d", n))
+	}
+	if task.ID == m.trackingTaskID {
+		card += textStyle.Render(" ") + textStyle.Foreground(lipgloss.Color("42")).Render("● "+formatTrackedTime(m.tracked[task.ID]))
+	} else if d := m.tracked[task.ID]; d > 0 {
+		card += textStyle.Render(" ") + textStyle.Foreground(lipgloss.Color("240")).Render(formatTrackedTime(d))
+	}
+	if task.DueDate != "" {
+		dueStyle := textStyle.Foreground(lipgloss.Color("240"))
+		if task.Overdue(time.Now()) {
+			dueStyle = textStyle.Foreground(lipgloss.Color("196"))
+		}
+		card += textStyle.Render(" ") + dueStyle.Render("due "+task.DueDate)
+	}
+	if task.Branch != "" {
+		card += "\n" + m.branchBadge(task, textStyle)
+	}
+	return taskStyle.Render(card)
 }
 
 func (m *Kanban) loadTasks() {
@@ -196,25 +531,273 @@ func (m *Kanban) loadTasks() {
 	for _, task := range tasks {
 		m.tasks[task.Status] = append(m.tasks[task.Status], task)
 	}
+
+	counts, err := storage.GetFocusSessionCounts(m.db, m.projectID)
+	if err != nil {
+		log.Printf("Error loading focus sessions: %v", err)
+	}
+	m.focusCounts = counts
+
+	progress, err := storage.GetChecklistProgress(m.db, m.projectID)
+	if err != nil {
+		log.Printf("Error loading checklists: %v", err)
+	}
+	m.progress = progress
+
+	blockers, err := storage.GetOpenBlockers(m.db, m.projectID)
+	if err != nil {
+		log.Printf("Error loading dependencies: %v", err)
+	}
+	m.blockers = blockers
+
+	m.loadLimits()
+	m.loadTracking()
 }
 
-func (m *Kanban) moveTask(direction int) {
-	currentColName := columns[m.cursorCol]
-	if len(m.tasks[currentColName]) == 0 {
+func (m *Kanban) loadTracking() {
+	tracked, err := storage.GetTrackedTimeForProject(m.db, m.projectID, time.Now())
+	if err != nil {
+		log.Printf("Error loading tracked time: %v", err)
+	}
+	m.tracked = tracked
+
+	m.trackingTaskID = ""
+	running, err := storage.GetRunningTimeEntry(m.db)
+	if err != nil {
+		log.Printf("Error loading running time entry: %v", err)
+	} else if running != nil {
+		m.trackingTaskID = running.TaskID
+	}
+}
+
+// toggleTracking starts tracking time on task, or stops it if task is the
+// one being tracked. The returned command tells the rest of the app.
+func (m *Kanban) toggleTracking(task storage.Task) tea.Cmd {
+	now := time.Now()
+	var err error
+	if task.ID == m.trackingTaskID {
+		err = storage.StopTimeEntry(m.db, now)
+	} else {
+		err = storage.StartTimeEntry(m.db, storage.TimeEntry{ID: uuid.New().String(), TaskID: task.ID, StartedAt: now})
+	}
+	if err != nil {
+		log.Printf("Error updating time tracking: %v", err)
+	}
+	m.loadTracking()
+	return func() tea.Msg { return TimeTrackingMsg{} }
+}
+
+// formatTrackedTime formats a tracked duration for a card, e.g. "1h05m".
+func formatTrackedTime(d time.Duration) string {
+	d = d.Truncate(time.Minute)
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// setDueDate parses value and saves it as the selected task's due date.
+func (m *Kanban) setDueDate(value string) {
+	task, ok := m.selectedTask()
+	if !ok {
 		return
 	}
+	due, err := parseDueDate(value, time.Now())
+	if err != nil {
+		log.Printf("Error parsing due date: %v", err)
+		return
+	}
+	task.DueDate = due
+	if err := storage.UpdateTask(m.db, task); err != nil {
+		log.Printf("Error updating task: %v", err)
+		return
+	}
+	m.replaceTask(task)
+}
+
+// parseDueDate accepts an absolute date, "today", "tomorrow" or an offset in
+// days such as "+3d", and returns it in storage.DateLayout. An empty value
+// clears the due date.
+func parseDueDate(value string, today time.Time) (string, error) {
+	value = strings.TrimSpace(strings.ToLower(value))
+	switch {
+	case value == "":
+		return "", nil
+	case value == "today":
+		return today.Format(storage.DateLayout), nil
+	case value == "tomorrow":
+		return today.AddDate(0, 0, 1).Format(storage.DateLayout), nil
+	case strings.HasPrefix(value, "+") && strings.HasSuffix(value, "d"):
+		days, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(value, "+"), "d"))
+		if err != nil {
+			return "", fmt.Errorf("invalid day offset %q", value)
+		}
+		return today.AddDate(0, 0, days).Format(storage.DateLayout), nil
+	}
+
+	t, err := time.Parse(storage.DateLayout, value)
+	if err != nil {
+		return "", fmt.Errorf("invalid date %q", value)
+	}
+	return t.Format(storage.DateLayout), nil
+}
+
+// replaceTask swaps the in-memory copy of a task for an updated one.
+func (m *Kanban) replaceTask(task storage.Task) {
+	for _, colName := range columns {
+		for i, t := range m.tasks[colName] {
+			if t.ID == task.ID {
+				m.tasks[colName][i] = task
+				return
+			}
+		}
+	}
+}
+
+// loadHistory fetches the activity log of the selected task.
+func (m *Kanban) loadHistory() {
+	task, ok := m.selectedTask()
+	if !ok {
+		m.history = nil
+		return
+	}
+	history, err := storage.GetEventsForEntity(m.db, storage.EntityTask, task.ID)
+	if err != nil {
+		log.Printf("Error loading task history: %v", err)
+		return
+	}
+	m.history = history
+}
+
+func (m *Kanban) historyView(width int) string {
+	timeStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+
+	var lines []string
+	if task, ok := m.selectedTask(); ok {
+		lines = append(lines, lipgloss.NewStyle().Bold(true).Render("History: "+task.Title), "")
+		for _, e := range m.history {
+			var what string
+			switch e.Action {
+			case storage.ActionCreate:
+				what = "created in " + e.NewValue
+			case storage.ActionMove:
+				what = e.OldValue + " → " + e.NewValue
+			case storage.ActionTransferIn:
+				what = "moved from project " + e.OldValue
+			case storage.ActionTransferOut:
+				continue
+			default:
+				what = "edited"
+			}
+			lines = append(lines, timeStyle.Render(e.CreatedAt.Local().Format("2006-01-02 15:04"))+"  "+what)
+		}
+		if len(m.history) == 0 {
+			lines = append(lines, "No history recorded.")
+		}
+	} else {
+		lines = append(lines, "No task selected.")
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		Padding(1).
+		Width(width - 2).
+		Height(m.height - 10).
+		Render(strings.Join(lines, "\n"))
+}
+
+// visibleTasks returns the tasks of a column the cursor moves through: those
+// that pass the current filter and, on a grouped board, are in the cursor's
+// lane. A collapsed lane has none.
+func (m *Kanban) visibleTasks(colName string) []storage.Task {
+	lanes := m.lanes()
+	if m.laneCollapsed(lanes, m.cursorLane) {
+		return nil
+	}
+	return m.cellTasks(lanes, m.cursorLane, colName)
+}
+
+// filteredTasks returns the tasks of a column that pass the current filter.
+func (m *Kanban) filteredTasks(colName string) []storage.Task {
+	if !m.filter.Active() {
+		return m.tasks[colName]
+	}
+	var visible []storage.Task
+	for _, task := range m.tasks[colName] {
+		if m.filter.Matches(task.Title, task.Description, strings.Join(task.Labels, " "), task.Assignee) {
+			visible = append(visible, task)
+		}
+	}
+	return visible
+}
+
+// selectedTask returns the task under the cursor, if any.
+func (m *Kanban) selectedTask() (storage.Task, bool) {
+	visible := m.visibleTasks(columns[m.cursorCol])
+	if m.cursorRow < 0 || m.cursorRow >= len(visible) {
+		return storage.Task{}, false
+	}
+	return visible[m.cursorRow], true
+}
+
+// removeTask drops a task from the in-memory column without touching the DB.
+func (m *Kanban) removeTask(colName, id string) {
+	for i, task := range m.tasks[colName] {
+		if task.ID == id {
+			m.tasks[colName] = append(m.tasks[colName][:i], m.tasks[colName][i+1:]...)
+			return
+		}
+	}
+}
+
+// moveTask moves the selected task to the next or previous column. Moving a
+// blocked task forward, or into a column at its WIP limit, is refused with a
+// warning, unless confirmed is its ID because the same move was just refused.
+// With strict WIP limits a move over the limit is always refused. Finishing a
+// recurring task may create its next occurrence.
+func (m *Kanban) moveTask(direction int, confirmed string) tea.Cmd {
+	currentColName := columns[m.cursorCol]
+	task, ok := m.selectedTask()
+	if !ok {
+		return nil
+	}
 
-	task := m.tasks[currentColName][m.cursorRow]
 	newColIndex := m.cursorCol + direction
 	if newColIndex < 0 || newColIndex >= len(columns) {
-		return
+		return nil
+	}
+
+	newColName := columns[newColIndex]
+	limit, atLimit := m.atLimit(newColName)
+	if atLimit && m.strictLimits {
+		m.message = fmt.Sprintf("%s is at its WIP limit of %d. Finish something there first.", newColName, limit)
+		return nil
+	}
+	if task.ID != confirmed {
+		var warnings []string
+		if atLimit {
+			warnings = append(warnings, fmt.Sprintf("%s is at its WIP limit of %d.", newColName, limit))
+		}
+		if direction > 0 {
+			if warning := m.blockedWarning(task); warning != "" {
+				warnings = append(warnings, warning)
+			}
+		}
+		if len(warnings) > 0 {
+			key := "L"
+			if direction < 0 {
+				key = "H"
+			}
+			m.message = strings.Join(warnings, " ") + " Press " + key + " again to move it anyway."
+			m.forceMoveID = task.ID
+			return nil
+		}
 	}
 
 	// Remove from old column
-	m.tasks[currentColName] = append(m.tasks[currentColName][:m.cursorRow], m.tasks[currentColName][m.cursorRow+1:]...)
+	m.removeTask(currentColName, task.ID)
 
 	// Add to new column
-	newColName := columns[newColIndex]
 	task.Status = newColName
 	m.tasks[newColName] = append(m.tasks[newColName], task)
 
@@ -226,22 +809,33 @@ func (m *Kanban) moveTask(direction int) {
 	}
 
 	m.cursorCol = newColIndex
-	m.cursorRow = len(m.tasks[newColName]) - 1
+	m.cursorRow = len(m.visibleTasks(newColName)) - 1
+	if newColName == Done {
+		return m.generateRecurring()
+	}
+	return nil
 }
 
 func (m *Kanban) deleteTask() {
 	currentColName := columns[m.cursorCol]
-	if len(m.tasks[currentColName]) == 0 {
+	task, ok := m.selectedTask()
+	if !ok {
 		return
 	}
 
-	task := m.tasks[currentColName][m.cursorRow]
 	if err := storage.DeleteTask(m.db, task.ID); err != nil {
 		log.Printf("Error deleting task: %v", err)
 	} else {
-		m.tasks[currentColName] = append(m.tasks[currentColName][:m.cursorRow], m.tasks[currentColName][m.cursorRow+1:]...)
-		if m.cursorRow >= len(m.tasks[currentColName]) && len(m.tasks[currentColName]) > 0 {
-			m.cursorRow = len(m.tasks[currentColName]) - 1
+		m.removeTask(currentColName, task.ID)
+		visible := m.visibleTasks(currentColName)
+		if m.cursorRow >= len(visible) && len(visible) > 0 {
+			m.cursorRow = len(visible) - 1
 		}
 	}
 }
+
+// FocusItem moves the cursor to the task with the given ID.
+func (m *Kanban) FocusItem(id string) bool {
+	m.filter.Reset()
+	return m.selectTask(id)
+}
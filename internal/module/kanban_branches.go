@@ -0,0 +1,221 @@
+package module
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Ceinl/Go-dashboard/internal/storage"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Steps of linking a task to a branch: the repository path is asked for
+// first, then the branch name.
+const (
+	linkNone = iota
+	linkPath
+	linkBranch
+)
+
+// branchStatesMsg carries the state of every linked branch on a board, keyed
+// by task ID.
+type branchStatesMsg struct {
+	owner      *Kanban
+	generation int
+	states     map[string]branchState
+}
+
+type branchRefreshMsg struct {
+	owner      *Kanban
+	generation int
+}
+
+// refreshBranches reads the state of every linked branch in the background.
+// The board keeps refreshing every gitRefreshInterval.
+func (m *Kanban) refreshBranches() tea.Cmd {
+	m.branchGeneration++
+	owner, generation := m, m.branchGeneration
+
+	links := make(map[string]storage.Task)
+	for _, tasks := range m.tasks {
+		for _, task := range tasks {
+			if task.Branch != "" {
+				links[task.ID] = task
+			}
+		}
+	}
+	if len(links) == 0 {
+		m.branches = nil
+		return nil
+	}
+
+	return func() tea.Msg {
+		states := make(map[string]branchState, len(links))
+		for id, task := range links {
+			states[id] = readBranchState(task.RepoPath, task.Branch, task.BranchBase)
+		}
+		return branchStatesMsg{owner: owner, generation: generation, states: states}
+	}
+}
+
+// updateBranches handles the branch refresh messages. It reports whether msg
+// was one of them.
+func (m *Kanban) updateBranches(msg tea.Msg) (tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case branchStatesMsg:
+		if msg.owner == m && msg.generation == m.branchGeneration {
+			m.branches = msg.states
+			return tea.Tick(gitRefreshInterval, func(time.Time) tea.Msg {
+				return branchRefreshMsg{owner: msg.owner, generation: msg.generation}
+			}), true
+		}
+		return nil, true
+	case branchRefreshMsg:
+		if msg.owner == m && msg.generation == m.branchGeneration {
+			return m.refreshBranches(), true
+		}
+		return nil, true
+	}
+	return nil, false
+}
+
+// startLinking asks for the repository of the selected task, suggesting the
+// task's current one or else the project's first repository.
+func (m *Kanban) startLinking(task storage.Task) tea.Cmd {
+	path := task.RepoPath
+	if path == "" {
+		repos, err := storage.GetRepositoriesForProject(m.db, m.projectID)
+		if err != nil {
+			log.Printf("Error loading repositories: %v", err)
+		} else if len(repos) > 0 {
+			path = repos[0].Path
+		}
+	}
+
+	m.editing = true
+	m.linking = linkPath
+	m.input.Placeholder = "Repository path (empty unlinks the task)"
+	m.input.SetValue(path)
+	m.input.CursorEnd()
+	return m.input.Focus()
+}
+
+// submitLink handles enter while linking. It returns true once linking is
+// finished.
+func (m *Kanban) submitLink() (tea.Cmd, bool) {
+	task, ok := m.selectedTask()
+	if !ok {
+		return nil, true
+	}
+	value := strings.TrimSpace(m.input.Value())
+
+	if m.linking == linkPath && value != "" {
+		m.linkRepoPath = value
+		m.linking = linkBranch
+		m.input.Placeholder = "Branch name"
+		m.input.SetValue(task.Branch)
+		m.input.CursorEnd()
+		return nil, false
+	}
+
+	if m.linking == linkPath || value == "" {
+		task.RepoPath, task.Branch, task.BranchBase = "", "", ""
+	} else if task.RepoPath != m.linkRepoPath || task.Branch != value {
+		task.RepoPath, task.Branch = m.linkRepoPath, value
+		task.BranchBase = linkBase(task.RepoPath)
+	}
+	if err := storage.UpdateTask(m.db, task); err != nil {
+		log.Printf("Error linking task to branch: %v", err)
+		return nil, true
+	}
+	m.replaceTask(task)
+	return m.refreshBranches(), true
+}
+
+// linkBase returns the tip of the main branch of the repository at path, or
+// "" if it can't be read.
+func linkBase(path string) string {
+	dir := expandHome(path)
+	branch := mainBranch(dir)
+	if branch == "" {
+		return ""
+	}
+	tip, err := revParse(dir, branch)
+	if err != nil {
+		return ""
+	}
+	return tip
+}
+
+// branchBadge describes a linked branch on a card.
+func (m *Kanban) branchBadge(task storage.Task, base lipgloss.Style) string {
+	label := "⎇ " + task.Branch
+	state, ok := m.branches[task.ID]
+	switch {
+	case !ok:
+		return base.Foreground(lipgloss.Color("240")).Render(label)
+	case state.Err != nil:
+		return base.Foreground(lipgloss.Color("196")).Render(label + " (no repository)")
+	case !state.Exists:
+		return base.Foreground(lipgloss.Color("196")).Render(label + " (missing)")
+	case state.Merged:
+		return base.Foreground(lipgloss.Color("42")).Render(label + " ✓ merged")
+	case state.Unpushed > 0 && state.Upstream:
+		return base.Foreground(lipgloss.Color("214")).Render(fmt.Sprintf("%s ↑%d unpushed", label, state.Unpushed))
+	case state.Unpushed > 0:
+		return base.Foreground(lipgloss.Color("214")).Render(fmt.Sprintf("%s ↑%d not pushed", label, state.Unpushed))
+	}
+	return base.Foreground(lipgloss.Color("39")).Render(label)
+}
+
+// mergedTasks returns the tasks whose branch has been merged but which
+// aren't Done yet.
+func (m *Kanban) mergedTasks() []storage.Task {
+	var merged []storage.Task
+	for _, col := range columns {
+		if col == Done {
+			continue
+		}
+		for _, task := range m.tasks[col] {
+			if m.branches[task.ID].Merged {
+				merged = append(merged, task)
+			}
+		}
+	}
+	return merged
+}
+
+// moveMergedToDone moves every task with a merged branch to Done, within its
+// WIP limit; see moveTask for confirmed.
+func (m *Kanban) moveMergedToDone(confirmed string) tea.Cmd {
+	merged := m.mergedTasks()
+	if len(merged) == 0 || !m.admit(m.overLimit(Done, len(merged)), m.strictLimits, "move merged", confirmed, "Press M again to move them") {
+		return nil
+	}
+	for _, task := range merged {
+		task.Status = Done
+		if err := storage.UpdateTask(m.db, task); err != nil {
+			log.Printf("Error moving merged task to Done: %v", err)
+		}
+	}
+	m.loadTasks()
+	if m.cursorRow >= len(m.visibleTasks(columns[m.cursorCol])) {
+		m.cursorRow = 0
+	}
+	return m.generateRecurring()
+}
+
+// mergedBanner proposes moving merged tasks to Done.
+func (m *Kanban) mergedBanner() string {
+	merged := m.mergedTasks()
+	if len(merged) == 0 {
+		return ""
+	}
+	text := fmt.Sprintf("%q has been merged. Press M to move it to Done.", merged[0].Title)
+	if len(merged) > 1 {
+		text = fmt.Sprintf("%d tasks have merged branches. Press M to move them to Done.", len(merged))
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("42")).Width(m.width).Align(lipgloss.Center).Render(text)
+}
@@ -0,0 +1,290 @@
+package module
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/Ceinl/Go-dashboard/internal/storage"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/google/uuid"
+)
+
+// Uses of the text input while the card detail is open.
+const (
+	checklistInputNone = iota
+	checklistInputAdd
+	checklistInputEdit
+)
+
+// openDetail shows the card detail of task with its checklist.
+func (m *Kanban) openDetail(task storage.Task) {
+	m.detail = true
+	m.detailTask = task
+	m.checkCursor = 0
+	m.loadChecklist()
+}
+
+func (m *Kanban) loadChecklist() {
+	items, err := storage.GetChecklist(m.db, m.detailTask.ID)
+	if err != nil {
+		log.Printf("Error loading checklist: %v", err)
+		return
+	}
+	m.checklist = items
+	if m.checkCursor >= len(m.checklist) {
+		m.checkCursor = max(len(m.checklist)-1, 0)
+	}
+}
+
+func (m *Kanban) selectedChecklistItem() (storage.ChecklistItem, bool) {
+	if m.checkCursor < 0 || m.checkCursor >= len(m.checklist) {
+		return storage.ChecklistItem{}, false
+	}
+	return m.checklist[m.checkCursor], true
+}
+
+func (m *Kanban) updateDetail(msg tea.Msg) (Module, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m.updateBrowsing(msg)
+	}
+
+	m.message = ""
+	switch keyMsg.String() {
+	case "esc", "q":
+		m.detail = false
+		m.loadTasks()
+	case "up", "k":
+		if m.checkCursor > 0 {
+			m.checkCursor--
+		}
+	case "down", "j":
+		if m.checkCursor < len(m.checklist)-1 {
+			m.checkCursor++
+		}
+	case " ", "x":
+		return m, m.toggleChecklistItem()
+	case "a":
+		return m, m.startChecklistInput(checklistInputAdd, "")
+	case "e", "enter":
+		if item, ok := m.selectedChecklistItem(); ok {
+			return m, m.startChecklistInput(checklistInputEdit, item.Text)
+		}
+	case "d":
+		if item, ok := m.selectedChecklistItem(); ok {
+			if err := storage.DeleteChecklistItem(m.db, item.ID); err != nil {
+				log.Printf("Error deleting checklist item: %v", err)
+			}
+			m.loadChecklist()
+		}
+	case "K":
+		m.moveChecklistItem(-1)
+	case "J":
+		m.moveChecklistItem(1)
+	case "A":
+		m.detailTask.AutoDone = !m.detailTask.AutoDone
+		if err := storage.UpdateTask(m.db, m.detailTask); err != nil {
+			log.Printf("Error updating task: %v", err)
+		}
+		m.replaceTask(m.detailTask)
+		return m, m.autoMoveToDone()
+	}
+	return m, nil
+}
+
+func (m *Kanban) startChecklistInput(mode int, value string) tea.Cmd {
+	m.editing = true
+	m.checklistInput = mode
+	m.input.Placeholder = "Checklist item"
+	m.input.SetValue(value)
+	m.input.CursorEnd()
+	return m.input.Focus()
+}
+
+// submitChecklistItem adds or renames an item with the typed text.
+func (m *Kanban) submitChecklistItem(text string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+	if m.checklistInput == checklistInputEdit {
+		item, ok := m.selectedChecklistItem()
+		if !ok {
+			return
+		}
+		item.Text = text
+		if err := storage.UpdateChecklistItem(m.db, item); err != nil {
+			log.Printf("Error updating checklist item: %v", err)
+		}
+		m.loadChecklist()
+		return
+	}
+
+	item := storage.ChecklistItem{ID: uuid.New().String(), TaskID: m.detailTask.ID, Text: text}
+	if err := storage.CreateChecklistItem(m.db, item); err != nil {
+		log.Printf("Error creating checklist item: %v", err)
+		return
+	}
+	m.loadChecklist()
+	m.checkCursor = len(m.checklist) - 1
+}
+
+func (m *Kanban) toggleChecklistItem() tea.Cmd {
+	item, ok := m.selectedChecklistItem()
+	if !ok {
+		return nil
+	}
+	item.Done = !item.Done
+	if err := storage.UpdateChecklistItem(m.db, item); err != nil {
+		log.Printf("Error updating checklist item: %v", err)
+		return nil
+	}
+	m.loadChecklist()
+	return m.autoMoveToDone()
+}
+
+// moveChecklistItem swaps the selected item with its neighbour.
+func (m *Kanban) moveChecklistItem(direction int) {
+	i, j := m.checkCursor, m.checkCursor+direction
+	if i < 0 || i >= len(m.checklist) || j < 0 || j >= len(m.checklist) {
+		return
+	}
+	m.checklist[i], m.checklist[j] = m.checklist[j], m.checklist[i]
+	ids := make([]string, len(m.checklist))
+	for k, item := range m.checklist {
+		ids[k] = item.ID
+	}
+	if err := storage.ReorderChecklist(m.db, ids); err != nil {
+		log.Printf("Error reordering checklist: %v", err)
+	}
+	m.checkCursor = j
+	m.loadChecklist()
+}
+
+// checklistProgress counts the checked items of the open checklist.
+func (m *Kanban) checklistProgress() storage.ChecklistProgress {
+	progress := storage.ChecklistProgress{Total: len(m.checklist)}
+	for _, item := range m.checklist {
+		if item.Done {
+			progress.Done++
+		}
+	}
+	return progress
+}
+
+// autoMoveToDone moves the open task to Done when it asks for that and its
+// whole checklist is checked. A task isn't moved over the WIP limit of Done
+// by itself, it has to be moved by hand.
+func (m *Kanban) autoMoveToDone() tea.Cmd {
+	task := m.detailTask
+	if !task.AutoDone || task.Status == Done || !m.checklistProgress().Complete() {
+		return nil
+	}
+	if over := m.overLimit(Done, 1); over != "" {
+		m.message = over + fmt.Sprintf(" %q stays in %s.", task.Title, task.Status)
+		return nil
+	}
+	task.Status = Done
+	if err := storage.UpdateTask(m.db, task); err != nil {
+		log.Printf("Error moving task to Done: %v", err)
+		return nil
+	}
+	m.detailTask = task
+	m.loadTasks()
+	m.selectTask(task.ID)
+	return m.generateRecurring()
+}
+
+// selectTask moves the board cursor to a task that passes the filter,
+// expanding its lane if it is collapsed.
+func (m *Kanban) selectTask(id string) bool {
+	lanes := m.lanes()
+	for l := 0; l < max(len(lanes), 1); l++ {
+		for i, colName := range columns {
+			for j, task := range m.cellTasks(lanes, l, colName) {
+				if task.ID == id {
+					if m.laneCollapsed(lanes, l) {
+						delete(m.collapsed, m.collapseKey(lanes[l]))
+					}
+					m.cursorLane, m.cursorCol, m.cursorRow = l, i, j
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// checklistBadge shows checklist progress on a card, e.g. "☑ 2/5".
+func (m *Kanban) checklistBadge(task storage.Task, base lipgloss.Style) string {
+	progress, ok := m.progress[task.ID]
+	if !ok {
+		return ""
+	}
+	color := lipgloss.Color("240")
+	if progress.Complete() {
+		color = lipgloss.Color("42")
+	}
+	return base.Render(" ") + base.Foreground(color).Render(fmt.Sprintf("☑ %d/%d", progress.Done, progress.Total))
+}
+
+func (m *Kanban) detailView() string {
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	width := min(max(m.width/2, 40), m.width-4)
+	task := m.detailTask
+
+	lines := []string{
+		lipgloss.NewStyle().Bold(true).Render(task.Title),
+		dim.Render(task.Status),
+	}
+	if task.DueDate != "" {
+		lines[1] += dim.Render("  due " + task.DueDate)
+	}
+	if repeat := describeRecurrence(task); repeat != "" {
+		lines = append(lines, dim.Render("↻ "+repeat))
+	}
+	if task.Description != "" {
+		lines = append(lines, "", task.Description)
+	}
+
+	progress := m.checklistProgress()
+	lines = append(lines, "", lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("Checklist %d/%d", progress.Done, progress.Total)))
+	for i, item := range m.checklist {
+		box := "[ ]"
+		text := item.Text
+		if item.Done {
+			box = "[x]"
+			text = dim.Strikethrough(true).Render(text)
+		}
+		line := fmt.Sprintf("%s %s", box, text)
+		if i == m.checkCursor {
+			line = lipgloss.NewStyle().Foreground(lipgloss.Color("212")).Render("> ") + line
+		} else {
+			line = "  " + line
+		}
+		lines = append(lines, line)
+	}
+	if len(m.checklist) == 0 {
+		lines = append(lines, dim.Render("  No items. Press a to add one."))
+	}
+
+	auto := "off"
+	if task.AutoDone {
+		auto = "on"
+	}
+	lines = append(lines, "", dim.Render("Move to Done when every item is checked: "+auto), "")
+	if m.message != "" {
+		lines = append(lines, blockedStyle.Render(m.message), "")
+	}
+	lines = append(lines, dim.Render("(space) check, (a)dd, (e)dit, (d)elete, (J/K) reorder, (A)uto-move, (esc) close"))
+
+	panel := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("57")).
+		Padding(1, 2).
+		Width(width).
+		Render(strings.Join(lines, "\n"))
+	return lipgloss.Place(m.width, m.height-4, lipgloss.Center, lipgloss.Center, panel)
+}
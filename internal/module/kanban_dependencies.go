@@ -0,0 +1,297 @@
+package module
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/Ceinl/Go-dashboard/internal/storage"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// dependencyDepth limits how far the dependency view follows a chain.
+const dependencyDepth = 8
+
+var blockedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+
+// openDependencies shows the dependency view of task.
+func (m *Kanban) openDependencies(task storage.Task) {
+	m.deps = true
+	m.depsTask = task
+	m.depsCursor = 0
+	m.loadDependencies()
+}
+
+func (m *Kanban) loadDependencies() {
+	blockers, err := storage.GetBlockers(m.db, m.depsTask.ID)
+	if err != nil {
+		log.Printf("Error loading dependencies: %v", err)
+		return
+	}
+	m.depsBlockers = blockers
+	if m.depsCursor >= len(m.depsBlockers) {
+		m.depsCursor = max(len(m.depsBlockers)-1, 0)
+	}
+}
+
+func (m *Kanban) updateDependencies(msg tea.Msg) (Module, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m.updateBrowsing(msg)
+	}
+	if m.picking {
+		return m.updatePicker(keyMsg)
+	}
+
+	m.message = ""
+	switch keyMsg.String() {
+	case "esc", "q":
+		m.deps = false
+		m.loadTasks()
+	case "up", "k":
+		if m.depsCursor > 0 {
+			m.depsCursor--
+		}
+	case "down", "j":
+		if m.depsCursor < len(m.depsBlockers)-1 {
+			m.depsCursor++
+		}
+	case "a":
+		return m, m.startPicker()
+	case "d":
+		if m.depsCursor < len(m.depsBlockers) {
+			if err := storage.RemoveDependency(m.db, m.depsTask.ID, m.depsBlockers[m.depsCursor].ID); err != nil {
+				log.Printf("Error removing dependency: %v", err)
+			}
+			m.loadDependencies()
+		}
+	}
+	return m, nil
+}
+
+// startPicker lists the tasks of the workspace to choose a blocker from.
+func (m *Kanban) startPicker() tea.Cmd {
+	project, err := storage.GetProject(m.db, m.projectID)
+	if err != nil {
+		log.Printf("Error loading project: %v", err)
+		return nil
+	}
+	tasks, err := storage.GetTasksForWorkspace(m.db, project.WorkspaceID)
+	if err != nil {
+		log.Printf("Error loading tasks: %v", err)
+		return nil
+	}
+	m.pickTasks = tasks
+	m.pickCursor = 0
+	m.picking = true
+	m.input.Reset()
+	m.input.Placeholder = "Blocked by: type to filter tasks"
+	return m.input.Focus()
+}
+
+// pickableTasks returns the workspace tasks matching the picker input,
+// leaving out the task itself.
+func (m *Kanban) pickableTasks() []storage.LinkedTask {
+	terms := strings.Fields(strings.ToLower(m.input.Value()))
+	var tasks []storage.LinkedTask
+	for _, task := range m.pickTasks {
+		if task.ID == m.depsTask.ID {
+			continue
+		}
+		haystack := strings.ToLower(task.Title + " " + task.ProjectName)
+		matches := true
+		for _, term := range terms {
+			if !strings.Contains(haystack, term) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
+
+func (m *Kanban) updatePicker(msg tea.KeyMsg) (Module, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.stopPicker()
+		return m, nil
+	case "up", "ctrl+p":
+		if m.pickCursor > 0 {
+			m.pickCursor--
+		}
+		return m, nil
+	case "down", "ctrl+n":
+		if m.pickCursor < len(m.pickableTasks())-1 {
+			m.pickCursor++
+		}
+		return m, nil
+	case "enter":
+		tasks := m.pickableTasks()
+		if m.pickCursor < len(tasks) {
+			blocker := tasks[m.pickCursor]
+			err := storage.AddDependency(m.db, m.depsTask.ID, blocker.ID)
+			if errors.Is(err, storage.ErrDependencyCycle) {
+				m.message = fmt.Sprintf("%q already depends on %q, so it can't block it.", blocker.Title, m.depsTask.Title)
+			} else if err != nil {
+				log.Printf("Error adding dependency: %v", err)
+			}
+			m.loadDependencies()
+		}
+		m.stopPicker()
+		return m, nil
+	}
+
+	previous := m.input.Value()
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	if m.input.Value() != previous {
+		m.pickCursor = 0
+	}
+	return m, cmd
+}
+
+func (m *Kanban) stopPicker() {
+	m.picking = false
+	m.pickTasks = nil
+	m.input.Blur()
+	m.input.Reset()
+	m.input.Placeholder = "New Task"
+}
+
+// blockedWarning explains why moving a blocked task forward needs
+// confirmation, or is "" if the task isn't blocked.
+func (m *Kanban) blockedWarning(task storage.Task) string {
+	blockers := m.blockers[task.ID]
+	if len(blockers) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%q is blocked by %s.", task.Title, quoteList(blockers))
+}
+
+// quoteList formats titles as `"a", "b" and "c"`.
+func quoteList(titles []string) string {
+	quoted := make([]string, len(titles))
+	for i, title := range titles {
+		quoted[i] = fmt.Sprintf("%q", title)
+	}
+	if len(quoted) == 1 {
+		return quoted[0]
+	}
+	return strings.Join(quoted[:len(quoted)-1], ", ") + " and " + quoted[len(quoted)-1]
+}
+
+// blockedBadge marks a card whose blockers aren't all Done.
+func (m *Kanban) blockedBadge(task storage.Task, base lipgloss.Style) string {
+	if task.Status == Done || len(m.blockers[task.ID]) == 0 {
+		return ""
+	}
+	return base.Render(" ") + blockedStyle.Inherit(base).Render("⛔ blocked")
+}
+
+// dependencyChain renders the tasks reachable from taskID through next,
+// indented by depth.
+func (m *Kanban) dependencyChain(taskID string, next func(string) ([]storage.LinkedTask, error), depth int, seen map[string]bool) []string {
+	if depth > dependencyDepth {
+		return []string{strings.Repeat("  ", depth) + "…"}
+	}
+	tasks, err := next(taskID)
+	if err != nil {
+		log.Printf("Error loading dependencies: %v", err)
+		return nil
+	}
+
+	var lines []string
+	for _, task := range tasks {
+		lines = append(lines, strings.Repeat("  ", depth)+"└ "+m.linkedTaskLine(task))
+		if seen[task.ID] {
+			continue
+		}
+		seen[task.ID] = true
+		lines = append(lines, m.dependencyChain(task.ID, next, depth+1, seen)...)
+	}
+	return lines
+}
+
+func (m *Kanban) linkedTaskLine(task storage.LinkedTask) string {
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	status := dim.Render(task.Status)
+	if task.Status == Done {
+		status = lipgloss.NewStyle().Foreground(lipgloss.Color("42")).Render("✓ " + Done)
+	}
+	line := task.Title + "  " + status
+	if task.ProjectID != m.projectID {
+		line += dim.Render("  [" + task.ProjectName + "]")
+	}
+	return line
+}
+
+func (m *Kanban) dependencyView() string {
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	bold := lipgloss.NewStyle().Bold(true)
+	width := min(max(m.width*2/3, 50), m.width-4)
+
+	lines := []string{bold.Render("Dependencies: " + m.depsTask.Title), ""}
+
+	if m.picking {
+		lines = append(lines, m.input.View(), "")
+		tasks := m.pickableTasks()
+		for i, task := range tasks {
+			line := "  " + m.linkedTaskLine(task)
+			if i == m.pickCursor {
+				line = lipgloss.NewStyle().Foreground(lipgloss.Color("212")).Render("> ") + m.linkedTaskLine(task)
+			}
+			lines = append(lines, ansi.Truncate(line, width-4, "…"))
+			if i >= m.height-16 && i < len(tasks)-1 {
+				lines = append(lines, dim.Render(fmt.Sprintf("  … %d more", len(tasks)-i-1)))
+				break
+			}
+		}
+		if len(tasks) == 0 {
+			lines = append(lines, dim.Render("  No matching tasks."))
+		}
+		lines = append(lines, "", dim.Render("(↑/↓) select, (enter) add as blocker, (esc) cancel"))
+	} else {
+		lines = append(lines, bold.Render("Blocked by"))
+		for i, task := range m.depsBlockers {
+			cursor := "  "
+			if i == m.depsCursor {
+				cursor = lipgloss.NewStyle().Foreground(lipgloss.Color("212")).Render("> ")
+			}
+			lines = append(lines, cursor+m.linkedTaskLine(task))
+			lines = append(lines, m.dependencyChain(task.ID, func(id string) ([]storage.LinkedTask, error) {
+				return storage.GetBlockers(m.db, id)
+			}, 2, map[string]bool{task.ID: true})...)
+		}
+		if len(m.depsBlockers) == 0 {
+			lines = append(lines, dim.Render("  Nothing. Press a to add a blocking task."))
+		}
+
+		blocks := m.dependencyChain(m.depsTask.ID, func(id string) ([]storage.LinkedTask, error) {
+			return storage.GetDependents(m.db, id)
+		}, 1, map[string]bool{m.depsTask.ID: true})
+		lines = append(lines, "", bold.Render("Blocks"))
+		if len(blocks) == 0 {
+			lines = append(lines, dim.Render("  Nothing."))
+		}
+		lines = append(lines, blocks...)
+
+		if m.message != "" {
+			lines = append(lines, "", blockedStyle.Render(m.message))
+		}
+		lines = append(lines, "", dim.Render("(a)dd blocker, (d) remove blocker, (j/k) select, (esc) close"))
+	}
+
+	panel := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("57")).
+		Padding(1, 2).
+		Width(width).
+		Render(strings.Join(lines, "\n"))
+	return lipgloss.Place(m.width, m.height-4, lipgloss.Center, lipgloss.Center, panel)
+}
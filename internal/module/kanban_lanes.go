@@ -0,0 +1,312 @@
+package module
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/Ceinl/Go-dashboard/internal/storage"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Swimlane groupings of the board, cycled with "g".
+const (
+	groupNone = iota
+	groupLabel
+	groupPriority
+	groupAssignee
+)
+
+var groupingNames = []string{"", "label", "priority", "assignee"}
+
+var priorityStyles = map[string]lipgloss.Style{
+	"high":   lipgloss.NewStyle().Foreground(lipgloss.Color("196")),
+	"medium": lipgloss.NewStyle().Foreground(lipgloss.Color("214")),
+	"low":    lipgloss.NewStyle().Foreground(lipgloss.Color("240")),
+}
+
+// swimlane is a horizontal band of the board holding the cards that share a
+// label, priority or assignee.
+type swimlane struct {
+	key   string // value of the grouped field, empty for the cards without one
+	title string
+}
+
+// laneKey returns the lane of task under the current grouping. Cards with
+// several labels go in the lane of their first one.
+func (m *Kanban) laneKey(task storage.Task) string {
+	switch m.grouping {
+	case groupLabel:
+		if len(task.Labels) > 0 {
+			return task.Labels[0]
+		}
+	case groupPriority:
+		return task.Priority
+	case groupAssignee:
+		return task.Assignee
+	}
+	return ""
+}
+
+// lanes lists the swimlanes of the filtered board, or nil when the board
+// isn't grouped. Priorities come highest first, labels and assignees by
+// name, and the lane of cards without a value comes last.
+func (m *Kanban) lanes() []swimlane {
+	if m.grouping == groupNone {
+		return nil
+	}
+
+	used := map[string]bool{}
+	for _, colName := range columns {
+		for _, task := range m.filteredTasks(colName) {
+			used[m.laneKey(task)] = true
+		}
+	}
+	var keys []string
+	for key := range used {
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+	if m.grouping == groupPriority {
+		slices.SortFunc(keys, func(a, b string) int {
+			return slices.Index(storage.Priorities, a) - slices.Index(storage.Priorities, b)
+		})
+	} else {
+		slices.SortFunc(keys, func(a, b string) int {
+			return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+		})
+	}
+
+	lanes := make([]swimlane, 0, len(keys)+1)
+	for _, key := range keys {
+		title := key
+		if m.grouping == groupAssignee {
+			title = "@" + key
+		}
+		lanes = append(lanes, swimlane{key: key, title: title})
+	}
+	if used[""] {
+		lanes = append(lanes, swimlane{title: map[int]string{
+			groupLabel:    "No label",
+			groupPriority: "No priority",
+			groupAssignee: "Unassigned",
+		}[m.grouping]})
+	}
+	return lanes
+}
+
+// cellTasks returns the filtered tasks of a column in a lane.
+func (m *Kanban) cellTasks(lanes []swimlane, lane int, colName string) []storage.Task {
+	tasks := m.filteredTasks(colName)
+	if m.grouping == groupNone {
+		return tasks
+	}
+	if lane < 0 || lane >= len(lanes) {
+		return nil
+	}
+	var cell []storage.Task
+	for _, task := range tasks {
+		if m.laneKey(task) == lanes[lane].key {
+			cell = append(cell, task)
+		}
+	}
+	return cell
+}
+
+// collapseKey identifies a lane in collapsed, so that each grouping
+// remembers its own collapsed lanes.
+func (m *Kanban) collapseKey(lane swimlane) string {
+	return groupingNames[m.grouping] + ":" + lane.key
+}
+
+func (m *Kanban) laneCollapsed(lanes []swimlane, lane int) bool {
+	return lane >= 0 && lane < len(lanes) && m.collapsed[m.collapseKey(lanes[lane])]
+}
+
+// cycleGrouping switches to the next grouping, keeping the selected task
+// under the cursor.
+func (m *Kanban) cycleGrouping() {
+	task, ok := m.selectedTask()
+	m.grouping = (m.grouping + 1) % len(groupingNames)
+	m.cursorLane, m.cursorRow = 0, 0
+	if ok {
+		m.selectTask(task.ID)
+	}
+}
+
+func (m *Kanban) toggleLane() {
+	lanes := m.lanes()
+	if m.cursorLane >= len(lanes) {
+		return
+	}
+	key := m.collapseKey(lanes[m.cursorLane])
+	m.collapsed[key] = !m.collapsed[key]
+	m.cursorRow = 0
+}
+
+// moveLane moves the cursor to the previous (-1) or next (1) lane, at the
+// top or, going up with j/k, at the bottom of the column.
+func (m *Kanban) moveLane(direction int, toBottom bool) bool {
+	lane := m.cursorLane + direction
+	if m.grouping == groupNone || lane < 0 || lane >= len(m.lanes()) {
+		return false
+	}
+	m.cursorLane = lane
+	m.cursorRow = 0
+	if toBottom {
+		m.cursorRow = max(len(m.visibleTasks(columns[m.cursorCol]))-1, 0)
+	}
+	return true
+}
+
+// clampLane keeps the cursor on an existing lane after lanes disappear.
+func (m *Kanban) clampLane() {
+	if lanes := m.lanes(); len(lanes) > 0 && m.cursorLane >= len(lanes) {
+		m.cursorLane = len(lanes) - 1
+		m.cursorRow = 0
+	}
+}
+
+// cyclePriority gives the selected task the next priority: high, medium,
+// low, then none.
+func (m *Kanban) cyclePriority() {
+	task, ok := m.selectedTask()
+	if !ok {
+		return
+	}
+	i := slices.Index(storage.Priorities, task.Priority)
+	task.Priority = ""
+	if i < len(storage.Priorities)-1 {
+		task.Priority = storage.Priorities[i+1]
+	}
+	m.saveTask(task)
+}
+
+// setLabels saves value as the comma-separated labels of the selected task.
+func (m *Kanban) setLabels(value string) {
+	if task, ok := m.selectedTask(); ok {
+		task.Labels = storage.ParseTags(value)
+		m.saveTask(task)
+	}
+}
+
+func (m *Kanban) setAssignee(value string) {
+	if task, ok := m.selectedTask(); ok {
+		task.Assignee = strings.TrimPrefix(strings.TrimSpace(value), "@")
+		m.saveTask(task)
+	}
+}
+
+// saveTask updates a task and follows it, as it may change lanes.
+func (m *Kanban) saveTask(task storage.Task) {
+	if err := storage.UpdateTask(m.db, task); err != nil {
+		log.Printf("Error updating task: %v", err)
+		return
+	}
+	m.replaceTask(task)
+	m.selectTask(task.ID)
+}
+
+// attributeBadges shows the priority, labels and assignee of a card.
+func (m *Kanban) attributeBadges(task storage.Task, base lipgloss.Style) string {
+	var badges string
+	if style, ok := priorityStyles[task.Priority]; ok {
+		badges += base.Render(" ") + style.Inherit(base).Render("!"+task.Priority)
+	}
+	dim := base.Foreground(lipgloss.Color("240"))
+	for _, label := range task.Labels {
+		badges += base.Render(" ") + dim.Render("#"+label)
+	}
+	if task.Assignee != "" {
+		badges += base.Render(" ") + dim.Render("@"+task.Assignee)
+	}
+	return badges
+}
+
+// laneView renders the board as swimlanes, each a row of the three columns
+// under a header. Lanes scroll so that the cursor's lane is on screen.
+func (m *Kanban) laneView(boardWidth int) string {
+	columnWidth := boardWidth / len(columns)
+	lanes := m.lanes()
+
+	var headers []string
+	for i, colName := range columns {
+		style := lipgloss.NewStyle().Bold(true).Width(columnWidth).Padding(0, 2)
+		title, over := m.columnTitle(colName)
+		if over {
+			style = style.Foreground(overLimitColor)
+		} else if i == m.cursorCol {
+			style = style.Foreground(lipgloss.Color("57"))
+		}
+		headers = append(headers, style.Render(title))
+	}
+
+	var blocks []string
+	for l, lane := range lanes {
+		count := 0
+		for _, colName := range columns {
+			count += len(m.cellTasks(lanes, l, colName))
+		}
+		marker := "▾"
+		if m.laneCollapsed(lanes, l) {
+			marker = "▸"
+		}
+		titleStyle := lipgloss.NewStyle().Bold(true)
+		if l == m.cursorLane {
+			titleStyle = titleStyle.Foreground(lipgloss.Color("212"))
+		}
+		block := titleStyle.Render(fmt.Sprintf("%s %s (%d)", marker, lane.title, count))
+
+		if !m.laneCollapsed(lanes, l) {
+			var cells []string
+			for i, colName := range columns {
+				var cards []string
+				for j, task := range m.cellTasks(lanes, l, colName) {
+					selected := l == m.cursorLane && i == m.cursorCol && j == m.cursorRow
+					cards = append(cards, m.cardView(task, selected, columnWidth))
+				}
+				cellStyle := lipgloss.NewStyle().
+					Border(lipgloss.RoundedBorder()).
+					Padding(0, 1).
+					Width(columnWidth - 2)
+				if _, over := m.columnTitle(colName); over {
+					cellStyle = cellStyle.BorderForeground(overLimitColor)
+				} else if l == m.cursorLane && i == m.cursorCol {
+					cellStyle = cellStyle.BorderForeground(lipgloss.Color("57"))
+				}
+				cells = append(cells, cellStyle.Render(strings.Join(cards, "\n")))
+			}
+			block += "\n" + lipgloss.JoinHorizontal(lipgloss.Top, cells...)
+		}
+		blocks = append(blocks, block)
+	}
+	if len(lanes) == 0 {
+		blocks = append(blocks, lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("No tasks."))
+	}
+
+	// Start early enough to show every lane up to the cursor's, if it fits.
+	height := m.height - 8
+	first := min(m.cursorLane, len(blocks)-1)
+	for used := lipgloss.Height(blocks[first]); first > 0 && used+lipgloss.Height(blocks[first-1]) <= height; {
+		first--
+		used += lipgloss.Height(blocks[first])
+	}
+
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	lines := []string{lipgloss.JoinHorizontal(lipgloss.Top, headers...)}
+	if first > 0 {
+		lines = append(lines, dim.Render(fmt.Sprintf("↑ %d more lanes", first)))
+	}
+	used := 0
+	for l := first; l < len(blocks); l++ {
+		if used > 0 && used+lipgloss.Height(blocks[l]) > height {
+			lines = append(lines, dim.Render(fmt.Sprintf("↓ %d more lanes", len(blocks)-l)))
+			break
+		}
+		used += lipgloss.Height(blocks[l])
+		lines = append(lines, blocks[l])
+	}
+	return lipgloss.NewStyle().Height(m.height - 6).Render(strings.Join(lines, "\n"))
+}
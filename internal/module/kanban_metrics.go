@@ -0,0 +1,352 @@
+package module
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Ceinl/Go-dashboard/internal/storage"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Range of weeks the metrics view covers, changed with +/-.
+const (
+	defaultMetricsWeeks = 8
+	maxMetricsWeeks     = 52
+)
+
+// statusColors color each column in the cumulative flow diagram.
+var statusColors = map[string]lipgloss.Color{
+	ToDo:       lipgloss.Color("63"),
+	InProgress: lipgloss.Color("214"),
+	Done:       lipgloss.Color("42"),
+}
+
+// barBlocks draw bars in eighths of a cell, from empty to full.
+var barBlocks = []rune(" ▁▂▃▄▅▆▇█")
+
+// flowMetrics describes how a project's tasks moved across the board during
+// a period, from its status transitions.
+type flowMetrics struct {
+	start      time.Time        // start of the first week of the period
+	flow       []map[string]int // tasks per status at evenly spaced times
+	cycle      []time.Duration  // In Progress → Done, of the tasks finished in the period
+	lead       []time.Duration  // created → Done, of the tasks finished in the period
+	throughput []int            // tasks finished each week, oldest first
+	burndown   []int            // unfinished tasks at the end of each day
+}
+
+// computeFlowMetrics replays transitions over the weeks weeks up to now,
+// sampling the cumulative flow samples times.
+func computeFlowMetrics(transitions []storage.TaskTransition, now time.Time, weeks, samples int) flowMetrics {
+	start := startOfWeek(startOfDay(now)).AddDate(0, 0, -7*(weeks-1))
+	metrics := flowMetrics{start: start}
+
+	times := make([]time.Time, samples)
+	for i := range times {
+		times[i] = start.Add(time.Duration(float64(now.Sub(start)) * float64(i) / float64(max(samples-1, 1))))
+	}
+	metrics.flow = countStatuses(transitions, times)
+
+	var days []time.Time
+	for day := start; !day.After(now); day = day.AddDate(0, 0, 1) {
+		days = append(days, minTime(day.AddDate(0, 0, 1), now))
+	}
+	for _, counts := range countStatuses(transitions, days) {
+		open := 0
+		for status, n := range counts {
+			if status != Done {
+				open += n
+			}
+		}
+		metrics.burndown = append(metrics.burndown, open)
+	}
+
+	// The times each task was created, first started and last finished.
+	created := map[string]time.Time{}
+	started := map[string]time.Time{}
+	finished := map[string]time.Time{}
+	status := map[string]string{}
+	for _, t := range transitions {
+		if t.From == "" {
+			created[t.TaskID] = t.At
+		}
+		switch t.To {
+		case InProgress:
+			if _, ok := started[t.TaskID]; !ok {
+				started[t.TaskID] = t.At
+			}
+		case Done:
+			finished[t.TaskID] = t.At
+		}
+		status[t.TaskID] = t.To
+	}
+
+	metrics.throughput = make([]int, weeks)
+	for id, done := range finished {
+		if status[id] != Done || done.Before(start) {
+			continue
+		}
+		if week := int(done.Sub(start).Hours() / (7 * 24)); week < weeks {
+			metrics.throughput[week]++
+		}
+		if at, ok := created[id]; ok {
+			metrics.lead = append(metrics.lead, done.Sub(at))
+		}
+		if at, ok := started[id]; ok && !at.After(done) {
+			metrics.cycle = append(metrics.cycle, done.Sub(at))
+		}
+	}
+	slices.Sort(metrics.cycle)
+	slices.Sort(metrics.lead)
+	return metrics
+}
+
+// countStatuses counts the tasks in each status at each of times, which are
+// in order.
+func countStatuses(transitions []storage.TaskTransition, times []time.Time) []map[string]int {
+	status := map[string]string{}
+	counts := make([]map[string]int, len(times))
+	i := 0
+	for k, at := range times {
+		for ; i < len(transitions) && !transitions[i].At.After(at); i++ {
+			if t := transitions[i]; t.To == "" {
+				delete(status, t.TaskID)
+			} else {
+				status[t.TaskID] = t.To
+			}
+		}
+		counts[k] = map[string]int{}
+		for _, s := range status {
+			counts[k][s]++
+		}
+	}
+	return counts
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+// percentile returns the nearest-rank p-th percentile of sorted durations.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	return sorted[min(max(i, 0), len(sorted)-1)]
+}
+
+func average(durations []time.Duration) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	var total time.Duration
+	for _, d := range durations {
+		total += d
+	}
+	return total / time.Duration(len(durations))
+}
+
+func (m *Kanban) openMetrics() {
+	m.metrics = true
+	if m.metricsWeeks == 0 {
+		m.metricsWeeks = defaultMetricsWeeks
+	}
+	transitions, err := storage.GetTaskTransitions(m.db, m.projectID)
+	if err != nil {
+		log.Printf("Error loading task history: %v", err)
+	}
+	m.transitions = transitions
+}
+
+func (m *Kanban) updateMetrics(msg tea.Msg) (Module, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m.updateBrowsing(msg)
+	}
+	switch keyMsg.String() {
+	case "esc", "q", "m":
+		m.metrics = false
+		m.transitions = nil
+	case "+", "=":
+		m.metricsWeeks = min(m.metricsWeeks+1, maxMetricsWeeks)
+	case "-":
+		m.metricsWeeks = max(m.metricsWeeks-1, 1)
+	}
+	return m, nil
+}
+
+func (m *Kanban) metricsView() string {
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	bold := lipgloss.NewStyle().Bold(true)
+	width := max(m.width-8, 20)
+	now := time.Now()
+	metrics := computeFlowMetrics(m.transitions, now, m.metricsWeeks, width-6)
+
+	lines := []string{
+		bold.Render(fmt.Sprintf("Flow metrics, last %d weeks", m.metricsWeeks)) +
+			dim.Render(" since "+metrics.start.Format("Mon Jan 2")),
+		"",
+	}
+	if len(m.transitions) == 0 {
+		lines = append(lines, dim.Render("No task history yet. Tasks show up here once they are added or moved."))
+	} else {
+		legend := ""
+		for _, status := range columns {
+			legend += "  " + lipgloss.NewStyle().Foreground(statusColors[status]).Render("█") + " " + status
+		}
+		lines = append(lines, bold.Render("Cumulative flow")+dim.Render(legend))
+		lines = append(lines, cumulativeFlowChart(metrics.flow, max(m.height-28, 6))...)
+		lines = append(lines, dim.Render("      "+spread(width-6, metrics.start.Format("Jan 2"), "now")), "")
+
+		for _, row := range []struct {
+			name, span string
+			durations  []time.Duration
+		}{
+			{"Cycle time", "In Progress → Done", metrics.cycle},
+			{"Lead time", "created → Done", metrics.lead},
+		} {
+			line := fmt.Sprintf("%-10s ", row.name) + dim.Render(fmt.Sprintf("%-20s", row.span))
+			if len(row.durations) == 0 {
+				line += dim.Render("no tasks finished")
+			} else {
+				line += fmt.Sprintf("avg %-8s p50 %-8s p85 %-8s p95 %-8s",
+					formatRemaining(average(row.durations)), formatRemaining(percentile(row.durations, 50)),
+					formatRemaining(percentile(row.durations, 85)), formatRemaining(percentile(row.durations, 95)))
+				line += dim.Render(fmt.Sprintf("over %d finished", len(row.durations)))
+			}
+			lines = append(lines, line)
+		}
+
+		half := (width - 4) / 2
+		barWidth := min(max(half/max(len(metrics.throughput), 1)-1, 1), 5)
+		throughput := append([]string{bold.Render("Throughput per week")},
+			barChart(metrics.throughput, 6, barWidth, lipgloss.NewStyle().Foreground(statusColors[Done]))...)
+		throughput = append(throughput, dim.Render(barLabels(metrics.throughput, barWidth)))
+		days := resample(metrics.burndown, half/2)
+		burndown := append([]string{bold.Render("Burndown") + dim.Render(fmt.Sprintf(" unfinished tasks, %d → %d", days[0], days[len(days)-1]))},
+			barChart(days, 6, 1, lipgloss.NewStyle().Foreground(statusColors[InProgress]))...)
+		burndown = append(burndown, dim.Render(spread(max(len(days)*2, 14), metrics.start.Format("Jan 2"), "now")))
+		lines = append(lines, "", lipgloss.JoinHorizontal(lipgloss.Top,
+			lipgloss.NewStyle().Width(half+4).Render(strings.Join(throughput, "\n")),
+			strings.Join(burndown, "\n")))
+	}
+	lines = append(lines, "", dim.Render("(+/-) weeks, (esc) close"))
+
+	return lipgloss.NewStyle().Padding(1, 2).Render(strings.Join(lines, "\n"))
+}
+
+// cumulativeFlowChart stacks the number of tasks in each column over time,
+// Done at the bottom, with the axis labeled by task count.
+func cumulativeFlowChart(flow []map[string]int, height int) []string {
+	peak := 1
+	for _, counts := range flow {
+		total := 0
+		for _, status := range columns {
+			total += counts[status]
+		}
+		peak = max(peak, total)
+	}
+
+	rows := make([]string, height)
+	for r := range height {
+		// The value at the middle of the cell decides its color.
+		value := (float64(height-r) - 0.5) * float64(peak) / float64(height)
+		var row strings.Builder
+		runStatus, runLength := "", 0
+		flush := func() {
+			if runLength == 0 {
+				return
+			}
+			if runStatus == "" {
+				row.WriteString(strings.Repeat(" ", runLength))
+			} else {
+				row.WriteString(lipgloss.NewStyle().Foreground(statusColors[runStatus]).Render(strings.Repeat("█", runLength)))
+			}
+		}
+		for _, counts := range flow {
+			status, bound := "", 0.0
+			for i := len(columns) - 1; i >= 0; i-- {
+				bound += float64(counts[columns[i]])
+				if value < bound {
+					status = columns[i]
+					break
+				}
+			}
+			if status != runStatus {
+				flush()
+				runStatus, runLength = status, 0
+			}
+			runLength++
+		}
+		flush()
+
+		axis := "     │"
+		switch r {
+		case 0:
+			axis = fmt.Sprintf("%4d ┤", peak)
+		case height - 1:
+			axis = fmt.Sprintf("%4d ┤", 0)
+		}
+		rows[r] = axis + row.String()
+	}
+	return rows
+}
+
+// barChart draws values as vertical bars of the given width, scaled to
+// height rows, in eighths of a row.
+func barChart(values []int, height, barWidth int, style lipgloss.Style) []string {
+	peak := 1
+	for _, v := range values {
+		peak = max(peak, v)
+	}
+	rows := make([]string, height)
+	for r := range height {
+		var row strings.Builder
+		for _, v := range values {
+			eighths := v*height*8/peak - (height-1-r)*8
+			row.WriteString(strings.Repeat(string(barBlocks[min(max(eighths, 0), 8)]), barWidth) + " ")
+		}
+		rows[r] = style.Render(row.String())
+	}
+	return rows
+}
+
+// barLabels centers each value under its bar.
+func barLabels(values []int, barWidth int) string {
+	var s strings.Builder
+	for _, v := range values {
+		label := fmt.Sprint(v)
+		if len(label) > barWidth {
+			label = strings.Repeat(" ", barWidth)
+		}
+		pad := barWidth - len(label)
+		s.WriteString(strings.Repeat(" ", pad/2) + label + strings.Repeat(" ", pad-pad/2) + " ")
+	}
+	return s.String()
+}
+
+// resample picks at most n evenly spaced values.
+func resample(values []int, n int) []int {
+	if len(values) <= n {
+		return values
+	}
+	picked := make([]int, n)
+	for i := range picked {
+		picked[i] = values[i*(len(values)-1)/max(n-1, 1)]
+	}
+	return picked
+}
+
+// spread puts left and right at the two ends of a line of width cells.
+func spread(width int, left, right string) string {
+	return left + strings.Repeat(" ", max(width-len(left)-len(right), 1)) + right
+}
@@ -0,0 +1,89 @@
+package module
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ceinl/Go-dashboard/internal/storage"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/google/uuid"
+)
+
+func TestComputeFlowMetrics(t *testing.T) {
+	// Wednesday of the second week of a two-week period.
+	now := time.Date(2024, 5, 15, 12, 0, 0, 0, time.Local)
+	day := func(d, hour int) time.Time { return time.Date(2024, 5, d, hour, 0, 0, 0, time.Local) }
+	transitions := []storage.TaskTransition{
+		{TaskID: "a", To: ToDo, At: day(6, 9)},
+		{TaskID: "b", To: ToDo, At: day(6, 9)},
+		{TaskID: "c", To: ToDo, At: day(6, 10)},
+		{TaskID: "a", From: ToDo, To: InProgress, At: day(7, 9)},
+		{TaskID: "a", From: InProgress, To: Done, At: day(9, 9)},
+		{TaskID: "b", From: ToDo, To: InProgress, At: day(13, 9)},
+		{TaskID: "b", From: InProgress, To: Done, At: day(14, 9)},
+		{TaskID: "c", From: ToDo, To: Done, At: day(14, 10)},
+		{TaskID: "c", From: Done, To: "", At: day(15, 9)},
+	}
+
+	metrics := computeFlowMetrics(transitions, now, 2, 10)
+	if !metrics.start.Equal(day(6, 0)) {
+		t.Errorf("expected the period to start on Monday May 6, got %v", metrics.start)
+	}
+	if len(metrics.throughput) != 2 || metrics.throughput[0] != 1 || metrics.throughput[1] != 1 {
+		t.Errorf("expected one task finished each week, got %v", metrics.throughput)
+	}
+	if want := []time.Duration{24 * time.Hour, 48 * time.Hour}; !equalDurations(metrics.cycle, want) {
+		t.Errorf("expected cycle times %v, got %v", want, metrics.cycle)
+	}
+	if want := []time.Duration{72 * time.Hour, 192 * time.Hour}; !equalDurations(metrics.lead, want) {
+		t.Errorf("expected lead times %v, got %v", want, metrics.lead)
+	}
+	if got := percentile(metrics.lead, 50); got != 72*time.Hour {
+		t.Errorf("expected a median lead time of 3 days, got %v", got)
+	}
+
+	// Three open tasks at the end of May 6, two once "a" is done on the 9th,
+	// none after the 14th.
+	if len(metrics.burndown) != 10 || metrics.burndown[0] != 3 || metrics.burndown[3] != 2 || metrics.burndown[8] != 0 {
+		t.Errorf("unexpected burndown %v", metrics.burndown)
+	}
+	if last := metrics.flow[len(metrics.flow)-1]; last[Done] != 2 || last[ToDo] != 0 {
+		t.Errorf("expected two tasks Done by now, got %v", last)
+	}
+}
+
+func equalDurations(a, b []time.Duration) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestKanbanMetricsView(t *testing.T) {
+	db, projectID := setupTestDB(t)
+	task := storage.Task{ID: uuid.New().String(), ProjectID: projectID, Title: "Ship", Status: ToDo}
+	if err := storage.CreateTask(db, task); err != nil {
+		t.Fatalf("failed to create task: %v", err)
+	}
+
+	k := NewKanban(db, projectID)
+	k.Init()
+	k, _ = k.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	k = typeKeys(k, "L", "L", "m")
+	view := ansi.Strip(k.View())
+	for _, want := range []string{"Flow metrics, last 8 weeks", "Cumulative flow", "Cycle time", "Throughput per week", "Burndown"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in the metrics view, got:\n%s", want, view)
+		}
+	}
+	if k = typeKeys(k, "-", "esc"); k.(*Kanban).metrics || k.(*Kanban).metricsWeeks != 7 {
+		t.Error("expected - to shorten the period and esc to close the view")
+	}
+}
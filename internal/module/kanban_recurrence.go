@@ -0,0 +1,108 @@
+package module
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Ceinl/Go-dashboard/internal/storage"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// setRecurrence parses value and saves it as the schedule of the selected
+// task, which becomes the template of the series. An empty value stops it.
+func (m *Kanban) setRecurrence(value string) tea.Cmd {
+	task, ok := m.selectedTask()
+	if !ok {
+		return nil
+	}
+
+	if strings.TrimSpace(value) == "" {
+		task.Recurrence, task.NextRun = "", ""
+	} else {
+		rule, err := storage.ParseRecurrence(value)
+		if err != nil {
+			m.message = err.Error()
+			return nil
+		}
+		start := time.Now()
+		if due, err := time.Parse(storage.DateLayout, task.DueDate); err == nil {
+			start = due
+		}
+		rule = rule.Anchor(start)
+		first := rule.First(start)
+		if task.DueDate == "" {
+			task.DueDate = first.Format(storage.DateLayout)
+		}
+		task.Recurrence = rule.String()
+		task.NextRun = rule.Next(first).Format(storage.DateLayout)
+	}
+
+	if err := storage.UpdateTask(m.db, task); err != nil {
+		log.Printf("Error updating task: %v", err)
+		return nil
+	}
+	if err := storage.SetNextRun(m.db, task.ID, task.NextRun); err != nil {
+		log.Printf("Error updating task: %v", err)
+		return nil
+	}
+	m.replaceTask(task)
+	return m.generateRecurring()
+}
+
+// generateRecurring runs the recurring task scheduler after a change that
+// may be due a new occurrence, such as finishing one. The returned command
+// tells the modules to reload if it created any.
+func (m *Kanban) generateRecurring() tea.Cmd {
+	created, err := GenerateRecurringTasks(m.db, time.Now())
+	if err != nil {
+		log.Printf("Error creating recurring tasks: %v", err)
+	}
+	if len(created) == 0 {
+		return nil
+	}
+	return func() tea.Msg { return TasksChangedMsg{} }
+}
+
+// reloadTasks reloads the board, keeping the cursor on the selected task,
+// and the task of an open card detail.
+func (m *Kanban) reloadTasks() {
+	task, ok := m.selectedTask()
+	m.loadTasks()
+	if ok {
+		m.selectTask(task.ID)
+	}
+	if m.detail {
+		if task, err := storage.GetTask(m.db, m.detailTask.ID); err == nil {
+			m.detailTask = task
+		}
+	}
+}
+
+// recurrenceBadge marks the tasks of a recurring series.
+func (m *Kanban) recurrenceBadge(task storage.Task, base lipgloss.Style) string {
+	if task.Recurrence == "" && task.TemplateID == "" {
+		return ""
+	}
+	return base.Render(" ") + base.Foreground(lipgloss.Color("240")).Render("↻")
+}
+
+// describeRecurrence explains the schedule of a task for its card detail.
+func describeRecurrence(task storage.Task) string {
+	if task.Recurrence == "" {
+		if task.TemplateID != "" {
+			return "Created by a recurring task"
+		}
+		return ""
+	}
+	rule, err := storage.ParseRecurrence(task.Recurrence)
+	if err != nil {
+		return "Repeats: " + task.Recurrence
+	}
+	s := "Repeats " + rule.Describe()
+	if task.NextRun != "" {
+		s += ", next on " + task.NextRun
+	}
+	return s
+}
@@ -0,0 +1,460 @@
+package module
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ceinl/Go-dashboard/internal/storage"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/google/uuid"
+)
+
+func setupTestDB(t *testing.T) (*sql.DB, string) {
+	db, err := storage.InitDB("file::memory:?cache=shared")
+	if err != nil {
+		t.Fatalf("failed to initialize database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	workspace := storage.Workspace{ID: uuid.New().String(), Name: "Test"}
+	if err := storage.CreateWorkspace(db, workspace); err != nil {
+		t.Fatalf("failed to create workspace: %v", err)
+	}
+	project := storage.Project{ID: uuid.New().String(), WorkspaceID: workspace.ID, Name: "Test"}
+	if err := storage.CreateProject(db, project); err != nil {
+		t.Fatalf("failed to create project: %v", err)
+	}
+	return db, project.ID
+}
+
+func typeKeys(m Module, keys ...string) Module {
+	for _, k := range keys {
+		var msg tea.KeyMsg
+		switch k {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		}
+		m, _ = m.Update(msg)
+	}
+	return m
+}
+
+func TestKanbanFilterKeepsCursorOnVisibleTasks(t *testing.T) {
+	db, projectID := setupTestDB(t)
+	for _, title := range []string{"write docs", "fix login bug", "release", "fix search bug"} {
+		task := storage.Task{ID: uuid.New().String(), ProjectID: projectID, Title: title, Status: ToDo}
+		if err := storage.CreateTask(db, task); err != nil {
+			t.Fatalf("failed to create task: %v", err)
+		}
+	}
+
+	k := NewKanban(db, projectID)
+	k.Init()
+	k = typeKeys(k, "/", "b", "u", "g", "enter")
+
+	kanban := k.(*Kanban)
+	if got := len(kanban.visibleTasks(ToDo)); got != 2 {
+		t.Fatalf("expected 2 visible tasks, got %d", got)
+	}
+
+	// The second visible task is "fix search bug", even though it is the
+	// fourth task in the column.
+	k = typeKeys(k, "j", "j", "L")
+	moved, _ := storage.GetTasksForProject(db, projectID)
+	for _, task := range moved {
+		want := ToDo
+		if task.Title == "fix search bug" {
+			want = InProgress
+		}
+		if task.Status != want {
+			t.Errorf("task %q: expected status %q, got %q", task.Title, want, task.Status)
+		}
+	}
+	if kanban.cursorCol != 1 || kanban.cursorRow != 0 {
+		t.Errorf("expected cursor on moved task at (1, 0), got (%d, %d)", kanban.cursorCol, kanban.cursorRow)
+	}
+
+	typeKeys(k, "esc")
+	if got := len(kanban.visibleTasks(ToDo)); got != 3 {
+		t.Errorf("expected filter to be cleared, got %d visible tasks", got)
+	}
+}
+
+func TestParseDueDate(t *testing.T) {
+	today := time.Date(2026, 1, 30, 9, 0, 0, 0, time.Local)
+	cases := map[string]string{
+		"":           "",
+		"today":      "2026-01-30",
+		"Tomorrow":   "2026-01-31",
+		"+3d":        "2026-02-02",
+		"2026-05-01": "2026-05-01",
+	}
+	for input, want := range cases {
+		got, err := parseDueDate(input, today)
+		if err != nil {
+			t.Errorf("parseDueDate(%q) returned error: %v", input, err)
+		} else if got != want {
+			t.Errorf("parseDueDate(%q) = %q, want %q", input, got, want)
+		}
+	}
+
+	for _, input := range []string{"next week", "+xd", "2026-13-01"} {
+		if _, err := parseDueDate(input, today); err == nil {
+			t.Errorf("parseDueDate(%q) should fail", input)
+		}
+	}
+}
+
+func TestKanbanChecklistAutoMovesToDone(t *testing.T) {
+	db, projectID := setupTestDB(t)
+	task := storage.Task{ID: uuid.New().String(), ProjectID: projectID, Title: "Release", Status: InProgress}
+	if err := storage.CreateTask(db, task); err != nil {
+		t.Fatalf("failed to create task: %v", err)
+	}
+
+	k := NewKanban(db, projectID)
+	k.Init()
+	k, _ = k.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	k = typeKeys(k, "l", "enter", "a", "T", "a", "g", "enter", "a", "S", "h", "i", "p", "enter")
+	kanban := k.(*Kanban)
+	if !kanban.detail || len(kanban.checklist) != 2 || kanban.checklist[1].Text != "Ship" {
+		t.Fatalf("expected two checklist items, got %+v", kanban.checklist)
+	}
+
+	k = typeKeys(k, "k", " ", "esc")
+	if got := kanban.progress[task.ID]; got.Done != 1 || got.Total != 2 {
+		t.Errorf("expected 1/2 on the card, got %+v", got)
+	}
+	if view := k.View(); !strings.Contains(view, "☑ 1/2") {
+		t.Errorf("expected the progress on the card, got:\n%s", view)
+	}
+
+	k = typeKeys(k, "enter", "A", "j", "x")
+	saved, _ := storage.GetTask(db, task.ID)
+	if saved.Status != Done || !saved.AutoDone {
+		t.Errorf("expected the task to move to Done once complete, got %+v", saved)
+	}
+	if kanban.cursorCol != 2 || kanban.cursorRow != 0 {
+		t.Errorf("expected the cursor to follow the task, got (%d, %d)", kanban.cursorCol, kanban.cursorRow)
+	}
+}
+
+func TestKanbanWarnsBeforeMovingBlockedTask(t *testing.T) {
+	db, projectID := setupTestDB(t)
+	for _, task := range []storage.Task{
+		{ID: "design", ProjectID: projectID, Title: "Design", Status: ToDo},
+		{ID: "build", ProjectID: projectID, Title: "Build", Status: ToDo},
+	} {
+		if err := storage.CreateTask(db, task); err != nil {
+			t.Fatalf("failed to create task: %v", err)
+		}
+	}
+
+	k := NewKanban(db, projectID)
+	k.Init()
+	k, _ = k.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	kanban := k.(*Kanban)
+	kanban.selectTask("build")
+
+	// Pick Design as the blocker of Build.
+	k = typeKeys(k, "D", "a", "d", "e", "s", "enter")
+	if len(kanban.depsBlockers) != 1 || kanban.depsBlockers[0].ID != "design" {
+		t.Fatalf("expected Design to block Build, got %+v", kanban.depsBlockers)
+	}
+	k = typeKeys(k, "esc")
+	if view := k.View(); !strings.Contains(view, "⛔ blocked") {
+		t.Errorf("expected the card to be marked blocked, got:\n%s", view)
+	}
+
+	k = typeKeys(k, "L")
+	if task, _ := storage.GetTask(db, "build"); task.Status != ToDo || !strings.Contains(kanban.message, `blocked by "Design"`) {
+		t.Fatalf("expected the move to be refused with a warning, got %q and %q", task.Status, kanban.message)
+	}
+	k = typeKeys(k, "L")
+	if task, _ := storage.GetTask(db, "build"); task.Status != InProgress {
+		t.Errorf("expected a second L to move the task anyway, got %q", task.Status)
+	}
+
+	// Design can't be blocked by Build now.
+	kanban.selectTask("design")
+	k = typeKeys(k, "D", "a", "b", "u", "i", "l", "d", "enter")
+	if len(kanban.depsBlockers) != 0 || !strings.Contains(kanban.message, "can't block") {
+		t.Errorf("expected the cycle to be refused, got %+v and %q", kanban.depsBlockers, kanban.message)
+	}
+}
+
+func TestKanbanSwimlanes(t *testing.T) {
+	db, projectID := setupTestDB(t)
+	for _, task := range []storage.Task{
+		{ID: "a", ProjectID: projectID, Title: "Fix crash", Status: ToDo, Priority: "high"},
+		{ID: "b", ProjectID: projectID, Title: "Review PR", Status: InProgress, Priority: "high"},
+		{ID: "c", ProjectID: projectID, Title: "Tidy docs", Status: ToDo},
+		{ID: "d", ProjectID: projectID, Title: "Rename flag", Status: ToDo, Priority: "low"},
+	} {
+		if err := storage.CreateTask(db, task); err != nil {
+			t.Fatalf("failed to create task: %v", err)
+		}
+	}
+
+	k := NewKanban(db, projectID)
+	k.Init()
+	k, _ = k.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	k = typeKeys(k, "g", "g")
+	kanban := k.(*Kanban)
+	var titles []string
+	for _, lane := range kanban.lanes() {
+		titles = append(titles, lane.title)
+	}
+	if strings.Join(titles, ", ") != "high, low, No priority" {
+		t.Fatalf("expected priority lanes, got %v", titles)
+	}
+
+	selected := func() string {
+		task, _ := kanban.selectedTask()
+		return task.ID
+	}
+	if k = typeKeys(k, "j"); selected() != "d" {
+		t.Errorf("expected j to move into the next lane, got %q", selected())
+	}
+	if k = typeKeys(k, "j", "k"); selected() != "d" {
+		t.Errorf("expected k to move back up a lane, got %q", selected())
+	}
+
+	k = typeKeys(k, "z")
+	if view := ansi.Strip(k.View()); !strings.Contains(view, "▸ low (1)") || strings.Contains(view, "Rename flag") {
+		t.Errorf("expected the low lane collapsed, got:\n%s", view)
+	}
+	if selected() != "" {
+		t.Errorf("expected no task selected on a collapsed lane, got %q", selected())
+	}
+
+	k = typeKeys(k, "]", "p")
+	if task, _ := storage.GetTask(db, "c"); task.Priority != "high" {
+		t.Fatalf("expected p to set the priority, got %q", task.Priority)
+	}
+	if kanban.cursorLane != 0 || selected() != "c" {
+		t.Errorf("expected the cursor to follow the task into the high lane, got lane %d on %q", kanban.cursorLane, selected())
+	}
+}
+
+func TestKanbanWIPLimits(t *testing.T) {
+	db, projectID := setupTestDB(t)
+	for _, task := range []storage.Task{
+		{ID: "a", ProjectID: projectID, Title: "Review", Status: ToDo},
+		{ID: "b", ProjectID: projectID, Title: "Build", Status: InProgress},
+		{ID: "c", ProjectID: projectID, Title: "Test", Status: InProgress},
+	} {
+		if err := storage.CreateTask(db, task); err != nil {
+			t.Fatalf("failed to create task: %v", err)
+		}
+	}
+
+	k := NewKanban(db, projectID)
+	k.Init()
+	k, _ = k.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	kanban := k.(*Kanban)
+	k = typeKeys(k, "l", "w", "2", "enter", "h")
+	if view := ansi.Strip(k.View()); !strings.Contains(view, "In Progress (2/2)") {
+		t.Fatalf("expected the limit in the column header, got:\n%s", view)
+	}
+
+	k = typeKeys(k, "L")
+	if task, _ := storage.GetTask(db, "a"); task.Status != ToDo || !strings.Contains(kanban.message, "WIP limit of 2") {
+		t.Fatalf("expected the move to need confirmation, got %q and %q", task.Status, kanban.message)
+	}
+	k = typeKeys(k, "L")
+	if task, _ := storage.GetTask(db, "a"); task.Status != InProgress {
+		t.Fatalf("expected the confirmed move to go through, got %q", task.Status)
+	}
+	if title, over := kanban.columnTitle(InProgress); title != "In Progress (3/2)" || !over {
+		t.Errorf("expected the column over its limit, got %q", title)
+	}
+
+	// With strict limits, confirming doesn't help.
+	k = typeKeys(k, "W", "H", "L", "L")
+	if task, _ := storage.GetTask(db, "a"); task.Status != ToDo || !strings.Contains(kanban.message, "Finish something") {
+		t.Errorf("expected the move to be refused, got %q and %q", task.Status, kanban.message)
+	}
+}
+
+func TestKanbanWIPLimitsCoverEveryWayIn(t *testing.T) {
+	db, projectID := setupTestDB(t)
+	for _, task := range []storage.Task{
+		{ID: "a", ProjectID: projectID, Title: "Build", Status: InProgress, AutoDone: true},
+		{ID: "b", ProjectID: projectID, Title: "Test", Status: InProgress},
+		{ID: "c", ProjectID: projectID, Title: "Plan", Status: Done},
+	} {
+		if err := storage.CreateTask(db, task); err != nil {
+			t.Fatalf("failed to create task: %v", err)
+		}
+	}
+	if err := storage.CreateChecklistItem(db, storage.ChecklistItem{ID: uuid.New().String(), TaskID: "a", Text: "Compile"}); err != nil {
+		t.Fatalf("failed to create checklist item: %v", err)
+	}
+	for colName, limit := range map[string]int{InProgress: 2, Done: 1} {
+		if err := storage.SetWIPLimit(db, projectID, colName, limit); err != nil {
+			t.Fatalf("failed to set WIP limit: %v", err)
+		}
+	}
+
+	k := NewKanban(db, projectID)
+	k.Init()
+	k, _ = k.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	kanban := k.(*Kanban)
+
+	// Adding a task to a full column needs confirmation.
+	k = typeKeys(k, "l", "a")
+	if kanban.editing || !strings.Contains(kanban.message, "WIP limit of 2") {
+		t.Fatalf("expected adding to need confirmation, got %q", kanban.message)
+	}
+	k = typeKeys(k, "a", "Ship", "enter")
+	if len(kanban.tasks[InProgress]) != 3 {
+		t.Fatalf("expected the confirmed task in In Progress, got %v", kanban.tasks[InProgress])
+	}
+
+	// A finished checklist doesn't move its task over the limit of Done.
+	k = typeKeys(k, "enter", " ")
+	if task, _ := storage.GetTask(db, "a"); task.Status != InProgress || !strings.Contains(kanban.message, "stays in In Progress") || !strings.Contains(ansi.Strip(k.View()), "Done would go over") {
+		t.Errorf("expected Build to stay in In Progress, got %q and %q", task.Status, kanban.message)
+	}
+	k = typeKeys(k, "esc")
+
+	// With strict limits, adding is refused.
+	k = typeKeys(k, "W", "a", "a")
+	if kanban.editing || !strings.Contains(kanban.message, "Finish something") {
+		t.Errorf("expected adding to be refused, got %q", kanban.message)
+	}
+
+	// Moving to another project checks the limits of its board.
+	project, err := storage.GetProject(db, projectID)
+	if err != nil {
+		t.Fatalf("failed to get project: %v", err)
+	}
+	other := storage.Project{ID: uuid.New().String(), WorkspaceID: project.WorkspaceID, Name: "Other"}
+	if err := storage.CreateProject(db, other); err != nil {
+		t.Fatalf("failed to create project: %v", err)
+	}
+	if err := storage.SetWIPLimit(db, other.ID, InProgress, 1); err != nil {
+		t.Fatalf("failed to set WIP limit: %v", err)
+	}
+	if err := storage.CreateTask(db, storage.Task{ID: "d", ProjectID: other.ID, Title: "Deploy", Status: InProgress}); err != nil {
+		t.Fatalf("failed to create task: %v", err)
+	}
+	k = typeKeys(k, "P", "other", "enter")
+	if task, _ := storage.GetTask(db, "a"); task.ProjectID != projectID || kanban.transferring != transferMove || !strings.Contains(ansi.Strip(k.View()), "would go over") {
+		t.Fatalf("expected the move to need confirmation, got %+v and %q", task, kanban.message)
+	}
+	k = typeKeys(k, "enter")
+	if task, _ := storage.GetTask(db, "a"); task.ProjectID != other.ID {
+		t.Errorf("expected the confirmed move to go through, got %+v", task)
+	}
+}
+
+func TestKanbanMovesAndCopiesTasksBetweenProjects(t *testing.T) {
+	db, projectID := setupTestDB(t)
+	home := storage.Workspace{ID: uuid.New().String(), Name: "Home"}
+	if err := storage.CreateWorkspace(db, home); err != nil {
+		t.Fatalf("failed to create workspace: %v", err)
+	}
+	garden := storage.Project{ID: uuid.New().String(), WorkspaceID: home.ID, Name: "Garden"}
+	if err := storage.CreateProject(db, garden); err != nil {
+		t.Fatalf("failed to create project: %v", err)
+	}
+	for _, task := range []storage.Task{
+		{ID: "a", ProjectID: projectID, Title: "Water", Status: ToDo, Labels: []string{"weekly"}},
+		{ID: "b", ProjectID: projectID, Title: "Weed", Status: ToDo},
+		{ID: "c", ProjectID: projectID, Title: "Plant", Status: InProgress, Priority: "high"},
+	} {
+		if err := storage.CreateTask(db, task); err != nil {
+			t.Fatalf("failed to create task: %v", err)
+		}
+	}
+	if err := storage.CreateChecklistItem(db, storage.ChecklistItem{ID: uuid.New().String(), TaskID: "c", Text: "Dig", Done: true}); err != nil {
+		t.Fatalf("failed to create checklist item: %v", err)
+	}
+	if err := storage.AddDependency(db, "a", "c"); err != nil {
+		t.Fatalf("failed to add dependency: %v", err)
+	}
+
+	k := NewKanban(db, projectID)
+	k.Init()
+	k, _ = k.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	kanban := k.(*Kanban)
+
+	k = typeKeys(k, "x", "j", "x", "P")
+	if view := ansi.Strip(k.View()); !strings.Contains(view, "Move 2 tasks to") || !strings.Contains(view, "Home / Garden") || strings.Contains(view, "Test / Test") {
+		t.Fatalf("expected a picker of the other projects, got:\n%s", view)
+	}
+	k = typeKeys(k, "garden", "enter")
+	for _, id := range []string{"a", "b"} {
+		if task, _ := storage.GetTask(db, id); task.ProjectID != garden.ID || task.Status != ToDo {
+			t.Errorf("expected %s in Garden, still To Do, got %+v", id, task)
+		}
+	}
+	if moved, _ := storage.GetTask(db, "a"); len(moved.Labels) != 1 || moved.Labels[0] != "weekly" {
+		t.Errorf("expected the labels to move along, got %v", moved.Labels)
+	}
+	if len(kanban.tasks[ToDo]) != 0 || kanban.message != "Moved 2 tasks to Home / Garden. Removed 1 dependency on a task of another workspace." || len(kanban.marked) != 0 {
+		t.Errorf("expected the tasks gone from the board, got %v and %q", kanban.tasks[ToDo], kanban.message)
+	}
+
+	k = typeKeys(k, "l", "c", "garden", "enter")
+	tasks, err := storage.GetTasksForProject(db, garden.ID)
+	if err != nil || len(tasks) != 3 {
+		t.Fatalf("expected a copy in Garden, got %+v (%v)", tasks, err)
+	}
+	for _, task := range tasks {
+		if task.Title != "Plant" {
+			continue
+		}
+		if task.ID == "c" || task.Status != InProgress || task.Priority != "high" {
+			t.Errorf("expected a new In Progress task with the same priority, got %+v", task)
+		}
+		if items, _ := storage.GetChecklist(db, task.ID); len(items) != 1 || items[0].Text != "Dig" || !items[0].Done {
+			t.Errorf("expected the checklist to be copied, got %+v", items)
+		}
+	}
+	if len(kanban.tasks[InProgress]) != 1 {
+		t.Errorf("expected the original to stay on the board, got %v", kanban.tasks[InProgress])
+	}
+}
+
+func TestKanbanTransferPickerScrolls(t *testing.T) {
+	db, projectID := setupTestDB(t)
+	workspace := storage.Workspace{ID: uuid.New().String(), Name: "Home"}
+	if err := storage.CreateWorkspace(db, workspace); err != nil {
+		t.Fatalf("failed to create workspace: %v", err)
+	}
+	for i := range 20 {
+		project := storage.Project{ID: uuid.New().String(), WorkspaceID: workspace.ID, Name: fmt.Sprintf("Project %02d", i)}
+		if err := storage.CreateProject(db, project); err != nil {
+			t.Fatalf("failed to create project: %v", err)
+		}
+	}
+	if err := storage.CreateTask(db, storage.Task{ID: "a", ProjectID: projectID, Title: "Water", Status: ToDo}); err != nil {
+		t.Fatalf("failed to create task: %v", err)
+	}
+
+	k := NewKanban(db, projectID)
+	k.Init()
+	k, _ = k.Update(tea.WindowSizeMsg{Width: 120, Height: 24})
+	k = typeKeys(k, "P")
+	if view := ansi.Strip(k.View()); !strings.Contains(view, "Project 00") || strings.Contains(view, "Project 19") {
+		t.Fatalf("expected the picker to show the first projects only, got:\n%s", view)
+	}
+	for range 19 {
+		k, _ = k.Update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	view := ansi.Strip(k.View())
+	if !strings.Contains(view, "> Home / Project 19") || strings.Contains(view, "Project 00") {
+		t.Errorf("expected the picker to scroll to the cursor, got:\n%s", view)
+	}
+}
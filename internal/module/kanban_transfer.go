@@ -0,0 +1,347 @@
+package module
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/Ceinl/Go-dashboard/internal/storage"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/google/uuid"
+)
+
+// Whether the project picker moves or copies the tasks, see transferring.
+const (
+	transferNone = iota
+	transferMove
+	transferCopy
+)
+
+// transferTarget is a project tasks can be moved or copied to, in any
+// workspace.
+type transferTarget struct {
+	project   storage.Project
+	workspace string
+}
+
+func (t transferTarget) name() string {
+	return t.workspace + " / " + t.project.Name
+}
+
+// toggleMark marks the selected task for the next move or copy, or unmarks it.
+func (m *Kanban) toggleMark() {
+	task, ok := m.selectedTask()
+	if !ok {
+		return
+	}
+	if m.marked[task.ID] {
+		delete(m.marked, task.ID)
+	} else {
+		m.marked[task.ID] = true
+	}
+}
+
+// transferTasks returns the marked tasks of the board, or the selected task
+// if none is marked.
+func (m *Kanban) transferTasks() []storage.Task {
+	var tasks []storage.Task
+	for _, colName := range columns {
+		for _, task := range m.tasks[colName] {
+			if m.marked[task.ID] {
+				tasks = append(tasks, task)
+			}
+		}
+	}
+	if len(tasks) == 0 {
+		if task, ok := m.selectedTask(); ok {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
+
+// startTransfer opens the project picker to move or copy the tasks to. Tasks
+// can be copied within their own project but not moved to it.
+func (m *Kanban) startTransfer(mode int) tea.Cmd {
+	if len(m.transferTasks()) == 0 {
+		return nil
+	}
+	workspaces, err := storage.GetAllWorkspaces(m.db)
+	if err != nil {
+		log.Printf("Error loading workspaces: %v", err)
+		return nil
+	}
+	var targets []transferTarget
+	for _, workspace := range workspaces {
+		projects, err := storage.GetAllProjectsForWorkspace(m.db, workspace.ID)
+		if err != nil {
+			log.Printf("Error loading projects: %v", err)
+			return nil
+		}
+		for _, project := range projects {
+			if mode == transferMove && project.ID == m.projectID {
+				continue
+			}
+			targets = append(targets, transferTarget{project: project, workspace: workspace.Name})
+		}
+	}
+
+	m.transferring = mode
+	m.transferTargets = targets
+	m.transferCursor = 0
+	m.input.Reset()
+	m.input.Placeholder = "Project: type to filter"
+	return m.input.Focus()
+}
+
+// pickableTargets returns the projects matching the picker input.
+func (m *Kanban) pickableTargets() []transferTarget {
+	terms := strings.Fields(strings.ToLower(m.input.Value()))
+	var targets []transferTarget
+	for _, target := range m.transferTargets {
+		haystack := strings.ToLower(target.name())
+		matches := true
+		for _, term := range terms {
+			if !strings.Contains(haystack, term) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+func (m *Kanban) updateTransfer(msg tea.Msg) (Module, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m.updateBrowsing(msg)
+	}
+	confirmed := m.forceMoveID
+	m.forceMoveID, m.message = "", ""
+	switch keyMsg.String() {
+	case "esc":
+		m.stopTransfer()
+		return m, nil
+	case "up", "ctrl+p":
+		if m.transferCursor > 0 {
+			m.transferCursor--
+		}
+		return m, nil
+	case "down", "ctrl+n":
+		if m.transferCursor < len(m.pickableTargets())-1 {
+			m.transferCursor++
+		}
+		return m, nil
+	case "enter":
+		var cmd tea.Cmd
+		if targets := m.pickableTargets(); m.transferCursor < len(targets) {
+			if !m.admitTransfer(targets[m.transferCursor], confirmed) {
+				return m, nil
+			}
+			cmd = m.transfer(targets[m.transferCursor])
+		}
+		m.stopTransfer()
+		return m, cmd
+	}
+
+	previous := m.input.Value()
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(keyMsg)
+	if m.input.Value() != previous {
+		m.transferCursor = 0
+	}
+	return m, cmd
+}
+
+func (m *Kanban) stopTransfer() {
+	m.transferring = transferNone
+	m.transferTargets = nil
+	m.input.Blur()
+	m.input.Reset()
+	m.input.Placeholder = "New Task"
+}
+
+// admitTransfer checks the WIP limits of the target project's columns for
+// the tasks, as admit does on the board.
+func (m *Kanban) admitTransfer(target transferTarget, confirmed string) bool {
+	tasks := m.transferTasks()
+	incoming := make(map[string]int)
+	for _, task := range tasks {
+		incoming[task.Status]++
+	}
+	var over string
+	for _, colName := range columns {
+		if incoming[colName] == 0 {
+			continue
+		}
+		room, limited, err := storage.GetWIPRoom(m.db, target.project.ID, colName)
+		if err != nil {
+			log.Printf("Error loading WIP limits: %v", err)
+			continue
+		}
+		if limited && incoming[colName] > room {
+			over = fmt.Sprintf("%s in %s would go over its WIP limit.", colName, target.name())
+			break
+		}
+	}
+	strict, err := storage.GetWIPStrict(m.db, target.project.ID)
+	if err != nil {
+		log.Printf("Error loading WIP limits: %v", err)
+	}
+
+	verb, what := "move", "it"
+	if m.transferring == transferCopy {
+		verb = "copy"
+	}
+	if len(tasks) > 1 {
+		what = "them"
+	}
+	return m.admit(over, strict, "transfer to "+target.project.ID, confirmed, "Press enter again to "+verb+" "+what)
+}
+
+// transfer moves or copies the tasks to target and unmarks them. The returned
+// command tells the other modules, whose boards may show the target project.
+func (m *Kanban) transfer(target transferTarget) tea.Cmd {
+	tasks := m.transferTasks()
+	if len(tasks) == 0 {
+		return nil
+	}
+	verb, past := "move", "Moved"
+	if m.transferring == transferCopy {
+		verb, past = "copy", "Copied"
+	}
+	done, dropped := 0, 0
+	for _, task := range tasks {
+		var err error
+		if m.transferring == transferCopy {
+			_, err = copyTask(m.db, task, target.project.ID)
+		} else {
+			var n int
+			n, err = storage.MoveTaskToProject(m.db, task.ID, target.project.ID)
+			dropped += n
+		}
+		if err != nil {
+			log.Printf("Error transferring task %q: %v", task.Title, err)
+			continue
+		}
+		done++
+	}
+	clear(m.marked)
+	m.reloadTasks()
+	m.clampLane()
+	if m.cursorRow >= len(m.visibleTasks(columns[m.cursorCol])) {
+		m.cursorRow = max(len(m.visibleTasks(columns[m.cursorCol]))-1, 0)
+	}
+
+	what := fmt.Sprintf("%q", tasks[0].Title)
+	if len(tasks) > 1 {
+		what = fmt.Sprintf("%d of %d tasks", done, len(tasks))
+		if done == len(tasks) {
+			what = fmt.Sprintf("%d tasks", done)
+		}
+	}
+	if done == 0 {
+		m.message = fmt.Sprintf("Couldn't %s %s to %s, see the log.", verb, what, target.name())
+		return nil
+	}
+	m.message = fmt.Sprintf("%s %s to %s.", past, what, target.name())
+	if dropped == 1 {
+		m.message += " Removed 1 dependency on a task of another workspace."
+	} else if dropped > 1 {
+		m.message += fmt.Sprintf(" Removed %d dependencies on tasks of another workspace.", dropped)
+	}
+	return func() tea.Msg { return TasksChangedMsg{} }
+}
+
+// copyTask adds a copy of task to a project, with its checklist and the
+// tasks blocking it that are in the same workspace. The copy doesn't join
+// the recurring series of task nor its branch, and starts a history of its
+// own.
+func copyTask(db *sql.DB, task storage.Task, projectID string) (storage.Task, error) {
+	copied := task
+	copied.ID = uuid.New().String()
+	copied.ProjectID = projectID
+	copied.Branch, copied.BranchBase = "", ""
+	copied.Recurrence, copied.NextRun, copied.TemplateID = "", "", ""
+	if err := storage.CreateTask(db, copied); err != nil {
+		return storage.Task{}, fmt.Errorf("copying %q: %w", task.Title, err)
+	}
+
+	items, err := storage.GetChecklist(db, task.ID)
+	if err != nil {
+		return copied, err
+	}
+	for _, item := range items {
+		item.ID, item.TaskID = uuid.New().String(), copied.ID
+		if err := storage.CreateChecklistItem(db, item); err != nil {
+			return copied, err
+		}
+	}
+	return copied, storage.CopyBlockers(db, task.ID, copied.ID)
+}
+
+// markBadge flags a card marked for the next move or copy.
+func (m *Kanban) markBadge(task storage.Task, base lipgloss.Style) string {
+	if !m.marked[task.ID] {
+		return ""
+	}
+	return base.Foreground(lipgloss.Color("212")).Render("✚") + base.Render(" ")
+}
+
+func (m *Kanban) transferView() string {
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	width := min(max(m.width*2/3, 50), m.width-4)
+
+	tasks := m.transferTasks()
+	title := "no task"
+	if len(tasks) == 1 {
+		title = fmt.Sprintf("%q", tasks[0].Title)
+	} else if len(tasks) > 1 {
+		title = fmt.Sprintf("%d tasks", len(tasks))
+	}
+	verb := "Move"
+	if m.transferring == transferCopy {
+		verb = "Copy"
+	}
+	lines := []string{lipgloss.NewStyle().Bold(true).Render(verb + " " + title + " to"), "", m.input.View(), ""}
+
+	// The list scrolls to keep the cursor in view.
+	targets := m.pickableTargets()
+	rows := max(m.height-16, 1)
+	start := min(max(m.transferCursor-rows+1, 0), max(len(targets)-rows, 0))
+	end := min(start+rows, len(targets))
+	if start > 0 {
+		lines = append(lines, dim.Render(fmt.Sprintf("  … %d more", start)))
+	}
+	for i, target := range targets[start:end] {
+		line := "  " + target.name()
+		if start+i == m.transferCursor {
+			line = lipgloss.NewStyle().Foreground(lipgloss.Color("212")).Render("> " + target.name())
+		}
+		lines = append(lines, ansi.Truncate(line, width-4, "…"))
+	}
+	if end < len(targets) {
+		lines = append(lines, dim.Render(fmt.Sprintf("  … %d more", len(targets)-end)))
+	}
+	if len(targets) == 0 {
+		lines = append(lines, dim.Render("  No matching projects."))
+	}
+	if m.message != "" {
+		lines = append(lines, "", blockedStyle.Render(m.message))
+	}
+	lines = append(lines, "", dim.Render(fmt.Sprintf("(↑/↓) select, (enter) %s, (esc) cancel", strings.ToLower(verb))))
+
+	panel := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("57")).
+		Padding(1, 2).
+		Width(width).
+		Render(strings.Join(lines, "\n"))
+	return lipgloss.Place(m.width, m.height-4, lipgloss.Center, lipgloss.Center, panel)
+}
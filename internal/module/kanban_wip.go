@@ -0,0 +1,108 @@
+package module
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/Ceinl/Go-dashboard/internal/storage"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// overLimitColor marks a column holding more tasks than its WIP limit.
+var overLimitColor = lipgloss.Color("196")
+
+func (m *Kanban) loadLimits() {
+	limits, err := storage.GetWIPLimits(m.db, m.projectID)
+	if err != nil {
+		log.Printf("Error loading WIP limits: %v", err)
+	}
+	m.limits = limits
+
+	strict, err := storage.GetWIPStrict(m.db, m.projectID)
+	if err != nil {
+		log.Printf("Error loading WIP limits: %v", err)
+	}
+	m.strictLimits = strict
+}
+
+// columnTitle is the name of a column with its task count against its WIP
+// limit, e.g. "In Progress (3/3)", and whether it is over the limit.
+func (m *Kanban) columnTitle(colName string) (string, bool) {
+	limit, ok := m.limits[colName]
+	if !ok {
+		return colName, false
+	}
+	count := len(m.tasks[colName])
+	return fmt.Sprintf("%s (%d/%d)", colName, count, limit), count > limit
+}
+
+// atLimit returns the WIP limit of a column if one more task would exceed it.
+func (m *Kanban) atLimit(colName string) (int, bool) {
+	limit, ok := m.limits[colName]
+	return limit, ok && len(m.tasks[colName]) >= limit
+}
+
+// overLimit describes the WIP limit of a column if n more tasks would go
+// over it, or returns "".
+func (m *Kanban) overLimit(colName string, n int) string {
+	limit, ok := m.limits[colName]
+	if !ok || len(m.tasks[colName])+n <= limit {
+		return ""
+	}
+	return fmt.Sprintf("%s would go over its WIP limit of %d.", colName, limit)
+}
+
+// admit decides whether tasks may be added over the WIP limit described by
+// over, if any. With strict limits they are refused; otherwise the user has
+// to repeat the action, which retry explains. action identifies it, and
+// confirmed is the action refused just before.
+func (m *Kanban) admit(over string, strict bool, action, confirmed, retry string) bool {
+	if over == "" {
+		return true
+	}
+	if strict {
+		m.message = over + " Finish something there first."
+		return false
+	}
+	if action == confirmed {
+		return true
+	}
+	m.message = over + " " + retry + " anyway."
+	m.forceMoveID = action
+	return false
+}
+
+// setLimit parses value as the WIP limit of the cursor's column. An empty
+// value or 0 removes the limit.
+func (m *Kanban) setLimit(value string) {
+	value = strings.TrimSpace(value)
+	limit := 0
+	if value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			m.message = fmt.Sprintf("%q isn't a number of tasks.", value)
+			return
+		}
+		limit = n
+	}
+	if err := storage.SetWIPLimit(m.db, m.projectID, columns[m.cursorCol], limit); err != nil {
+		log.Printf("Error saving WIP limit: %v", err)
+	}
+	m.loadLimits()
+}
+
+// toggleStrictLimits switches between refusing moves over a WIP limit and
+// asking to confirm them.
+func (m *Kanban) toggleStrictLimits() {
+	if err := storage.SetWIPStrict(m.db, m.projectID, !m.strictLimits); err != nil {
+		log.Printf("Error saving WIP limits: %v", err)
+		return
+	}
+	m.loadLimits()
+	m.message = "Moves over a WIP limit now need confirmation."
+	if m.strictLimits {
+		m.message = "Moves over a WIP limit are now refused."
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/Ceinl/Go-dashboard/internal/storage"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/google/uuid"
 )
 
@@ -19,7 +20,8 @@ type LinkSaver struct {
 	links     []storage.Link
 	input     textinput.Model
 	editing   bool
-	cursor    int
+	filter    itemFilter
+	cursor    int // index into visibleLinks
 }
 
 func NewLinkSaver(db *sql.DB, projectID string) Module {
@@ -32,6 +34,7 @@ func NewLinkSaver(db *sql.DB, projectID string) Module {
 		db:        db,
 		projectID: projectID,
 		input:     ti,
+		filter:    newItemFilter(),
 	}
 }
 
@@ -44,10 +47,33 @@ func (m *LinkSaver) Update(msg tea.Msg) (Module, tea.Cmd) {
 	if m.editing {
 		return m.updateEditing(msg)
 	}
+	if m.filter.Typing() {
+		return m.updateFiltering(msg)
+	}
 
 	return m.updateBrowsing(msg)
 }
 
+// CapturingInput reports whether the module needs every key press.
+func (m *LinkSaver) CapturingInput() bool {
+	return m.editing || m.filter.Typing()
+}
+
+func (m *LinkSaver) Filters() {}
+
+func (m *LinkSaver) updateFiltering(msg tea.Msg) (Module, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m.updateBrowsing(msg)
+	}
+
+	cmd, changed := m.filter.Update(keyMsg)
+	if changed {
+		m.cursor = 0
+	}
+	return m, cmd
+}
+
 func (m *LinkSaver) updateEditing(msg tea.Msg) (Module, tea.Cmd) {
 	var cmd tea.Cmd
 
@@ -94,7 +120,7 @@ func (m *LinkSaver) updateBrowsing(msg tea.Msg) (Module, tea.Cmd) {
 				m.cursor--
 			}
 		case "down", "j":
-			if m.cursor < len(m.links)-1 {
+			if m.cursor < len(m.visibleLinks())-1 {
 				m.cursor++
 			}
 		case "a":
@@ -112,29 +138,40 @@ func (m *LinkSaver) updateBrowsing(msg tea.Msg) (Module, tea.Cmd) {
 			m.input.Focus()
 			return m, textinput.Blink
 		case "d":
-			if len(m.links) > 0 && m.cursor < len(m.links) {
-				linkToDelete := m.links[m.cursor]
+			if linkToDelete, ok := m.selectedLink(); ok {
 				if err := storage.DeleteLink(m.db, linkToDelete.ID); err != nil {
 					log.Printf("Error deleting link: %v", err)
 				} else {
-					m.links = append(m.links[:m.cursor], m.links[m.cursor+1:]...)
-					if m.cursor >= len(m.links) && len(m.links) > 0 {
-						m.cursor = len(m.links) - 1
+					for i, link := range m.links {
+						if link.ID == linkToDelete.ID {
+							m.links = append(m.links[:i], m.links[i+1:]...)
+							break
+						}
+					}
+					visible := m.visibleLinks()
+					if m.cursor >= len(visible) && len(visible) > 0 {
+						m.cursor = len(visible) - 1
 					}
 				}
 			}
 		case "enter":
-			if len(m.links) > 0 && m.cursor < len(m.links) {
-				linkToOpen := m.links[m.cursor]
+			if linkToOpen, ok := m.selectedLink(); ok {
 				exec.Command("open", linkToOpen.URL).Start()
 			}
 		case "c":
-			if len(m.links) > 0 && m.cursor < len(m.links) {
-				linkToCopy := m.links[m.cursor]
+			if linkToCopy, ok := m.selectedLink(); ok {
 				cmd := exec.Command("pbcopy")
 				cmd.Stdin = strings.NewReader(linkToCopy.URL)
 				cmd.Run()
 			}
+		case "/":
+			m.cursor = 0
+			return m, m.filter.Start()
+		case "esc":
+			if m.filter.Active() {
+				m.filter.Reset()
+				m.cursor = 0
+			}
 		}
 	}
 	return m, nil
@@ -144,22 +181,48 @@ func (m *LinkSaver) View() string {
 	var s strings.Builder
 	s.WriteString("Link Saver\n\n")
 
-	for i, link := range m.links {
+	plain := lipgloss.NewStyle()
+	for i, link := range m.visibleLinks() {
 		cursor := " "
 		if m.cursor == i {
 			cursor = ">"
 		}
-		s.WriteString(fmt.Sprintf("%s %s: %s\n", cursor, link.Title, link.URL))
+		s.WriteString(fmt.Sprintf("%s %s: %s\n", cursor, m.filter.Highlight(link.Title, plain), m.filter.Highlight(link.URL, plain)))
 	}
 
 	if m.editing {
 		s.WriteString("\n" + m.input.View())
+	} else if filterView := m.filter.View(); filterView != "" {
+		s.WriteString("\n" + filterView)
 	}
 
-	s.WriteString("\n\n(a)dd, (p)aste, (d)elete, (c)opy, (enter) open, (j/k) navigate")
+	s.WriteString("\n\n(a)dd, (p)aste, (d)elete, (c)opy, (enter) open, (j/k) navigate, (/) filter")
 	return s.String()
 }
 
+// visibleLinks returns the links that pass the current filter.
+func (m *LinkSaver) visibleLinks() []storage.Link {
+	if !m.filter.Active() {
+		return m.links
+	}
+	var visible []storage.Link
+	for _, link := range m.links {
+		if m.filter.Matches(link.Title, link.URL) {
+			visible = append(visible, link)
+		}
+	}
+	return visible
+}
+
+// selectedLink returns the link under the cursor, if any.
+func (m *LinkSaver) selectedLink() (storage.Link, bool) {
+	visible := m.visibleLinks()
+	if m.cursor < 0 || m.cursor >= len(visible) {
+		return storage.Link{}, false
+	}
+	return visible[m.cursor], true
+}
+
 func (m *LinkSaver) loadLinks() {
 	if m.projectID == "" {
 		m.links = []storage.Link{}
@@ -172,3 +235,15 @@ func (m *LinkSaver) loadLinks() {
 	}
 	m.links = links
 }
+
+// FocusItem moves the cursor to the link with the given ID.
+func (m *LinkSaver) FocusItem(id string) bool {
+	m.filter.Reset()
+	for i, link := range m.links {
+		if link.ID == id {
+			m.cursor = i
+			return true
+		}
+	}
+	return false
+}
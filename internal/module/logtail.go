@@ -0,0 +1,578 @@
+package module
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Ceinl/Go-dashboard/internal/storage"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/google/uuid"
+)
+
+const (
+	logPollInterval = 500 * time.Millisecond
+	logMaxLines     = 5000
+)
+
+// What the Logtail input is being used for.
+const (
+	logInputNone = iota
+	logInputAdd
+	logInputInclude
+	logInputExclude
+)
+
+type logLine struct {
+	fileID string
+	text   string
+}
+
+// logLinesMsg carries the lines read by one poll of every followed file.
+type logLinesMsg struct {
+	owner      *Logtail
+	generation int
+	lines      []logLine
+	errors     map[string]error // by file ID
+}
+
+type logPollMsg struct {
+	owner      *Logtail
+	generation int
+}
+
+// severityPattern finds the log level of a line.
+var severityPattern = regexp.MustCompile(`(?i)\b(fatal|panic|crit(?:ical)?|error|err|warn(?:ing)?|info|debug|trace)\b`)
+
+var (
+	logErrorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	logWarnStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	logInfoStyle  = lipgloss.NewStyle()
+	logDebugStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
+	logDimStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+)
+
+// severityStyle colors a line by the first log level it mentions.
+func severityStyle(line string) lipgloss.Style {
+	match := severityPattern.FindString(line)
+	switch strings.ToLower(match) {
+	case "fatal", "panic", "crit", "critical", "error", "err":
+		return logErrorStyle
+	case "warn", "warning":
+		return logWarnStyle
+	case "debug", "trace":
+		return logDebugStyle
+	}
+	return logInfoStyle
+}
+
+// Logtail follows the log files configured for a project, like tail -F,
+// with regex filters and search.
+type Logtail struct {
+	db        *sql.DB
+	projectID string
+	files     []storage.LogFile
+	tailers   map[string]*fileTailer // by file ID
+	removed   []*fileTailer          // closed after the poll in progress
+	pollMu    sync.Mutex             // held by a poll in progress, see Close
+	closed    bool
+	errors    map[string]error
+	source    int // 0 shows every file, i shows files[i-1]
+
+	lines   []logLine
+	pending []logLine // read while paused
+	paused  bool
+	scroll  int // lines scrolled up from the bottom; 0 follows new lines
+
+	include *regexp.Regexp
+	exclude *regexp.Regexp
+	search  itemFilter
+	match   int // index into visibleLines of the current search match, or -1
+
+	inputMode  int
+	input      textinput.Model
+	inputError string
+
+	generation int
+	width      int
+	height     int
+}
+
+func NewLogtail(db *sql.DB, projectID string) Module {
+	ti := textinput.New()
+	ti.CharLimit = 512
+	ti.Width = 60
+
+	return &Logtail{
+		db:        db,
+		projectID: projectID,
+		tailers:   make(map[string]*fileTailer),
+		errors:    make(map[string]error),
+		search:    newItemFilter(),
+		match:     -1,
+		input:     ti,
+	}
+}
+
+func (m *Logtail) Init() tea.Cmd {
+	m.loadFiles()
+	return m.poll()
+}
+
+func (m *Logtail) loadFiles() {
+	files, err := storage.GetLogFilesForProject(m.db, m.projectID)
+	if err != nil {
+		log.Printf("Error loading log files: %v", err)
+	}
+	m.files = files
+	for _, file := range files {
+		if m.tailers[file.ID] == nil {
+			m.tailers[file.ID] = newFileTailer(expandHome(file.Path))
+		}
+	}
+	if m.source > len(m.files) {
+		m.source = 0
+	}
+}
+
+// poll reads every followed file in the background. Tailers are only
+// touched by one poll at a time: the next poll is scheduled after the
+// result of the previous one arrives.
+func (m *Logtail) poll() tea.Cmd {
+	m.generation++
+	owner, generation := m, m.generation
+	ids := make([]string, 0, len(m.files))
+	tailers := make([]*fileTailer, 0, len(m.files))
+	for _, file := range m.files {
+		ids = append(ids, file.ID)
+		tailers = append(tailers, m.tailers[file.ID])
+	}
+	return func() tea.Msg {
+		msg := logLinesMsg{owner: owner, generation: generation, errors: make(map[string]error)}
+		owner.pollMu.Lock()
+		defer owner.pollMu.Unlock()
+		if owner.closed {
+			return msg
+		}
+		for i, tailer := range tailers {
+			lines, err := tailer.poll()
+			for _, line := range lines {
+				msg.lines = append(msg.lines, logLine{fileID: ids[i], text: line})
+			}
+			if err != nil {
+				msg.errors[ids[i]] = err
+			}
+		}
+		return msg
+	}
+}
+
+// Close closes the followed files when the module is discarded, waiting for
+// a poll in progress to finish.
+func (m *Logtail) Close() {
+	m.pollMu.Lock()
+	defer m.pollMu.Unlock()
+	m.closed = true
+	for _, tailer := range m.tailers {
+		tailer.close()
+	}
+	for _, tailer := range m.removed {
+		tailer.close()
+	}
+	m.removed = nil
+}
+
+// CapturingInput reports whether the module needs every key press.
+func (m *Logtail) CapturingInput() bool {
+	return m.inputMode != logInputNone || m.search.Typing()
+}
+
+func (m *Logtail) Filters() {}
+
+func (m *Logtail) Update(msg tea.Msg) (Module, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	case logLinesMsg:
+		if msg.owner != m || msg.generation != m.generation {
+			return m, nil
+		}
+		m.receive(msg)
+		return m, tea.Tick(logPollInterval, func(time.Time) tea.Msg {
+			return logPollMsg{owner: msg.owner, generation: msg.generation}
+		})
+	case logPollMsg:
+		if msg.owner == m && msg.generation == m.generation {
+			return m, m.poll()
+		}
+	case tea.KeyMsg:
+		if m.inputMode != logInputNone {
+			return m.updateInput(msg)
+		}
+		if m.search.Typing() {
+			cmd, changed := m.search.Update(msg)
+			if changed {
+				m.findMatch(-1, false)
+			}
+			return m, cmd
+		}
+		return m.updateKeys(msg)
+	}
+	return m, nil
+}
+
+// receive stores freshly read lines. Tailers of removed files are closed
+// here, once no poll can be using them.
+func (m *Logtail) receive(msg logLinesMsg) {
+	for _, tailer := range m.removed {
+		tailer.close()
+	}
+	m.removed = nil
+	m.errors = msg.errors
+
+	if m.paused {
+		m.pending = append(m.pending, msg.lines...)
+		if len(m.pending) > logMaxLines {
+			m.pending = m.pending[len(m.pending)-logMaxLines:]
+		}
+		return
+	}
+	m.appendLines(msg.lines)
+}
+
+func (m *Logtail) appendLines(lines []logLine) {
+	if len(lines) == 0 {
+		return
+	}
+	before := len(m.visibleLines())
+	added := 0
+	for _, line := range lines {
+		if m.shows(line) {
+			added++
+		}
+	}
+	m.lines = append(m.lines, lines...)
+	if len(m.lines) > logMaxLines {
+		m.lines = m.lines[len(m.lines)-logMaxLines:]
+	}
+	after := len(m.visibleLines())
+
+	// Keep a scrolled view and the current match on the same lines instead
+	// of following.
+	if m.scroll > 0 {
+		m.scroll = min(m.scroll+added, max(after-m.bodyHeight(), 0))
+	}
+	if m.match >= 0 {
+		if m.match -= before + added - after; m.match < 0 {
+			m.match = -1
+		}
+	}
+}
+
+func (m *Logtail) updateKeys(msg tea.KeyMsg) (Module, tea.Cmd) {
+	page := max(m.bodyHeight()-1, 1)
+	switch msg.String() {
+	case "a":
+		return m, m.startInput(logInputAdd, "Path to a log file", "")
+	case "d":
+		if m.source > 0 {
+			file := m.files[m.source-1]
+			if err := storage.DeleteLogFile(m.db, file.ID); err != nil {
+				log.Printf("Error deleting log file: %v", err)
+			}
+			m.removed = append(m.removed, m.tailers[file.ID])
+			delete(m.tailers, file.ID)
+			m.lines = withoutFile(m.lines, file.ID)
+			m.pending = withoutFile(m.pending, file.ID)
+			m.match = -1
+			m.source = 0
+			m.loadFiles()
+		}
+	case "tab":
+		m.source = (m.source + 1) % (len(m.files) + 1)
+		m.scroll = 0
+		m.findMatch(-1, false)
+	case "i":
+		return m, m.startInput(logInputInclude, "Show only lines matching (regex, empty for all)", patternString(m.include))
+	case "x":
+		return m, m.startInput(logInputExclude, "Hide lines matching (regex, empty for none)", patternString(m.exclude))
+	case " ", "p":
+		m.paused = !m.paused
+		if !m.paused {
+			pending := m.pending
+			m.pending = nil
+			m.appendLines(pending)
+		}
+	case "C":
+		m.lines, m.pending = nil, nil
+		m.scroll = 0
+		m.match = -1
+	case "/":
+		return m, m.search.Start()
+	case "esc":
+		m.search.Reset()
+		m.match = -1
+	case "n":
+		m.findMatch(m.match, true)
+	case "N":
+		m.findMatch(m.match, false)
+	case "up", "k":
+		m.scrollBy(1)
+	case "down", "j":
+		m.scrollBy(-1)
+	case "pgup", "ctrl+u":
+		m.scrollBy(page)
+	case "pgdown", "ctrl+d":
+		m.scrollBy(-page)
+	case "g":
+		m.scrollBy(len(m.lines))
+	case "G":
+		m.scroll = 0
+	}
+	return m, nil
+}
+
+func (m *Logtail) scrollBy(n int) {
+	m.scroll = min(max(m.scroll+n, 0), max(len(m.visibleLines())-m.bodyHeight(), 0))
+}
+
+func withoutFile(lines []logLine, fileID string) []logLine {
+	var kept []logLine
+	for _, line := range lines {
+		if line.fileID != fileID {
+			kept = append(kept, line)
+		}
+	}
+	return kept
+}
+
+func patternString(re *regexp.Regexp) string {
+	if re == nil {
+		return ""
+	}
+	return re.String()
+}
+
+func (m *Logtail) startInput(mode int, placeholder, value string) tea.Cmd {
+	m.inputMode = mode
+	m.inputError = ""
+	m.input.Placeholder = placeholder
+	m.input.SetValue(value)
+	m.input.CursorEnd()
+	return m.input.Focus()
+}
+
+func (m *Logtail) updateInput(msg tea.KeyMsg) (Module, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		value := strings.TrimSpace(m.input.Value())
+		switch m.inputMode {
+		case logInputAdd:
+			if value != "" {
+				file := storage.LogFile{ID: uuid.New().String(), ProjectID: m.projectID, Path: value}
+				if err := storage.CreateLogFile(m.db, file); err != nil {
+					log.Printf("Error adding log file: %v", err)
+				}
+				m.loadFiles()
+			}
+		case logInputInclude, logInputExclude:
+			var re *regexp.Regexp
+			if value != "" {
+				var err error
+				if re, err = regexp.Compile(value); err != nil {
+					m.inputError = err.Error()
+					return m, nil
+				}
+			}
+			if m.inputMode == logInputInclude {
+				m.include = re
+			} else {
+				m.exclude = re
+			}
+			m.scroll = 0
+			m.findMatch(-1, false)
+		}
+		m.inputMode = logInputNone
+		m.input.Blur()
+		return m, nil
+	case tea.KeyEsc:
+		m.inputMode = logInputNone
+		m.input.Blur()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// visibleLines returns the lines of the selected source that pass the
+// include and exclude filters.
+func (m *Logtail) visibleLines() []logLine {
+	var visible []logLine
+	for _, line := range m.lines {
+		if m.shows(line) {
+			visible = append(visible, line)
+		}
+	}
+	return visible
+}
+
+// shows reports whether a line belongs to the selected source and passes the
+// include and exclude filters.
+func (m *Logtail) shows(line logLine) bool {
+	if m.source > 0 && m.source <= len(m.files) && line.fileID != m.files[m.source-1].ID {
+		return false
+	}
+	if m.include != nil && !m.include.MatchString(line.text) {
+		return false
+	}
+	return m.exclude == nil || !m.exclude.MatchString(line.text)
+}
+
+// findMatch moves to the next search match after from, or before it when
+// forward is false, and scrolls it into view. A from of -1 starts over from
+// the newest line.
+func (m *Logtail) findMatch(from int, forward bool) {
+	if !m.search.Active() {
+		m.match = -1
+		return
+	}
+	visible := m.visibleLines()
+	if from < 0 || from >= len(visible) {
+		from, forward = len(visible), false
+	}
+	step := -1
+	if forward {
+		step = 1
+	}
+	for i := from + step; i >= 0 && i < len(visible); i += step {
+		if m.search.Matches(visible[i].text) {
+			m.match = i
+			// Put the match in the middle of the view when possible.
+			m.scroll = min(max(len(visible)-1-i-m.bodyHeight()/2, 0), max(len(visible)-m.bodyHeight(), 0))
+			return
+		}
+	}
+	if from == len(visible) {
+		m.match = -1
+	}
+}
+
+func (m *Logtail) bodyHeight() int {
+	return max(m.height-14, 3)
+}
+
+func (m *Logtail) fileName(id string) string {
+	for _, file := range m.files {
+		if file.ID == id {
+			return filepath.Base(file.Path)
+		}
+	}
+	return "?"
+}
+
+func (m *Logtail) View() string {
+	if m.width == 0 {
+		return "loading..."
+	}
+	if m.inputMode != logInputNone {
+		view := m.input.View()
+		if m.inputError != "" {
+			view += "\n" + logErrorStyle.Render(m.inputError)
+		}
+		return lipgloss.Place(m.width, m.height-10, lipgloss.Center, lipgloss.Center, view)
+	}
+	if len(m.files) == 0 {
+		return logDimStyle.Render("No log files. Press a to follow one.")
+	}
+
+	// Header: the sources, with the selected one highlighted.
+	tabs := []string{"All"}
+	for _, file := range m.files {
+		name := filepath.Base(file.Path)
+		if m.errors[file.ID] != nil {
+			name += " ⚠"
+		}
+		tabs = append(tabs, name)
+	}
+	for i, tab := range tabs {
+		style := lipgloss.NewStyle().Padding(0, 1)
+		if i == m.source {
+			style = style.Background(lipgloss.Color("57"))
+		}
+		tabs[i] = style.Render(tab)
+	}
+	header := strings.Join(tabs, " ")
+
+	var status []string
+	if m.paused {
+		status = append(status, logWarnStyle.Render(fmt.Sprintf("PAUSED, %d new lines", len(m.pending))))
+	}
+	if m.include != nil {
+		status = append(status, "include /"+m.include.String()+"/")
+	}
+	if m.exclude != nil {
+		status = append(status, "exclude /"+m.exclude.String()+"/")
+	}
+	if m.source > 0 {
+		file := m.files[m.source-1]
+		status = append(status, file.Path)
+		if err := m.errors[file.ID]; err != nil {
+			status = append(status, logErrorStyle.Render(err.Error()))
+		}
+	}
+
+	visible := m.visibleLines()
+	height := m.bodyHeight()
+	end := max(len(visible)-m.scroll, 0)
+	start := max(end-height, 0)
+	showFile := m.source == 0 && len(m.files) > 1
+	var body []string
+	for i := start; i < end; i++ {
+		line := visible[i]
+		prefix := ""
+		if showFile {
+			prefix = logDimStyle.Render(fmt.Sprintf("%-12s ", truncate(m.fileName(line.fileID), 12)))
+		}
+		text := truncate(line.text, m.width-lipgloss.Width(prefix)-4)
+		style := severityStyle(line.text)
+		if i == m.match {
+			style = style.Background(lipgloss.Color("57"))
+		}
+		body = append(body, prefix+m.search.Highlight(text, style))
+	}
+	if len(visible) == 0 {
+		body = append(body, logDimStyle.Render("Waiting for lines..."))
+	}
+
+	pane := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		Padding(0, 1).
+		Width(m.width - 4).
+		Height(height).
+		Render(strings.Join(body, "\n"))
+
+	footer := "(a)dd file, (d)elete, (tab) source, (i)nclude, e(x)clude, (space) pause, (/) search, (n/N) next/prev, (C)lear"
+	if m.scroll > 0 {
+		footer = fmt.Sprintf("%d lines below  ", m.scroll) + footer
+	}
+	if filterView := m.search.View(); filterView != "" {
+		footer = filterView + "  " + footer
+	}
+
+	parts := []string{header}
+	if len(status) > 0 {
+		parts = append(parts, logDimStyle.Render(strings.Join(status, "  ")))
+	}
+	parts = append(parts, pane, logDimStyle.Render(footer))
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+}
@@ -0,0 +1,218 @@
+package module
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Ceinl/Go-dashboard/internal/storage"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/google/uuid"
+)
+
+func appendToFile(t *testing.T, path, content string) {
+	t.Helper()
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatalf("failed to open %s: %v", path, err)
+	}
+	defer file.Close()
+	if _, err := file.WriteString(content); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func pollLines(t *testing.T, tailer *fileTailer) []string {
+	t.Helper()
+	lines, err := tailer.poll()
+	if err != nil {
+		t.Fatalf("poll failed: %v", err)
+	}
+	return lines
+}
+
+func TestFileTailer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	tailer := newFileTailer(path)
+	defer tailer.close()
+
+	if _, err := tailer.poll(); err == nil {
+		t.Fatal("expected an error for a missing file")
+	}
+
+	appendToFile(t, path, "one\ntwo\nthr")
+	if got := pollLines(t, tailer); !reflect.DeepEqual(got, []string{"one", "two"}) {
+		t.Errorf("expected the complete lines, got %q", got)
+	}
+	appendToFile(t, path, "ee\r\nfour\n")
+	if got := pollLines(t, tailer); !reflect.DeepEqual(got, []string{"three", "four"}) {
+		t.Errorf("expected the partial line to be completed, got %q", got)
+	}
+	if got := pollLines(t, tailer); len(got) != 0 {
+		t.Errorf("expected nothing new, got %q", got)
+	}
+
+	// Truncated in place, as with "> app.log".
+	if err := os.WriteFile(path, []byte("fresh\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := pollLines(t, tailer); !reflect.DeepEqual(got, []string{"fresh"}) {
+		t.Errorf("expected the file to be read from the start after truncation, got %q", got)
+	}
+
+	// Rotated: the old file gets a last line after being renamed, then a
+	// new file is created under the same path.
+	appendToFile(t, path, "before rotation\n")
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	appendToFile(t, path+".1", "last words\n")
+	if got := pollLines(t, tailer); !reflect.DeepEqual(got, []string{"before rotation", "last words"}) {
+		t.Errorf("expected the old file to be drained while the path is missing, got %q", got)
+	}
+	appendToFile(t, path, "new file\n")
+	if got := pollLines(t, tailer); !reflect.DeepEqual(got, []string{"new file"}) {
+		t.Errorf("expected the new file after rotation, got %q", got)
+	}
+}
+
+func TestFileTailerStartsNearTheEnd(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "big.log")
+	line := strings.Repeat("x", 99) + "\n"
+	appendToFile(t, path, strings.Repeat(line, 1000)+"last\n")
+
+	tailer := newFileTailer(path)
+	defer tailer.close()
+	lines := pollLines(t, tailer)
+	if len(lines) == 0 || len(lines) > logTailInitialBytes/100+1 || lines[len(lines)-1] != "last" {
+		t.Fatalf("expected only the last lines, got %d lines", len(lines))
+	}
+	for _, l := range lines[:len(lines)-1] {
+		if len(l) != 99 {
+			t.Fatalf("expected whole lines only, got %q", l)
+		}
+	}
+}
+
+func TestLogtailFiltersAndSearch(t *testing.T) {
+	db, projectID := setupTestDB(t)
+	path := filepath.Join(t.TempDir(), "service.log")
+	appendToFile(t, path, "INFO started\nDEBUG cache warm\nERROR db down\nWARN retrying db\nINFO healthy\n")
+	if err := storage.CreateLogFile(db, storage.LogFile{ID: uuid.New().String(), ProjectID: projectID, Path: path}); err != nil {
+		t.Fatalf("failed to add log file: %v", err)
+	}
+
+	m := NewLogtail(db, projectID)
+	cmd := m.Init()
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m, _ = m.Update(cmd())
+	logtail := m.(*Logtail)
+	if got := len(logtail.visibleLines()); got != 5 {
+		t.Fatalf("expected 5 lines, got %d", got)
+	}
+
+	texts := func() []string {
+		var texts []string
+		for _, line := range logtail.visibleLines() {
+			texts = append(texts, line.text)
+		}
+		return texts
+	}
+
+	m = typeKeys(m, "i", "d", "b", "enter")
+	if got := texts(); !reflect.DeepEqual(got, []string{"ERROR db down", "WARN retrying db"}) {
+		t.Errorf("unexpected included lines %q", got)
+	}
+	m = typeKeys(m, "x", "^", "W", "enter")
+	if got := texts(); !reflect.DeepEqual(got, []string{"ERROR db down"}) {
+		t.Errorf("unexpected lines after exclude %q", got)
+	}
+
+	// An invalid pattern keeps the input open and the old filter applied.
+	m = typeKeys(m, "x")
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlU})
+	m = typeKeys(m, "(", "enter")
+	if logtail.inputError == "" || logtail.exclude.String() != "^W" {
+		t.Errorf("expected an error for an invalid regex, got %q with exclude %v", logtail.inputError, logtail.exclude)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+
+	for _, key := range []string{"i", "x"} {
+		m = typeKeys(m, key)
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlU})
+		m = typeKeys(m, "enter")
+	}
+	if got := len(texts()); got != 5 {
+		t.Fatalf("expected the filters to be cleared, got %d lines", got)
+	}
+
+	m = typeKeys(m, "/", "i", "n", "f", "o", "enter")
+	if logtail.match != 4 {
+		t.Errorf("expected the newest match first, got %d", logtail.match)
+	}
+	m = typeKeys(m, "N")
+	if logtail.match != 0 {
+		t.Errorf("expected the previous match, got %d", logtail.match)
+	}
+
+	// While paused, new lines are held back.
+	m = typeKeys(m, " ")
+	appendToFile(t, path, "ERROR again\n")
+	m, _ = m.Update(logtail.poll()())
+	if len(logtail.pending) != 1 || len(logtail.lines) != 5 {
+		t.Errorf("expected 1 pending line, got %d pending and %d shown", len(logtail.pending), len(logtail.lines))
+	}
+	m = typeKeys(m, " ")
+	if len(logtail.pending) != 0 || len(logtail.lines) != 6 {
+		t.Errorf("expected the pending line to be shown on resume, got %d shown", len(logtail.lines))
+	}
+	if !strings.Contains(m.View(), "ERROR again") {
+		t.Error("expected the new line in the view")
+	}
+}
+
+func TestSeverityStyle(t *testing.T) {
+	tests := map[string]lipgloss.Style{
+		"2024-01-01 ERROR failed":     logErrorStyle,
+		"level=warn msg=slow":         logWarnStyle,
+		"[debug] cache hit":           logDebugStyle,
+		"terrorist is not a severity": logInfoStyle,
+	}
+	for line, want := range tests {
+		if got := severityStyle(line); !reflect.DeepEqual(got, want) {
+			t.Errorf("%q: unexpected style", line)
+		}
+	}
+}
+
+func TestLogtailClosesFilesWhenDiscarded(t *testing.T) {
+	db, projectID := setupTestDB(t)
+	path := filepath.Join(t.TempDir(), "service.log")
+	appendToFile(t, path, "INFO started\n")
+	file := storage.LogFile{ID: uuid.New().String(), ProjectID: projectID, Path: path}
+	if err := storage.CreateLogFile(db, file); err != nil {
+		t.Fatalf("failed to add log file: %v", err)
+	}
+
+	m := NewLogtail(db, projectID)
+	cmd := m.Init()
+	m, _ = m.Update(cmd())
+	logtail := m.(*Logtail)
+	tailer := logtail.tailers[file.ID]
+	if tailer.file == nil {
+		t.Fatal("expected the log file to be open")
+	}
+
+	poll := logtail.poll()
+	m.(Closer).Close()
+	if tailer.file != nil {
+		t.Error("expected Close to close the log file")
+	}
+	// A poll that was already scheduled doesn't reopen it.
+	if msg := poll().(logLinesMsg); len(msg.lines) != 0 || tailer.file != nil {
+		t.Errorf("expected no reads after Close, got %q", msg.lines)
+	}
+}
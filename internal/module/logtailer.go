@@ -0,0 +1,135 @@
+package module
+
+import (
+	"bytes"
+	"io"
+	"os"
+)
+
+// logTailInitialBytes is how much of an existing file is shown when it is
+// first opened.
+const logTailInitialBytes = 16 * 1024
+
+// fileTailer follows a growing log file like tail -F. It notices when the
+// file is truncated in place, and when it is rotated, i.e. renamed or
+// removed and recreated under the same path; the rest of the old file is
+// read before switching to the new one.
+type fileTailer struct {
+	path    string
+	file    *os.File
+	info    os.FileInfo
+	offset  int64
+	partial []byte // an unterminated last line, completed by a later read
+	opened  bool   // the file has been opened at least once
+}
+
+func newFileTailer(path string) *fileTailer {
+	return &fileTailer{path: path}
+}
+
+// poll returns the complete lines written since the last poll. An error
+// means the file can't be read right now; polling again retries.
+func (t *fileTailer) poll() ([]string, error) {
+	info, err := os.Stat(t.path)
+	if err != nil {
+		if t.file == nil {
+			return nil, err
+		}
+		// Removed during a rotation: keep reading the old file until a new
+		// one appears.
+		return t.read()
+	}
+
+	var lines []string
+	if t.file != nil && !os.SameFile(t.info, info) {
+		lines, _ = t.read()
+		if len(t.partial) > 0 {
+			lines = append(lines, string(t.partial))
+		}
+		t.close()
+	}
+
+	if t.file == nil {
+		if err := t.open(info); err != nil {
+			return lines, err
+		}
+	} else if info.Size() < t.offset {
+		// Truncated in place.
+		if _, err := t.file.Seek(0, io.SeekStart); err != nil {
+			return lines, err
+		}
+		t.offset = 0
+		t.partial = nil
+	}
+	t.info = info
+
+	more, err := t.read()
+	return append(lines, more...), err
+}
+
+// open opens the file. The first time, only its last few lines are read;
+// a file that replaces a rotated one is read from the start.
+func (t *fileTailer) open(info os.FileInfo) error {
+	file, err := os.Open(t.path)
+	if err != nil {
+		return err
+	}
+	t.file, t.info, t.offset, t.partial = file, info, 0, nil
+
+	if !t.opened && info.Size() > logTailInitialBytes {
+		t.offset = info.Size() - logTailInitialBytes
+		if _, err := file.Seek(t.offset, io.SeekStart); err != nil {
+			return err
+		}
+		// Skip the line cut in half by seeking.
+		if err := t.skipLine(); err != nil {
+			return err
+		}
+	}
+	t.opened = true
+	return nil
+}
+
+// skipLine discards bytes up to and including the next newline.
+func (t *fileTailer) skipLine() error {
+	buf := make([]byte, 1)
+	for {
+		n, err := t.file.Read(buf)
+		t.offset += int64(n)
+		if n > 0 && buf[0] == '\n' || err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// read reads to the end of the file and splits off the complete lines.
+func (t *fileTailer) read() ([]string, error) {
+	data, err := io.ReadAll(t.file)
+	t.offset += int64(len(data))
+	if len(data) == 0 {
+		return nil, err
+	}
+
+	data = append(t.partial, data...)
+	var lines []string
+	for {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			break
+		}
+		lines = append(lines, string(bytes.TrimSuffix(data[:i], []byte("\r"))))
+		data = data[i+1:]
+	}
+	t.partial = append([]byte(nil), data...)
+	return lines, err
+}
+
+func (t *fileTailer) close() {
+	if t.file != nil {
+		t.file.Close()
+		t.file = nil
+	}
+}
@@ -0,0 +1,119 @@
+package module
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	mdHeadingStyles = []lipgloss.Style{
+		lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212")).Underline(true),
+		lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("63")),
+		lipgloss.NewStyle().Bold(true),
+	}
+	mdCodeBlockStyle = lipgloss.NewStyle().Background(lipgloss.Color("236")).Foreground(lipgloss.Color("252")).Padding(0, 1)
+	mdCodeStyle      = lipgloss.NewStyle().Background(lipgloss.Color("236")).Foreground(lipgloss.Color("203"))
+	mdBoldStyle      = lipgloss.NewStyle().Bold(true)
+	mdItalicStyle    = lipgloss.NewStyle().Italic(true)
+	mdLinkStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("39")).Underline(true)
+	mdURLStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	mdQuoteStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Italic(true)
+	mdBulletStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("212"))
+
+	mdHeading     = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	mdOrderedItem = regexp.MustCompile(`^(\s*)(\d+)[.)]\s+(.*)$`)
+	mdBulletItem  = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	mdInline      = regexp.MustCompile("`[^`]+`|\\*\\*[^*]+\\*\\*|__[^_]+__|\\*[^*\\s][^*]*\\*|\\[[^\\]]+\\]\\([^)\\s]+\\)")
+	mdLink        = regexp.MustCompile(`^\[([^\]]+)\]\(([^)\s]+)\)$`)
+)
+
+// renderMarkdown renders the subset of Markdown used in notes for the
+// terminal: headings, bullet and numbered lists, fenced code blocks, block
+// quotes, rules and inline code, emphasis and links. Paragraphs are wrapped to
+// width.
+func renderMarkdown(src string, width int) string {
+	if width < 10 {
+		width = 10
+	}
+
+	var out []string
+	var code []string
+	inCode := false
+
+	for _, line := range strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") {
+			if inCode {
+				out = append(out, mdCodeBlockStyle.Width(width).Render(strings.Join(code, "\n")))
+				code = nil
+			}
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			code = append(code, line)
+			continue
+		}
+
+		switch {
+		case trimmed == "":
+			out = append(out, "")
+		case mdHeading.MatchString(trimmed):
+			parts := mdHeading.FindStringSubmatch(trimmed)
+			level := len(parts[1])
+			if level > len(mdHeadingStyles) {
+				level = len(mdHeadingStyles)
+			}
+			out = append(out, mdHeadingStyles[level-1].Width(width).Render(strings.TrimRight(parts[2], " #")))
+		case trimmed == "---" || trimmed == "***" || trimmed == "___":
+			out = append(out, mdURLStyle.Render(strings.Repeat("─", width)))
+		case strings.HasPrefix(trimmed, ">"):
+			text := strings.TrimSpace(strings.TrimPrefix(trimmed, ">"))
+			out = append(out, mdQuoteStyle.Width(width-2).Render("│ "+renderInline(text)))
+		case mdBulletItem.MatchString(line):
+			parts := mdBulletItem.FindStringSubmatch(line)
+			out = append(out, renderListItem(parts[1], mdBulletStyle.Render("•"), parts[2], width))
+		case mdOrderedItem.MatchString(line):
+			parts := mdOrderedItem.FindStringSubmatch(line)
+			out = append(out, renderListItem(parts[1], mdBulletStyle.Render(parts[2]+"."), parts[3], width))
+		default:
+			out = append(out, lipgloss.NewStyle().Width(width).Render(renderInline(trimmed)))
+		}
+	}
+
+	// An unterminated fence still shows its contents.
+	if inCode && len(code) > 0 {
+		out = append(out, mdCodeBlockStyle.Width(width).Render(strings.Join(code, "\n")))
+	}
+
+	return strings.Join(out, "\n")
+}
+
+func renderListItem(indent, marker, text string, width int) string {
+	prefix := strings.Repeat(" ", len(indent)) + marker + " "
+	body := lipgloss.NewStyle().Width(width - lipgloss.Width(prefix)).Render(renderInline(text))
+	return lipgloss.JoinHorizontal(lipgloss.Top, prefix, body)
+}
+
+// renderInline styles code spans, bold and italic text and links.
+func renderInline(text string) string {
+	return mdInline.ReplaceAllStringFunc(text, func(match string) string {
+		switch {
+		case strings.HasPrefix(match, "`"):
+			return mdCodeStyle.Render(strings.Trim(match, "`"))
+		case strings.HasPrefix(match, "**"), strings.HasPrefix(match, "__"):
+			return mdBoldStyle.Render(match[2 : len(match)-2])
+		case strings.HasPrefix(match, "["):
+			parts := mdLink.FindStringSubmatch(match)
+			if parts == nil {
+				return match
+			}
+			return mdLinkStyle.Render(parts[1]) + " " + mdURLStyle.Render("("+parts[2]+")")
+		default:
+			return mdItalicStyle.Render(match[1 : len(match)-1])
+		}
+	})
+}
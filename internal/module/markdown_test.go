@@ -0,0 +1,46 @@
+package module
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	src := strings.Join([]string{
+		"# Standup",
+		"Talked about **rate limits** and `retry_after`.",
+		"",
+		"- first item",
+		"2. second item",
+		"> quoted",
+		"See [the docs](https://example.com/docs).",
+		"```",
+		"go test ./...",
+		"```",
+		"snake_case_names stay intact",
+	}, "\n")
+
+	out := ansi.Strip(renderMarkdown(src, 60))
+
+	for _, want := range []string{
+		"Standup",
+		"Talked about rate limits and retry_after.",
+		"• first item",
+		"2. second item",
+		"│ quoted",
+		"See the docs (https://example.com/docs).",
+		"go test ./...",
+		"snake_case_names stay intact",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("rendered output is missing %q:\n%s", want, out)
+		}
+	}
+	for _, unwanted := range []string{"# Standup", "**", "```", "[the docs]"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("rendered output still contains markup %q:\n%s", unwanted, out)
+		}
+	}
+}
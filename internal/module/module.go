@@ -8,3 +8,46 @@ type Module interface {
 	Update(msg tea.Msg) (Module, tea.Cmd)
 	View() string
 }
+
+// ItemFocuser is implemented by modules that can move their cursor to a
+// specific item, e.g. when jumping to a search result.
+type ItemFocuser interface {
+	FocusItem(id string) bool
+}
+
+// InputCapturer is implemented by modules that sometimes need every key
+// press, e.g. while a text input or filter has focus. Global keybindings
+// are suspended while CapturingInput returns true.
+type InputCapturer interface {
+	CapturingInput() bool
+}
+
+// Filterer is implemented by modules that use "/" to filter or search their
+// own items. In the other modules "/" opens the global search.
+type Filterer interface {
+	Filters()
+}
+
+// Persistent is implemented by modules that don't belong to a single
+// project. They are created once and reused when switching projects or
+// workspaces, so that long-running state such as a timer survives.
+type Persistent interface {
+	Persistent()
+}
+
+// Closer is implemented by modules that hold resources, such as open files,
+// to release when the module is discarded on a project or workspace switch.
+// Persistent modules are never discarded.
+type Closer interface {
+	Close()
+}
+
+// StatusReporter is implemented by modules that show a short indicator in
+// the status bar, whether or not they are the module on screen.
+type StatusReporter interface {
+	StatusText() string
+}
+
+// TimeTrackingMsg is sent after a time entry is started or stopped, so that
+// the status bar and the other modules can refresh.
+type TimeTrackingMsg struct{}
@@ -0,0 +1,426 @@
+package module
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/Ceinl/Go-dashboard/internal/storage"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/google/uuid"
+)
+
+type notesMode int
+
+const (
+	notesBrowsing notesMode = iota
+	notesNaming             // typing the title of a new or renamed note
+	notesEditing            // editing the selected note in the textarea
+)
+
+// Notes keeps per-project Markdown notes, with a rendered preview of the
+// selected note next to the list.
+type Notes struct {
+	db         *sql.DB
+	projectID  string
+	notes      []storage.Note
+	cursor     int // index into visibleNotes
+	filter     itemFilter
+	mode       notesMode
+	renaming   bool
+	titleInput textinput.Model
+	editor     textarea.Model
+	preview    viewport.Model
+	width      int
+	height     int
+}
+
+// noteEditedMsg is sent when the external editor opened with "E" exits.
+type noteEditedMsg struct {
+	noteID  string
+	content string
+	err     error
+}
+
+func NewNotes(db *sql.DB, projectID string) Module {
+	ti := textinput.New()
+	ti.Placeholder = "Note title"
+	ti.CharLimit = 100
+	ti.Width = 40
+
+	editor := textarea.New()
+	editor.Placeholder = "Write Markdown..."
+	editor.MaxHeight = 0
+	editor.ShowLineNumbers = false
+
+	return &Notes{
+		db:         db,
+		projectID:  projectID,
+		filter:     newItemFilter(),
+		titleInput: ti,
+		editor:     editor,
+		preview:    viewport.New(0, 0),
+	}
+}
+
+func (m *Notes) Init() tea.Cmd {
+	m.loadNotes()
+	return nil
+}
+
+// CapturingInput reports whether the module needs every key press.
+func (m *Notes) CapturingInput() bool {
+	return m.mode != notesBrowsing || m.filter.Typing()
+}
+
+func (m *Notes) Filters() {}
+
+func (m *Notes) Update(msg tea.Msg) (Module, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.editor.SetWidth(m.width*2/3 - 4)
+		m.editor.SetHeight(m.height - 12)
+		m.preview.Width = m.width*2/3 - 4
+		m.preview.Height = m.height - 12
+		m.refreshPreview()
+		return m, nil
+	case noteEditedMsg:
+		m.finishExternalEdit(msg)
+		return m, nil
+	}
+
+	switch m.mode {
+	case notesNaming:
+		return m.updateNaming(msg)
+	case notesEditing:
+		return m.updateEditing(msg)
+	}
+	if m.filter.Typing() {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			cmd, changed := m.filter.Update(keyMsg)
+			if changed {
+				m.cursor = 0
+				m.refreshPreview()
+			}
+			return m, cmd
+		}
+	}
+	return m.updateBrowsing(msg)
+}
+
+func (m *Notes) updateBrowsing(msg tea.Msg) (Module, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+			m.refreshPreview()
+		}
+	case "down", "j":
+		if m.cursor < len(m.visibleNotes())-1 {
+			m.cursor++
+			m.refreshPreview()
+		}
+	case "ctrl+d", "pgdown":
+		m.preview.HalfPageDown()
+	case "ctrl+u", "pgup":
+		m.preview.HalfPageUp()
+	case "n":
+		m.mode = notesNaming
+		m.renaming = false
+		m.titleInput.Reset()
+		return m, m.titleInput.Focus()
+	case "r":
+		if note, ok := m.selectedNote(); ok {
+			m.mode = notesNaming
+			m.renaming = true
+			m.titleInput.SetValue(note.Title)
+			return m, m.titleInput.Focus()
+		}
+	case "enter", "e":
+		if note, ok := m.selectedNote(); ok {
+			m.mode = notesEditing
+			m.editor.SetValue(note.Content)
+			return m, m.editor.Focus()
+		}
+	case "E":
+		if note, ok := m.selectedNote(); ok {
+			return m, m.openExternalEditor(note)
+		}
+	case "d":
+		m.deleteNote()
+	case "/":
+		m.cursor = 0
+		return m, m.filter.Start()
+	case "esc":
+		if m.filter.Active() {
+			m.filter.Reset()
+			m.cursor = 0
+			m.refreshPreview()
+		}
+	}
+	return m, nil
+}
+
+func (m *Notes) updateNaming(msg tea.Msg) (Module, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "enter":
+			title := strings.TrimSpace(m.titleInput.Value())
+			m.titleInput.Blur()
+			m.mode = notesBrowsing
+			if title == "" {
+				return m, nil
+			}
+			if m.renaming {
+				m.renameNote(title)
+				return m, nil
+			}
+			if m.createNote(title) {
+				// Go straight to writing the new note.
+				m.mode = notesEditing
+				m.editor.Reset()
+				return m, m.editor.Focus()
+			}
+			return m, nil
+		case "esc":
+			m.titleInput.Blur()
+			m.mode = notesBrowsing
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.titleInput, cmd = m.titleInput.Update(msg)
+	return m, cmd
+}
+
+func (m *Notes) updateEditing(msg tea.Msg) (Module, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "ctrl+s":
+			m.saveContent(m.editor.Value())
+			m.editor.Blur()
+			m.mode = notesBrowsing
+			return m, nil
+		case "esc":
+			m.editor.Blur()
+			m.mode = notesBrowsing
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.editor, cmd = m.editor.Update(msg)
+	return m, cmd
+}
+
+// openExternalEditor suspends the dashboard while $EDITOR (or vi) edits the
+// note.
+func (m *Notes) openExternalEditor(note storage.Note) tea.Cmd {
+	return editExternally(note.Content, "dashboard-note-*.md", func(content string, err error) tea.Msg {
+		return noteEditedMsg{noteID: note.ID, content: content, err: err}
+	})
+}
+
+func (m *Notes) finishExternalEdit(msg noteEditedMsg) {
+	if msg.err != nil {
+		log.Printf("Error editing note: %v", msg.err)
+		return
+	}
+	for _, note := range m.notes {
+		if note.ID == msg.noteID {
+			note.Content = msg.content
+			m.updateNote(note)
+			return
+		}
+	}
+}
+
+func (m *Notes) createNote(title string) bool {
+	if m.projectID == "" {
+		return false
+	}
+	note := storage.Note{ID: uuid.New().String(), ProjectID: m.projectID, Title: title}
+	if err := storage.CreateNote(m.db, note); err != nil {
+		log.Printf("Error creating note: %v", err)
+		return false
+	}
+	m.filter.Reset()
+	m.loadNotes()
+	m.focusNote(note.ID)
+	return true
+}
+
+func (m *Notes) renameNote(title string) {
+	if note, ok := m.selectedNote(); ok {
+		note.Title = title
+		m.updateNote(note)
+	}
+}
+
+func (m *Notes) saveContent(content string) {
+	if note, ok := m.selectedNote(); ok {
+		note.Content = content
+		m.updateNote(note)
+	}
+}
+
+func (m *Notes) updateNote(note storage.Note) {
+	if err := storage.UpdateNote(m.db, note); err != nil {
+		log.Printf("Error updating note: %v", err)
+		return
+	}
+	m.loadNotes()
+	m.focusNote(note.ID)
+}
+
+func (m *Notes) deleteNote() {
+	note, ok := m.selectedNote()
+	if !ok {
+		return
+	}
+	if err := storage.DeleteNote(m.db, note.ID); err != nil {
+		log.Printf("Error deleting note: %v", err)
+		return
+	}
+	m.loadNotes()
+	if visible := m.visibleNotes(); m.cursor >= len(visible) && len(visible) > 0 {
+		m.cursor = len(visible) - 1
+	}
+	m.refreshPreview()
+}
+
+// focusNote moves the cursor onto a note, e.g. after saving reorders the list.
+func (m *Notes) focusNote(id string) {
+	for i, note := range m.visibleNotes() {
+		if note.ID == id {
+			m.cursor = i
+		}
+	}
+	m.refreshPreview()
+}
+
+// visibleNotes returns the notes that pass the current filter.
+func (m *Notes) visibleNotes() []storage.Note {
+	if !m.filter.Active() {
+		return m.notes
+	}
+	var visible []storage.Note
+	for _, note := range m.notes {
+		if m.filter.Matches(note.Title, note.Content) {
+			visible = append(visible, note)
+		}
+	}
+	return visible
+}
+
+// selectedNote returns the note under the cursor, if any.
+func (m *Notes) selectedNote() (storage.Note, bool) {
+	visible := m.visibleNotes()
+	if m.cursor < 0 || m.cursor >= len(visible) {
+		return storage.Note{}, false
+	}
+	return visible[m.cursor], true
+}
+
+func (m *Notes) refreshPreview() {
+	note, ok := m.selectedNote()
+	if !ok {
+		m.preview.SetContent("")
+		return
+	}
+	m.preview.SetContent(renderMarkdown(note.Content, m.preview.Width))
+	m.preview.GotoTop()
+}
+
+func (m *Notes) loadNotes() {
+	if m.projectID == "" {
+		m.notes = []storage.Note{}
+		return
+	}
+	notes, err := storage.GetNotesForProject(m.db, m.projectID)
+	if err != nil {
+		log.Printf("Error loading notes: %v", err)
+		return
+	}
+	m.notes = notes
+	m.refreshPreview()
+}
+
+func (m *Notes) View() string {
+	if m.width == 0 {
+		return "loading..."
+	}
+
+	listWidth := m.width/3 - 2
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	selectedStyle := lipgloss.NewStyle().Background(lipgloss.Color("57"))
+
+	var items []string
+	items = append(items, lipgloss.NewStyle().Bold(true).Render("Notes"), "")
+	for i, note := range m.visibleNotes() {
+		base := lipgloss.NewStyle()
+		if i == m.cursor {
+			base = selectedStyle
+		}
+		title := m.filter.Highlight(note.Title, base)
+		line := lipgloss.NewStyle().Width(listWidth).Render(title)
+		if !note.UpdatedAt.IsZero() {
+			line += "\n" + dimStyle.Render(note.UpdatedAt.Local().Format("2006-01-02 15:04"))
+		}
+		items = append(items, line)
+	}
+	if len(m.notes) == 0 {
+		items = append(items, dimStyle.Render("No notes yet. Press n to create one."))
+	}
+	if filterView := m.filter.View(); filterView != "" {
+		items = append(items, "", filterView)
+	}
+	if m.mode == notesNaming {
+		items = append(items, "", m.titleInput.View())
+	}
+
+	var right string
+	switch {
+	case m.mode == notesEditing:
+		right = m.editor.View()
+	case len(m.visibleNotes()) > 0:
+		right = m.preview.View()
+	default:
+		right = dimStyle.Render("Select a note to preview it.")
+	}
+
+	listView := lipgloss.NewStyle().Width(listWidth).Height(m.height - 10).Render(strings.Join(items, "\n"))
+	rightView := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		Padding(0, 1).
+		Width(m.width*2/3 - 2).
+		Height(m.height - 10).
+		Render(right)
+
+	var help string
+	switch m.mode {
+	case notesEditing:
+		help = "(ctrl+s) save, (esc) cancel"
+	case notesNaming:
+		help = "(enter) confirm, (esc) cancel"
+	default:
+		help = "(n)ew, (enter) edit, (E) edit in $EDITOR, (r)ename, (d)elete, (/) filter, (ctrl+d/u) scroll"
+	}
+
+	mainView := lipgloss.JoinHorizontal(lipgloss.Top, listView, "  ", rightView)
+	helpView := lipgloss.NewStyle().Width(m.width).Align(lipgloss.Center).Render(fmt.Sprintf("\n%s", help))
+	return lipgloss.JoinVertical(lipgloss.Left, mainView, helpView)
+}
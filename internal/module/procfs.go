@@ -0,0 +1,369 @@
+package module
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cpuTimes are the cumulative jiffies of one CPU line in /proc/stat.
+type cpuTimes struct {
+	Idle  uint64
+	Total uint64
+}
+
+type memInfo struct {
+	Total     uint64 // bytes
+	Available uint64
+	SwapTotal uint64
+	SwapFree  uint64
+}
+
+type mountUsage struct {
+	Path  string
+	Total uint64 // bytes
+	Free  uint64
+}
+
+type netCounters struct {
+	RxBytes uint64
+	TxBytes uint64
+}
+
+type procTimes struct {
+	PID   int
+	Name  string
+	Ticks uint64 // user + system clock ticks
+	RSS   uint64 // bytes
+}
+
+// sysSample is one reading of the system counters. Sections whose files
+// couldn't be read are left empty and their error recorded in Errors.
+type sysSample struct {
+	At     time.Time
+	CPUs   []cpuTimes // index 0 is the total over all cores
+	Load   [3]float64
+	Mem    memInfo
+	Mounts []mountUsage
+	Net    map[string]netCounters
+	Procs  map[int]procTimes
+	Errors map[string]error
+}
+
+// procReader reads system statistics from a /proc tree. Root is "/proc"
+// outside of tests. Statfs reports the total and free bytes of a mount.
+type procReader struct {
+	Root   string
+	Statfs func(path string) (total, free uint64, err error)
+}
+
+// ignoredFilesystems are virtual filesystems left out of the disk usage.
+var ignoredFilesystems = map[string]bool{
+	"proc": true, "sysfs": true, "devtmpfs": true, "devpts": true, "tmpfs": true, "cgroup": true,
+	"cgroup2": true, "securityfs": true, "pstore": true, "debugfs": true, "tracefs": true, "mqueue": true,
+	"hugetlbfs": true, "configfs": true, "fusectl": true, "bpf": true, "autofs": true, "binfmt_misc": true,
+	"nsfs": true, "overlay": true, "squashfs": true, "ramfs": true, "rpc_pipefs": true,
+}
+
+func (r procReader) path(parts ...string) string {
+	return filepath.Join(append([]string{r.Root}, parts...)...)
+}
+
+// sample reads everything at once.
+func (r procReader) sample(now time.Time) sysSample {
+	s := sysSample{At: now, Errors: make(map[string]error)}
+	var err error
+	if s.CPUs, err = r.readCPU(); err != nil {
+		s.Errors["cpu"] = err
+	}
+	if s.Load, err = r.readLoad(); err != nil {
+		s.Errors["load"] = err
+	}
+	if s.Mem, err = r.readMemory(); err != nil {
+		s.Errors["memory"] = err
+	}
+	if s.Mounts, err = r.readMounts(); err != nil {
+		s.Errors["disk"] = err
+	}
+	if s.Net, err = r.readNetwork(); err != nil {
+		s.Errors["network"] = err
+	}
+	if s.Procs, err = r.readProcesses(); err != nil {
+		s.Errors["processes"] = err
+	}
+	return s
+}
+
+func (r procReader) readCPU() ([]cpuTimes, error) {
+	file, err := os.Open(r.path("stat"))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var cpus []cpuTimes
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 5 || !strings.HasPrefix(fields[0], "cpu") {
+			continue
+		}
+		var times cpuTimes
+		for i, field := range fields[1:] {
+			v, err := strconv.ParseUint(field, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("bad /proc/stat line %q", scanner.Text())
+			}
+			// guest and guest_nice are already included in user and nice.
+			if i < 8 {
+				times.Total += v
+			}
+			if i == 3 || i == 4 { // idle and iowait
+				times.Idle += v
+			}
+		}
+		cpus = append(cpus, times)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(cpus) == 0 {
+		return nil, fmt.Errorf("no CPU lines in %s", r.path("stat"))
+	}
+	return cpus, nil
+}
+
+func (r procReader) readLoad() ([3]float64, error) {
+	var load [3]float64
+	data, err := os.ReadFile(r.path("loadavg"))
+	if err != nil {
+		return load, err
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) < 3 {
+		return load, fmt.Errorf("bad loadavg %q", data)
+	}
+	for i := range load {
+		if load[i], err = strconv.ParseFloat(fields[i], 64); err != nil {
+			return load, err
+		}
+	}
+	return load, nil
+}
+
+func (r procReader) readMemory() (memInfo, error) {
+	var mem memInfo
+	file, err := os.Open(r.path("meminfo"))
+	if err != nil {
+		return mem, err
+	}
+	defer file.Close()
+
+	values := make(map[string]uint64)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		v, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		if len(fields) > 2 && fields[2] == "kB" {
+			v *= 1024
+		}
+		values[strings.TrimSuffix(fields[0], ":")] = v
+	}
+	if err := scanner.Err(); err != nil {
+		return mem, err
+	}
+
+	mem.Total = values["MemTotal"]
+	mem.Available = values["MemAvailable"]
+	if _, ok := values["MemAvailable"]; !ok {
+		// Kernels before 3.14 don't report MemAvailable.
+		mem.Available = values["MemFree"] + values["Buffers"] + values["Cached"]
+	}
+	mem.SwapTotal = values["SwapTotal"]
+	mem.SwapFree = values["SwapFree"]
+	if mem.Total == 0 {
+		return mem, fmt.Errorf("no MemTotal in %s", r.path("meminfo"))
+	}
+	return mem, nil
+}
+
+func (r procReader) readMounts() ([]mountUsage, error) {
+	file, err := os.Open(r.path("mounts"))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var mounts []mountUsage
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 || ignoredFilesystems[fields[2]] || seen[fields[0]] {
+			continue
+		}
+		// Mount points escape spaces as \040.
+		path := strings.ReplaceAll(fields[1], `\040`, " ")
+		if r.Statfs == nil {
+			continue
+		}
+		total, free, err := r.Statfs(path)
+		if err != nil || total == 0 {
+			continue
+		}
+		seen[fields[0]] = true
+		mounts = append(mounts, mountUsage{Path: path, Total: total, Free: free})
+	}
+	return mounts, scanner.Err()
+}
+
+func (r procReader) readNetwork() (map[string]netCounters, error) {
+	file, err := os.Open(r.path("net", "dev"))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	counters := make(map[string]netCounters)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		name, rest, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue // header lines
+		}
+		name = strings.TrimSpace(name)
+		fields := strings.Fields(rest)
+		if name == "lo" || len(fields) < 9 {
+			continue
+		}
+		rx, err1 := strconv.ParseUint(fields[0], 10, 64)
+		tx, err2 := strconv.ParseUint(fields[8], 10, 64)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		counters[name] = netCounters{RxBytes: rx, TxBytes: tx}
+	}
+	return counters, scanner.Err()
+}
+
+// readProcesses reads the CPU time and memory of every process. Processes
+// that exit while being read are skipped.
+func (r procReader) readProcesses() (map[int]procTimes, error) {
+	entries, err := os.ReadDir(r.Root)
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize := uint64(os.Getpagesize())
+	procs := make(map[int]procTimes)
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		data, err := os.ReadFile(r.path(entry.Name(), "stat"))
+		if err != nil {
+			continue
+		}
+		// The command name is in parentheses and may itself contain spaces
+		// and parentheses, so split at the last ")".
+		stat := string(data)
+		open, end := strings.Index(stat, "("), strings.LastIndex(stat, ")")
+		if open < 0 || end < open {
+			continue
+		}
+		fields := strings.Fields(stat[end+1:])
+		// fields[0] is the state, field 3 of the full line.
+		if len(fields) < 22 {
+			continue
+		}
+		utime, _ := strconv.ParseUint(fields[11], 10, 64)
+		stime, _ := strconv.ParseUint(fields[12], 10, 64)
+		rss, _ := strconv.ParseUint(fields[21], 10, 64)
+		procs[pid] = procTimes{PID: pid, Name: stat[open+1 : end], Ticks: utime + stime, RSS: rss * pageSize}
+	}
+	return procs, nil
+}
+
+// sysStats are the rates computed from two consecutive samples.
+type sysStats struct {
+	CPU     []float64 // busy fraction, index 0 is the total
+	NetRx   float64   // bytes per second over all interfaces
+	NetTx   float64
+	TopCPU  []procUsage
+	Elapsed time.Duration
+}
+
+type procUsage struct {
+	procTimes
+	CPU float64 // fraction of one core
+}
+
+// clockTicks is USER_HZ, which is 100 on every mainstream Linux platform.
+const clockTicks = 100
+
+// computeStats compares two samples. It returns zero rates when prev is
+// empty.
+func computeStats(prev, cur sysSample, topN int) sysStats {
+	stats := sysStats{Elapsed: cur.At.Sub(prev.At)}
+
+	for i, c := range cur.CPUs {
+		usage := 0.0
+		if i < len(prev.CPUs) {
+			total := float64(c.Total - prev.CPUs[i].Total)
+			idle := float64(c.Idle - prev.CPUs[i].Idle)
+			if c.Total > prev.CPUs[i].Total && c.Idle >= prev.CPUs[i].Idle {
+				usage = (total - idle) / total
+			}
+		}
+		stats.CPU = append(stats.CPU, usage)
+	}
+
+	seconds := stats.Elapsed.Seconds()
+	if seconds <= 0 || prev.At.IsZero() {
+		return stats
+	}
+
+	for name, c := range cur.Net {
+		p, ok := prev.Net[name]
+		if !ok || c.RxBytes < p.RxBytes || c.TxBytes < p.TxBytes {
+			continue
+		}
+		stats.NetRx += float64(c.RxBytes-p.RxBytes) / seconds
+		stats.NetTx += float64(c.TxBytes-p.TxBytes) / seconds
+	}
+
+	for pid, c := range cur.Procs {
+		p, ok := prev.Procs[pid]
+		if !ok || c.Ticks < p.Ticks {
+			continue
+		}
+		usage := float64(c.Ticks-p.Ticks) / clockTicks / seconds
+		stats.TopCPU = append(stats.TopCPU, procUsage{procTimes: c, CPU: usage})
+	}
+	sort.Slice(stats.TopCPU, func(i, j int) bool {
+		a, b := stats.TopCPU[i], stats.TopCPU[j]
+		if a.CPU != b.CPU {
+			return a.CPU > b.CPU
+		}
+		if a.RSS != b.RSS {
+			return a.RSS > b.RSS
+		}
+		return a.PID < b.PID
+	})
+	if len(stats.TopCPU) > topN {
+		stats.TopCPU = stats.TopCPU[:topN]
+	}
+	return stats
+}
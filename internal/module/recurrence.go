@@ -0,0 +1,127 @@
+package module
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Ceinl/Go-dashboard/internal/storage"
+	"github.com/google/uuid"
+)
+
+// TasksChangedMsg is sent after tasks were created outside of a module, such
+// as by the recurring task scheduler, so that boards can reload.
+type TasksChangedMsg struct{}
+
+// GenerateRecurringTasks creates the due occurrences of every recurring task
+// and returns them. An occurrence is created when the period of the next one
+// starts, or as soon as every earlier occurrence is Done. Periods missed while
+// the dashboard wasn't running are skipped, only the latest one is created.
+// An occurrence isn't created over the WIP limit of To Do; it waits until the
+// column has room.
+func GenerateRecurringTasks(db *sql.DB, now time.Time) ([]storage.Task, error) {
+	templates, err := storage.GetRecurringTasks(db)
+	if err != nil {
+		return nil, err
+	}
+
+	today := now.Format(storage.DateLayout)
+	var created []storage.Task
+	for _, template := range templates {
+		// Rules are checked when they are set, so this only skips rules
+		// edited by hand in the database.
+		rule, err := storage.ParseRecurrence(template.Recurrence)
+		if err != nil {
+			log.Printf("Error reading schedule of %q: %v", template.Title, err)
+			continue
+		}
+		next := template.NextRun
+		if next == "" {
+			next = rule.Next(now).Format(storage.DateLayout)
+			if err := storage.SetNextRun(db, template.ID, next); err != nil {
+				return created, err
+			}
+		}
+
+		var due string
+		if next <= today {
+			due = latestOccurrence(rule, next, today)
+		} else {
+			open, err := storage.CountOpenOccurrences(db, template.ID)
+			if err != nil {
+				return created, err
+			}
+			if open > 0 {
+				continue
+			}
+			due = next
+		}
+
+		room, limited, err := storage.GetWIPRoom(db, template.ProjectID, ToDo)
+		if err != nil {
+			return created, err
+		}
+		if limited && room <= 0 {
+			continue
+		}
+
+		task, err := createOccurrence(db, template, due)
+		if err != nil {
+			return created, err
+		}
+		created = append(created, task)
+
+		day, _ := time.Parse(storage.DateLayout, due)
+		if err := storage.SetNextRun(db, template.ID, rule.Next(day).Format(storage.DateLayout)); err != nil {
+			return created, err
+		}
+	}
+	return created, nil
+}
+
+// latestOccurrence returns the last occurrence from first up to today.
+func latestOccurrence(rule storage.Recurrence, first, today string) string {
+	day, err := time.Parse(storage.DateLayout, first)
+	if err != nil {
+		return today
+	}
+	for next := rule.Next(day); next.Format(storage.DateLayout) <= today; next = rule.Next(next) {
+		day = next
+	}
+	return day.Format(storage.DateLayout)
+}
+
+// createOccurrence adds a To Do copy of template due on due, with its
+// checklist unchecked.
+func createOccurrence(db *sql.DB, template storage.Task, due string) (storage.Task, error) {
+	task := storage.Task{
+		ID:          uuid.New().String(),
+		ProjectID:   template.ProjectID,
+		Title:       template.Title,
+		Status:      ToDo,
+		Description: template.Description,
+		DueDate:     due,
+		RepoPath:    template.RepoPath,
+		AutoDone:    template.AutoDone,
+		Priority:    template.Priority,
+		Assignee:    template.Assignee,
+		Labels:      template.Labels,
+		TemplateID:  template.ID,
+	}
+	if err := storage.CreateTask(db, task); err != nil {
+		return storage.Task{}, fmt.Errorf("creating occurrence of %q: %w", template.Title, err)
+	}
+
+	items, err := storage.GetChecklist(db, template.ID)
+	if err != nil {
+		return task, err
+	}
+	for _, item := range items {
+		copied := storage.ChecklistItem{ID: uuid.New().String(), TaskID: task.ID, Text: item.Text}
+		if err := storage.CreateChecklistItem(db, copied); err != nil {
+			return task, err
+		}
+	}
+	return task, nil
+}
@@ -0,0 +1,112 @@
+package module
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Ceinl/Go-dashboard/internal/storage"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/uuid"
+)
+
+func TestGenerateRecurringTasks(t *testing.T) {
+	db, projectID := setupTestDB(t)
+	// A weekly report, due on Mondays. 2024-05-06 is a Monday.
+	template := storage.Task{
+		ID:         uuid.New().String(),
+		ProjectID:  projectID,
+		Title:      "Weekly report",
+		Status:     ToDo,
+		DueDate:    "2024-05-06",
+		Recurrence: "FREQ=WEEKLY;BYDAY=MO",
+		NextRun:    "2024-05-13",
+	}
+	if err := storage.CreateTask(db, template); err != nil {
+		t.Fatalf("failed to create task: %v", err)
+	}
+	item := storage.ChecklistItem{ID: uuid.New().String(), TaskID: template.ID, Text: "Send to team", Done: true}
+	if err := storage.CreateChecklistItem(db, item); err != nil {
+		t.Fatalf("failed to create checklist item: %v", err)
+	}
+
+	wednesday := time.Date(2024, 5, 8, 9, 0, 0, 0, time.Local)
+	if created, err := GenerateRecurringTasks(db, wednesday); err != nil || len(created) != 0 {
+		t.Fatalf("expected nothing while the report is open, got %+v (%v)", created, err)
+	}
+
+	template.Status = Done
+	if err := storage.UpdateTask(db, template); err != nil {
+		t.Fatalf("failed to update task: %v", err)
+	}
+	created, err := GenerateRecurringTasks(db, wednesday)
+	if err != nil || len(created) != 1 {
+		t.Fatalf("expected the next report once this one is done, got %+v (%v)", created, err)
+	}
+	next := created[0]
+	if next.DueDate != "2024-05-13" || next.Status != ToDo || next.TemplateID != template.ID || next.Recurrence != "" {
+		t.Errorf("unexpected occurrence %+v", next)
+	}
+	if items, _ := storage.GetChecklist(db, next.ID); len(items) != 1 || items[0].Done {
+		t.Errorf("expected the checklist copied unchecked, got %+v", items)
+	}
+	if created, _ := GenerateRecurringTasks(db, wednesday); len(created) != 0 {
+		t.Errorf("expected no second occurrence the same day, got %+v", created)
+	}
+
+	// Weeks later, only the latest period gets a task even though the last
+	// one is still open.
+	later := time.Date(2024, 6, 4, 9, 0, 0, 0, time.Local)
+	created, err = GenerateRecurringTasks(db, later)
+	if err != nil || len(created) != 1 || created[0].DueDate != "2024-06-03" {
+		t.Fatalf("expected one occurrence for 2024-06-03, got %+v (%v)", created, err)
+	}
+	if saved, _ := storage.GetTask(db, template.ID); saved.NextRun != "2024-06-10" {
+		t.Errorf("expected the next run on 2024-06-10, got %q", saved.NextRun)
+	}
+
+	// An occurrence waits while To Do is at its WIP limit.
+	if err := storage.SetWIPLimit(db, projectID, ToDo, 2); err != nil {
+		t.Fatalf("failed to set WIP limit: %v", err)
+	}
+	tuesday := time.Date(2024, 6, 11, 9, 0, 0, 0, time.Local)
+	if created, err := GenerateRecurringTasks(db, tuesday); err != nil || len(created) != 0 {
+		t.Fatalf("expected nothing over the WIP limit, got %+v (%v)", created, err)
+	}
+	if err := storage.SetWIPLimit(db, projectID, ToDo, 3); err != nil {
+		t.Fatalf("failed to set WIP limit: %v", err)
+	}
+	if created, err := GenerateRecurringTasks(db, tuesday); err != nil || len(created) != 1 || created[0].DueDate != "2024-06-10" {
+		t.Errorf("expected the occurrence once there is room, got %+v (%v)", created, err)
+	}
+}
+
+func TestKanbanRepeatsFinishedTask(t *testing.T) {
+	db, projectID := setupTestDB(t)
+	task := storage.Task{ID: uuid.New().String(), ProjectID: projectID, Title: "Update dependencies", Status: InProgress}
+	if err := storage.CreateTask(db, task); err != nil {
+		t.Fatalf("failed to create task: %v", err)
+	}
+
+	k := NewKanban(db, projectID)
+	k.Init()
+	k, _ = k.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	k = typeKeys(k, "l", "R", "d", "a", "i", "l", "y", "enter")
+	saved, _ := storage.GetTask(db, task.ID)
+	today := time.Now().Format(storage.DateLayout)
+	if saved.Recurrence != "FREQ=DAILY" || saved.DueDate != today {
+		t.Fatalf("expected a daily task due today, got %+v", saved)
+	}
+
+	k, cmd := k.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("L")})
+	if cmd == nil {
+		t.Fatal("expected finishing the task to create the next one")
+	}
+	k, _ = k.Update(cmd())
+	kanban := k.(*Kanban)
+	if todo := kanban.tasks[ToDo]; len(todo) != 1 || todo[0].TemplateID != task.ID {
+		t.Fatalf("expected the next occurrence in To Do, got %+v", todo)
+	}
+	if selected, _ := kanban.selectedTask(); selected.ID != task.ID {
+		t.Errorf("expected the cursor to stay on the finished task, got %+v", selected)
+	}
+}
@@ -2,12 +2,28 @@ package module
 
 var availableModules = []string{
 	"linksaver",
-	"placeholder",
+	"notes",
 	"kanban",
 	"twitter",
+	"timer",
+	"calendar",
+	"git",
+	"runner",
+	"sysmon",
+	"logtail",
+	"feeds",
+	"habits",
+	"snippets",
+	"journal",
+	"countdown",
 	// "profile",
 }
 
 func GetAvailableModules() []string {
 	return availableModules
 }
+
+// GetKanbanColumns returns the Kanban column names in board order.
+func GetKanbanColumns() []string {
+	return columns
+}
@@ -0,0 +1,492 @@
+package module
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Ceinl/Go-dashboard/internal/storage"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/google/uuid"
+)
+
+// runnerFields are the steps of the add/edit form.
+var runnerFields = []string{
+	"Name, e.g. tests",
+	"Command, run with sh -c",
+	"Working directory (empty for the current one)",
+	"Environment: KEY=VALUE pairs separated by semicolons",
+}
+
+// runnerTickMsg refreshes the elapsed time of running commands.
+type runnerTickMsg struct {
+	owner *Runner
+}
+
+// Runner runs a project's saved shell commands and shows their output. The
+// last storage.RunLogLimit runs of each command are kept.
+type Runner struct {
+	db        *sql.DB
+	projectID string
+	commands  []storage.Command
+	cursor    int
+
+	runs         []storage.CommandRun // saved runs of the selected command, newest first
+	historyIndex int                  // 0 is the latest run, see selectedRun
+	output       viewport.Model
+	followOutput bool // keep the viewport scrolled to the end
+	ticking      bool
+
+	form       bool
+	formStep   int
+	formValues []string
+	editingID  string // command being edited, empty when adding
+	input      textinput.Model
+	message    string
+
+	width  int
+	height int
+}
+
+func NewRunner(db *sql.DB, projectID string) Module {
+	ti := textinput.New()
+	ti.CharLimit = 1024
+	ti.Width = 60
+
+	return &Runner{
+		db:           db,
+		projectID:    projectID,
+		output:       viewport.New(0, 0),
+		followOutput: true,
+		input:        ti,
+	}
+}
+
+func (m *Runner) Init() tea.Cmd {
+	m.loadCommands()
+	m.selectCommand()
+
+	var cmds []tea.Cmd
+	for _, command := range m.commands {
+		if p := currentRun(command.ID); p != nil {
+			cmds = append(cmds, waitForRunner(p))
+		}
+	}
+	cmds = append(cmds, m.tick())
+	return tea.Batch(cmds...)
+}
+
+func (m *Runner) loadCommands() {
+	commands, err := storage.GetCommandsForProject(m.db, m.projectID)
+	if err != nil {
+		log.Printf("Error loading commands: %v", err)
+	}
+	m.commands = commands
+	if m.cursor >= len(m.commands) {
+		m.cursor = max(len(m.commands)-1, 0)
+	}
+}
+
+// selectCommand loads the run logs of the command under the cursor.
+func (m *Runner) selectCommand() {
+	m.runs = nil
+	m.historyIndex = 0
+	if command, ok := m.selectedCommand(); ok {
+		runs, err := storage.GetCommandRuns(m.db, command.ID)
+		if err != nil {
+			log.Printf("Error loading run logs: %v", err)
+		}
+		m.runs = runs
+	}
+	m.followOutput = true
+	m.refreshOutput()
+}
+
+func (m *Runner) selectedCommand() (storage.Command, bool) {
+	if m.cursor < len(m.commands) {
+		return m.commands[m.cursor], true
+	}
+	return storage.Command{}, false
+}
+
+// selectedRun returns the run shown in the output pane and whether it is
+// still going. Index 0 is the run started in this session, if any, or the
+// newest saved run; higher indexes go back through the saved logs.
+func (m *Runner) selectedRun() (storage.CommandRun, bool, bool) {
+	command, ok := m.selectedCommand()
+	if !ok {
+		return storage.CommandRun{}, false, false
+	}
+
+	index := m.historyIndex
+	if p := currentRun(command.ID); p != nil {
+		if index == 0 {
+			return p.log(), p.isRunning(), true
+		}
+		if p.isRunning() {
+			// The live run isn't saved yet, so it isn't in m.runs.
+			index--
+		}
+	}
+	if index < len(m.runs) {
+		return m.runs[index], false, true
+	}
+	return storage.CommandRun{}, false, false
+}
+
+// historyLength is the number of runs selectedRun can show.
+func (m *Runner) historyLength() int {
+	command, ok := m.selectedCommand()
+	if !ok {
+		return 0
+	}
+	if p := currentRun(command.ID); p != nil && p.isRunning() {
+		return len(m.runs) + 1
+	}
+	return len(m.runs)
+}
+
+func (m *Runner) refreshOutput() {
+	run, _, ok := m.selectedRun()
+	if !ok {
+		m.output.SetContent(gitDimStyle.Render("No runs yet. Press enter to run the command."))
+		return
+	}
+	m.output.SetContent(run.Output)
+	if m.followOutput {
+		m.output.GotoBottom()
+	}
+}
+
+// tick keeps elapsed times current while a command is running.
+func (m *Runner) tick() tea.Cmd {
+	if m.ticking || !m.anyRunning() {
+		return nil
+	}
+	m.ticking = true
+	owner := m
+	return tea.Tick(time.Second, func(time.Time) tea.Msg { return runnerTickMsg{owner: owner} })
+}
+
+func (m *Runner) anyRunning() bool {
+	for _, command := range m.commands {
+		if p := currentRun(command.ID); p != nil && p.isRunning() {
+			return true
+		}
+	}
+	return false
+}
+
+// CapturingInput reports whether the module needs every key press.
+func (m *Runner) CapturingInput() bool {
+	return m.form
+}
+
+func (m *Runner) Update(msg tea.Msg) (Module, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.output.Width = m.width - m.width/3 - 8
+		m.output.Height = max(m.height-16, 3)
+		m.refreshOutput()
+		return m, nil
+	case runnerOutputMsg:
+		var cmd tea.Cmd
+		if msg.done {
+			if command, ok := m.selectedCommand(); ok && command.ID == msg.commandID {
+				m.reloadRuns()
+			}
+		} else if p := currentRun(msg.commandID); p != nil {
+			cmd = waitForRunner(p)
+		}
+		m.refreshOutput()
+		return m, cmd
+	case runnerTickMsg:
+		if msg.owner != m {
+			return m, nil
+		}
+		m.ticking = false
+		return m, m.tick()
+	case tea.KeyMsg:
+		if m.form {
+			return m.updateForm(msg)
+		}
+		return m.updateBrowsing(msg)
+	}
+	return m, nil
+}
+
+// reloadRuns reloads the saved logs of the selected command, keeping the
+// history position.
+func (m *Runner) reloadRuns() {
+	command, ok := m.selectedCommand()
+	if !ok {
+		return
+	}
+	runs, err := storage.GetCommandRuns(m.db, command.ID)
+	if err != nil {
+		log.Printf("Error loading run logs: %v", err)
+		return
+	}
+	m.runs = runs
+}
+
+func (m *Runner) updateBrowsing(msg tea.KeyMsg) (Module, tea.Cmd) {
+	m.message = ""
+	switch msg.String() {
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+			m.selectCommand()
+		}
+	case "down", "j":
+		if m.cursor < len(m.commands)-1 {
+			m.cursor++
+			m.selectCommand()
+		}
+	case "enter", "r":
+		return m, m.run()
+	case "x":
+		if command, ok := m.selectedCommand(); ok {
+			if p := currentRun(command.ID); p != nil {
+				p.kill()
+			}
+		}
+	case "a":
+		return m, m.startForm(storage.Command{})
+	case "e":
+		if command, ok := m.selectedCommand(); ok {
+			return m, m.startForm(command)
+		}
+	case "d":
+		if command, ok := m.selectedCommand(); ok {
+			if p := currentRun(command.ID); p != nil && p.isRunning() {
+				m.message = "Stop the command with x before deleting it."
+				return m, nil
+			}
+			if err := storage.DeleteCommand(m.db, command.ID); err != nil {
+				log.Printf("Error deleting command: %v", err)
+			}
+			m.loadCommands()
+			m.selectCommand()
+		}
+	case "[":
+		if m.historyIndex < m.historyLength()-1 {
+			m.historyIndex++
+			m.followOutput = true
+			m.refreshOutput()
+		}
+	case "]":
+		if m.historyIndex > 0 {
+			m.historyIndex--
+			m.followOutput = true
+			m.refreshOutput()
+		}
+	case "pgup", "ctrl+u", "g":
+		if msg.String() == "g" {
+			m.output.GotoTop()
+		} else {
+			m.output.HalfViewUp()
+		}
+		m.followOutput = false
+	case "pgdown", "ctrl+d", "G":
+		if msg.String() == "G" {
+			m.output.GotoBottom()
+		} else {
+			m.output.HalfViewDown()
+		}
+		m.followOutput = m.output.AtBottom()
+	}
+	return m, nil
+}
+
+func (m *Runner) run() tea.Cmd {
+	command, ok := m.selectedCommand()
+	if !ok {
+		return nil
+	}
+	p, err := startRun(m.db, command)
+	if err != nil {
+		m.message = fmt.Sprintf("Can't run %s: %v", command.Name, err)
+		return nil
+	}
+	m.reloadRuns()
+	m.historyIndex = 0
+	m.followOutput = true
+	m.refreshOutput()
+	return tea.Batch(waitForRunner(p), m.tick())
+}
+
+func (m *Runner) startForm(command storage.Command) tea.Cmd {
+	m.form = true
+	m.formStep = 0
+	m.editingID = command.ID
+	m.formValues = []string{command.Name, command.Command, command.WorkDir, strings.Join(command.EnvList(), "; ")}
+	return m.showFormStep()
+}
+
+func (m *Runner) showFormStep() tea.Cmd {
+	m.input.Placeholder = runnerFields[m.formStep]
+	m.input.SetValue(m.formValues[m.formStep])
+	m.input.CursorEnd()
+	return m.input.Focus()
+}
+
+func (m *Runner) updateForm(msg tea.KeyMsg) (Module, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.form = false
+		m.input.Blur()
+		return m, nil
+	case tea.KeyEnter:
+		m.formValues[m.formStep] = strings.TrimSpace(m.input.Value())
+		if m.formStep == 0 && m.formValues[0] == "" || m.formStep == 1 && m.formValues[1] == "" {
+			return m, nil // name and command are required
+		}
+		if m.formStep < len(runnerFields)-1 {
+			m.formStep++
+			return m, m.showFormStep()
+		}
+		m.form = false
+		m.input.Blur()
+		m.saveForm()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m *Runner) saveForm() {
+	command := storage.Command{
+		ID:        m.editingID,
+		ProjectID: m.projectID,
+		Name:      m.formValues[0],
+		Command:   m.formValues[1],
+		WorkDir:   m.formValues[2],
+		Env:       parseEnv(m.formValues[3]),
+	}
+
+	var err error
+	if command.ID == "" {
+		command.ID = uuid.New().String()
+		err = storage.CreateCommand(m.db, command)
+	} else {
+		err = storage.UpdateCommand(m.db, command)
+	}
+	if err != nil {
+		log.Printf("Error saving command: %v", err)
+		return
+	}
+
+	m.loadCommands()
+	for i, c := range m.commands {
+		if c.ID == command.ID {
+			m.cursor = i
+		}
+	}
+	m.selectCommand()
+}
+
+// parseEnv turns the semicolon-separated KEY=VALUE pairs of the form into
+// Command.Env. Values may contain spaces, but not semicolons.
+func parseEnv(value string) string {
+	var env []string
+	for _, pair := range strings.Split(value, ";") {
+		if pair = strings.TrimSpace(pair); pair != "" {
+			env = append(env, pair)
+		}
+	}
+	return strings.Join(env, "\n")
+}
+
+// runSummary describes a run's outcome, e.g. "exit 0 after 3.2s".
+func runSummary(run storage.CommandRun, running bool) string {
+	duration := run.Duration().Round(100 * time.Millisecond)
+	if running {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Render(fmt.Sprintf("running %s", duration.Round(time.Second)))
+	}
+	if run.ExitCode == 0 {
+		return gitAddedStyle.Render(fmt.Sprintf("✓ exit 0 after %s", duration))
+	}
+	return gitErrorStyle.Render(fmt.Sprintf("✗ exit %d after %s", run.ExitCode, duration))
+}
+
+func (m *Runner) View() string {
+	if m.width == 0 {
+		return "loading..."
+	}
+	if m.form {
+		step := gitDimStyle.Render(fmt.Sprintf("Step %d of %d", m.formStep+1, len(runnerFields)))
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center,
+			lipgloss.JoinVertical(lipgloss.Left, runnerFields[m.formStep], m.input.View(), step))
+	}
+
+	listWidth := m.width / 3
+	paneHeight := m.height - 10
+
+	var list []string
+	for i, command := range m.commands {
+		line := truncate(command.Name, listWidth-4)
+		if p := currentRun(command.ID); p != nil {
+			line += "\n  " + runSummary(p.log(), p.isRunning())
+		} else if i == m.cursor && len(m.runs) > 0 {
+			line += "\n  " + runSummary(m.runs[0], false)
+		} else {
+			line += "\n  " + gitDimStyle.Render(truncate(command.Command, listWidth-6))
+		}
+		style := lipgloss.NewStyle().Width(listWidth - 4)
+		if i == m.cursor {
+			style = style.Background(lipgloss.Color("57"))
+		}
+		list = append(list, style.Render(line))
+	}
+	if len(m.commands) == 0 {
+		list = append(list, gitDimStyle.Render("No commands. Press a to add one."))
+	}
+
+	listPane := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		Padding(0, 1).
+		Width(listWidth - 2).
+		Height(paneHeight).
+		Render(strings.Join(list, "\n"))
+
+	var header []string
+	if command, ok := m.selectedCommand(); ok {
+		dir := command.WorkDir
+		if dir == "" {
+			dir = "."
+		}
+		header = append(header, lipgloss.NewStyle().Bold(true).Render("$ "+command.Command)+gitDimStyle.Render("  in "+dir))
+		if run, running, ok := m.selectedRun(); ok {
+			position := ""
+			if m.historyLength() > 1 {
+				position = gitDimStyle.Render(fmt.Sprintf("  run %d of %d", m.historyIndex+1, m.historyLength()))
+			}
+			header = append(header, run.StartedAt.Local().Format("Jan 2 15:04:05")+"  "+runSummary(run, running)+position)
+		}
+	}
+	outputPane := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("63")).
+		Padding(0, 1).
+		Width(m.width - listWidth - 4).
+		Height(paneHeight).
+		Render(lipgloss.JoinVertical(lipgloss.Left, strings.Join(header, "\n"), "", m.output.View()))
+
+	footer := "(enter) run, (x) stop, (a)dd, (e)dit, (d)elete, ([/]) older/newer run, (ctrl+u/d) scroll"
+	if m.message != "" {
+		footer = m.message
+	}
+	return lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.JoinHorizontal(lipgloss.Top, listPane, outputPane),
+		gitDimStyle.Render(footer))
+}
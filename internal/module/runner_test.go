@@ -0,0 +1,139 @@
+package module
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ceinl/Go-dashboard/internal/storage"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/uuid"
+)
+
+// runRunnerCmds runs cmd and the commands it leads to, as the Bubble Tea
+// runtime would, feeding the runner's messages back to m until done holds.
+func runRunnerCmds(t *testing.T, m Module, cmd tea.Cmd, done func() bool) Module {
+	t.Helper()
+	msgs := make(chan tea.Msg, 16)
+	start := func(cmd tea.Cmd) {
+		if cmd != nil {
+			go func() { msgs <- cmd() }()
+		}
+	}
+	start(cmd)
+	timeout := time.After(5 * time.Second)
+	for !done() {
+		select {
+		case msg := <-msgs:
+			switch msg := msg.(type) {
+			case tea.BatchMsg:
+				for _, cmd := range msg {
+					start(cmd)
+				}
+			case runnerOutputMsg:
+				var cmd tea.Cmd
+				m, cmd = m.Update(msg)
+				start(cmd)
+			}
+		case <-timeout:
+			t.Fatal("the runner didn't get to the expected state")
+		}
+	}
+	return m
+}
+
+func TestRunnerRunsCommandsAndKeepsLogs(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+	db, projectID := setupTestDB(t)
+	dir := t.TempDir()
+	command := storage.Command{
+		ID:        uuid.New().String(),
+		ProjectID: projectID,
+		Name:      "check",
+		Command:   `echo "in $(basename "$PWD") as $GREETING"; echo oops >&2; exit 3`,
+		WorkDir:   dir,
+		Env:       "GREETING=hello",
+	}
+	if err := storage.CreateCommand(db, command); err != nil {
+		t.Fatalf("failed to create command: %v", err)
+	}
+
+	m := NewRunner(db, projectID)
+	m.Init()
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	runner := m.(*Runner)
+
+	// The command may well finish before run returns; the module still
+	// hears of it and reloads its logs.
+	m = runRunnerCmds(t, m, runner.run(), func() bool { return len(runner.runs) == 1 })
+	m = runRunnerCmds(t, m, runner.run(), func() bool { return len(runner.runs) == 2 })
+
+	run, running, ok := runner.selectedRun()
+	if !ok || running {
+		t.Fatalf("expected a finished run, got ok=%v running=%v", ok, running)
+	}
+	wantOutput := "in " + dirBase(dir) + " as hello\noops"
+	if run.ExitCode != 3 || run.Output != wantOutput {
+		t.Errorf("expected exit 3 with output %q, got exit %d with %q", wantOutput, run.ExitCode, run.Output)
+	}
+	if runner.historyLength() != 2 {
+		t.Errorf("expected 2 runs in the history, got %d", runner.historyLength())
+	}
+	m = typeKeys(m, "[")
+	if previous, _, _ := runner.selectedRun(); previous.ID != runner.runs[1].ID {
+		t.Errorf("expected [ to show the first run, got %+v", previous)
+	}
+}
+
+func TestRunnerStopsCompoundCommands(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+	db, projectID := setupTestDB(t)
+	command := storage.Command{ID: uuid.New().String(), ProjectID: projectID, Name: "slow", Command: "sleep 5; echo done"}
+	if err := storage.CreateCommand(db, command); err != nil {
+		t.Fatalf("failed to create command: %v", err)
+	}
+
+	p, err := startRun(db, command)
+	if err != nil {
+		t.Fatalf("failed to start command: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond) // let sh start sleep
+	p.kill()
+	select {
+	case <-p.done:
+	case <-time.After(time.Second):
+		t.Fatal("the command kept running after being stopped")
+	}
+	if run := p.log(); run.ExitCode == 0 || strings.Contains(run.Output, "done") {
+		t.Errorf("expected the command to be killed, got exit %d with %q", run.ExitCode, run.Output)
+	}
+}
+
+func TestRunnerFormKeepsEnvironmentValues(t *testing.T) {
+	db, projectID := setupTestDB(t)
+	m := NewRunner(db, projectID)
+	m.Init()
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	runner := m.(*Runner)
+
+	m = typeKeys(m, "a", "tests", "enter", "go test ./...", "enter", "enter", "A=1; B=two words", "enter")
+	command, ok := runner.selectedCommand()
+	if !ok || command.Env != "A=1\nB=two words" {
+		t.Fatalf("expected both variables to be saved, got %q", command.Env)
+	}
+
+	// Saving the edit form unchanged keeps them as they were.
+	m = typeKeys(m, "e", "enter", "enter", "enter", "enter")
+	if command, _ := runner.selectedCommand(); command.Env != "A=1\nB=two words" {
+		t.Errorf("expected editing to keep the variables, got %q", command.Env)
+	}
+}
+
+func dirBase(path string) string {
+	return path[strings.LastIndex(path, "/")+1:]
+}
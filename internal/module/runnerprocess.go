@@ -0,0 +1,219 @@
+package module
+
+import (
+	"bufio"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Ceinl/Go-dashboard/internal/storage"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/uuid"
+)
+
+// runnerMaxLines caps the output kept in memory and in a run log.
+const runnerMaxLines = 5000
+
+// runnerWaitDelay is how long a run waits, once its shell has exited, for the
+// processes it left in the background to close the output.
+const runnerWaitDelay = 2 * time.Second
+
+// runnerProcess is a running or finished run of a saved command. Processes
+// live outside the Runner module so that they keep running, and keep their
+// output, when the module is recreated on a project switch.
+type runnerProcess struct {
+	mu        sync.Mutex
+	commandID string
+	output    []string
+	startedAt time.Time
+	endedAt   time.Time
+	exitCode  int
+	running   bool
+	waiting   bool // a waitForRunner command is pending
+	cmd       *exec.Cmd
+
+	changed chan struct{} // signalled, without blocking, when anything changes
+	done    chan struct{} // closed when the run has finished and been saved
+}
+
+var (
+	runnerMu        sync.Mutex
+	runnerProcesses = make(map[string]*runnerProcess) // latest run, by command ID
+)
+
+// runnerOutputMsg tells the Runner module that a process has new output or,
+// when done is set, has finished and its log has been saved.
+type runnerOutputMsg struct {
+	commandID string
+	done      bool
+}
+
+// currentRun returns the latest run of a command started in this session.
+func currentRun(commandID string) *runnerProcess {
+	runnerMu.Lock()
+	defer runnerMu.Unlock()
+	return runnerProcesses[commandID]
+}
+
+// startRun runs command in the background with sh, in a process group of its
+// own so that stopping it stops everything it started. Its combined output is
+// collected line by line; when it exits, the log is saved to db.
+func startRun(db *sql.DB, command storage.Command) (*runnerProcess, error) {
+	if p := currentRun(command.ID); p != nil && p.isRunning() {
+		return nil, errors.New("already running")
+	}
+
+	cmd := exec.Command("sh", "-c", command.Command)
+	cmd.Dir = expandHome(command.WorkDir)
+	cmd.Env = append(os.Environ(), command.EnvList()...)
+	pr, pw := io.Pipe()
+	cmd.Stdout = pw
+	cmd.Stderr = pw
+	cmd.WaitDelay = runnerWaitDelay
+	startProcessGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	p := &runnerProcess{
+		commandID: command.ID,
+		startedAt: time.Now(),
+		running:   true,
+		cmd:       cmd,
+		changed:   make(chan struct{}, 1),
+		done:      make(chan struct{}),
+	}
+	runnerMu.Lock()
+	runnerProcesses[command.ID] = p
+	runnerMu.Unlock()
+
+	read := make(chan struct{})
+	go func() {
+		defer close(read)
+		scanner := bufio.NewScanner(pr)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			p.appendLine(scanner.Text())
+		}
+		// Keep draining so that the process never blocks on a full pipe.
+		io.Copy(io.Discard, pr)
+	}()
+
+	go func() {
+		err := cmd.Wait()
+		pw.Close()
+		<-read
+		p.finish(err)
+
+		if err := storage.CreateCommandRun(db, p.log()); err != nil {
+			log.Printf("Error saving run log: %v", err)
+		}
+		close(p.done)
+	}()
+
+	return p, nil
+}
+
+func (p *runnerProcess) appendLine(line string) {
+	p.mu.Lock()
+	p.output = append(p.output, line)
+	if len(p.output) > runnerMaxLines {
+		p.output = p.output[len(p.output)-runnerMaxLines:]
+	}
+	p.mu.Unlock()
+	p.signal()
+}
+
+func (p *runnerProcess) finish(err error) {
+	p.mu.Lock()
+	p.running = false
+	p.endedAt = time.Now()
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		p.exitCode = 0
+	case errors.Is(err, exec.ErrWaitDelay):
+		p.exitCode = p.cmd.ProcessState.ExitCode()
+		p.output = append(p.output, "note: processes left in the background kept running, their output isn't shown")
+	case errors.As(err, &exitErr):
+		p.exitCode = exitErr.ExitCode()
+	default:
+		p.exitCode = -1
+		p.output = append(p.output, fmt.Sprintf("error: %v", err))
+	}
+	p.mu.Unlock()
+}
+
+func (p *runnerProcess) signal() {
+	select {
+	case p.changed <- struct{}{}:
+	default:
+	}
+}
+
+// kill stops a running process and the processes it started.
+func (p *runnerProcess) kill() {
+	if p.isRunning() && p.cmd.Process != nil {
+		if err := killProcessGroup(p.cmd); err != nil {
+			log.Printf("Error killing process: %v", err)
+		}
+	}
+}
+
+func (p *runnerProcess) isRunning() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.running
+}
+
+// log returns the run as it is saved, or as it stands so far.
+func (p *runnerProcess) log() storage.CommandRun {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	run := storage.CommandRun{
+		ID:        uuid.New().String(),
+		CommandID: p.commandID,
+		StartedAt: p.startedAt,
+		EndedAt:   p.endedAt,
+		ExitCode:  p.exitCode,
+		Output:    strings.Join(p.output, "\n"),
+	}
+	if p.running {
+		run.EndedAt = time.Now()
+	}
+	return run
+}
+
+// waitForRunner returns a command that delivers a runnerOutputMsg the next
+// time p has new output or has finished and been saved, right away if it
+// already has. It returns nil if such a command is already pending.
+func waitForRunner(p *runnerProcess) tea.Cmd {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.waiting {
+		return nil
+	}
+	p.waiting = true
+	return func() tea.Msg {
+		select {
+		case <-p.changed:
+		case <-p.done:
+		}
+		p.mu.Lock()
+		p.waiting = false
+		p.mu.Unlock()
+		select {
+		case <-p.done:
+			return runnerOutputMsg{commandID: p.commandID, done: true}
+		default:
+			return runnerOutputMsg{commandID: p.commandID}
+		}
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !unix
+
+package module
+
+import "os/exec"
+
+// startProcessGroup does nothing where process groups aren't supported.
+func startProcessGroup(cmd *exec.Cmd) {}
+
+// killProcessGroup only kills cmd itself where process groups aren't
+// supported, leaving the processes it started running.
+func killProcessGroup(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
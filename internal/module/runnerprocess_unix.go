@@ -0,0 +1,19 @@
+//go:build unix
+
+package module
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// startProcessGroup makes cmd lead a process group of its own, so that
+// killProcessGroup also stops the processes it starts.
+func startProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// killProcessGroup kills the process group led by cmd.
+func killProcessGroup(cmd *exec.Cmd) error {
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
@@ -0,0 +1,646 @@
+package module
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/Ceinl/Go-dashboard/internal/storage"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/google/uuid"
+)
+
+// snippetFields are the steps of the add/edit form. The body is written
+// afterwards in the editor.
+var snippetFields = []string{
+	"Name, e.g. deploy.sh or slow queries",
+	"Language (empty to guess from the name)",
+	"Tags, separated by commas",
+}
+
+// snippetExtensions name the temporary files opened in $EDITOR, so that the
+// editor picks the right syntax.
+var snippetExtensions = map[string]string{
+	"go": ".go", "sh": ".sh", "sql": ".sql", "python": ".py",
+	"javascript": ".js", "json": ".json", "yaml": ".yaml",
+}
+
+var snippetGlobalStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+
+// copyToClipboard puts text on the system clipboard. Tests replace it.
+var copyToClipboard = func(text string) error {
+	cmd := exec.Command("pbcopy")
+	cmd.Stdin = strings.NewReader(text)
+	return cmd.Run()
+}
+
+type snippetsMode int
+
+const (
+	snippetsBrowsing snippetsMode = iota
+	snippetsForm                  // editing name, language and tags
+	snippetsEditing               // editing the body in the textarea
+)
+
+// snippetEditedMsg is sent when $EDITOR exits. With keep set the result is
+// saved as the snippet's body, otherwise it is only copied.
+type snippetEditedMsg struct {
+	snippetID string
+	content   string
+	keep      bool
+	err       error
+}
+
+// snippetMatch is a snippet that matches the search, with the positions of
+// the matched runes in its name.
+type snippetMatch struct {
+	storage.Snippet
+	score     int
+	positions []int
+}
+
+// Snippets keeps named, tagged pieces of text such as shell commands and SQL
+// queries, per project or shared by every project.
+type Snippets struct {
+	db        *sql.DB
+	projectID string
+	snippets  []storage.Snippet
+	cursor    int // index into visibleSnippets
+	filter    itemFilter
+	mode      snippetsMode
+
+	formStep   int
+	formValues []string
+	editingID  string // snippet being edited, empty when adding
+	input      textinput.Model
+	editor     textarea.Model
+	preview    viewport.Model
+	message    string
+
+	width  int
+	height int
+}
+
+func NewSnippets(db *sql.DB, projectID string) Module {
+	ti := textinput.New()
+	ti.CharLimit = 200
+	ti.Width = 60
+
+	editor := textarea.New()
+	editor.Placeholder = "Paste or write the snippet..."
+	editor.MaxHeight = 0
+	editor.ShowLineNumbers = true
+
+	filter := newItemFilter()
+	filter.input.Placeholder = "fuzzy search"
+
+	return &Snippets{
+		db:        db,
+		projectID: projectID,
+		filter:    filter,
+		input:     ti,
+		editor:    editor,
+		preview:   viewport.New(0, 0),
+	}
+}
+
+func (m *Snippets) Init() tea.Cmd {
+	m.loadSnippets()
+	return nil
+}
+
+func (m *Snippets) loadSnippets() {
+	snippets, err := storage.GetSnippets(m.db, m.projectID)
+	if err != nil {
+		log.Printf("Error loading snippets: %v", err)
+		return
+	}
+	m.snippets = snippets
+	m.clampCursor()
+}
+
+// CapturingInput reports whether the module needs every key press.
+func (m *Snippets) CapturingInput() bool {
+	return m.mode != snippetsBrowsing || m.filter.Typing()
+}
+
+func (m *Snippets) Filters() {}
+
+// visibleSnippets returns the snippets matching the search, best match
+// first, or every snippet when there is no search.
+func (m *Snippets) visibleSnippets() []snippetMatch {
+	query := m.filter.input.Value()
+	var matches []snippetMatch
+	for _, snippet := range m.snippets {
+		if score, positions, ok := matchSnippet(query, snippet); ok {
+			matches = append(matches, snippetMatch{Snippet: snippet, score: score, positions: positions})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+	return matches
+}
+
+func (m *Snippets) selectedSnippet() (storage.Snippet, bool) {
+	visible := m.visibleSnippets()
+	if m.cursor < 0 || m.cursor >= len(visible) {
+		return storage.Snippet{}, false
+	}
+	return visible[m.cursor].Snippet, true
+}
+
+func (m *Snippets) clampCursor() {
+	if n := len(m.visibleSnippets()); m.cursor >= n {
+		m.cursor = max(n-1, 0)
+	}
+	m.refreshPreview()
+}
+
+func (m *Snippets) focusSnippet(id string) {
+	for i, match := range m.visibleSnippets() {
+		if match.ID == id {
+			m.cursor = i
+		}
+	}
+	m.refreshPreview()
+}
+
+func (m *Snippets) refreshPreview() {
+	snippet, ok := m.selectedSnippet()
+	if !ok {
+		m.preview.SetContent("")
+		return
+	}
+	m.preview.SetContent(highlightCode(snippet.Body, snippetLanguage(snippet)))
+	m.preview.GotoTop()
+}
+
+func (m *Snippets) Update(msg tea.Msg) (Module, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.editor.SetWidth(m.width*2/3 - 4)
+		m.editor.SetHeight(max(m.height-12, 3))
+		m.preview.Width = m.width*2/3 - 8
+		m.preview.Height = max(m.height-15, 3)
+		m.refreshPreview()
+		return m, nil
+	case snippetEditedMsg:
+		m.finishExternalEdit(msg)
+		return m, nil
+	}
+
+	switch m.mode {
+	case snippetsForm:
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			return m.updateForm(keyMsg)
+		}
+		return m, nil
+	case snippetsEditing:
+		return m.updateEditing(msg)
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	if m.filter.Typing() {
+		cmd, changed := m.filter.Update(keyMsg)
+		if changed {
+			m.cursor = 0
+			m.refreshPreview()
+		}
+		return m, cmd
+	}
+	return m.updateBrowsing(keyMsg)
+}
+
+func (m *Snippets) updateBrowsing(msg tea.KeyMsg) (Module, tea.Cmd) {
+	m.message = ""
+	switch msg.String() {
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+			m.refreshPreview()
+		}
+	case "down", "j":
+		if m.cursor < len(m.visibleSnippets())-1 {
+			m.cursor++
+			m.refreshPreview()
+		}
+	case "ctrl+d", "pgdown":
+		m.preview.HalfPageDown()
+	case "ctrl+u", "pgup":
+		m.preview.HalfPageUp()
+	case "enter", "c", "y":
+		if snippet, ok := m.selectedSnippet(); ok {
+			m.copy(snippet.Name, snippet.Body)
+		}
+	case "i":
+		if snippet, ok := m.selectedSnippet(); ok {
+			return m, m.openExternalEditor(snippet, false)
+		}
+	case "E":
+		if snippet, ok := m.selectedSnippet(); ok {
+			return m, m.openExternalEditor(snippet, true)
+		}
+	case "b":
+		if snippet, ok := m.selectedSnippet(); ok {
+			return m, m.startEditing(snippet)
+		}
+	case "a":
+		return m, m.startForm(storage.Snippet{})
+	case "e":
+		if snippet, ok := m.selectedSnippet(); ok {
+			return m, m.startForm(snippet)
+		}
+	case "g":
+		m.toggleGlobal()
+	case "d":
+		if snippet, ok := m.selectedSnippet(); ok {
+			if err := storage.DeleteSnippet(m.db, snippet.ID); err != nil {
+				log.Printf("Error deleting snippet: %v", err)
+			}
+			m.loadSnippets()
+		}
+	case "/":
+		m.cursor = 0
+		return m, m.filter.Start()
+	case "esc":
+		if m.filter.Active() {
+			m.filter.Reset()
+			m.cursor = 0
+			m.refreshPreview()
+		}
+	}
+	return m, nil
+}
+
+func (m *Snippets) copy(name, text string) {
+	if err := copyToClipboard(text); err != nil {
+		log.Printf("Error copying snippet: %v", err)
+		m.message = fmt.Sprintf("Can't copy %s: %v", name, err)
+		return
+	}
+	m.message = fmt.Sprintf("Copied %s to the clipboard.", name)
+}
+
+// toggleGlobal moves the selected snippet between the project and the
+// global scope.
+func (m *Snippets) toggleGlobal() {
+	snippet, ok := m.selectedSnippet()
+	if !ok {
+		return
+	}
+	if snippet.Global() {
+		if m.projectID == "" {
+			return
+		}
+		snippet.ProjectID = m.projectID
+	} else {
+		snippet.ProjectID = ""
+	}
+	m.saveSnippet(snippet)
+}
+
+func (m *Snippets) saveSnippet(snippet storage.Snippet) {
+	if err := storage.UpdateSnippet(m.db, snippet); err != nil {
+		log.Printf("Error saving snippet: %v", err)
+		return
+	}
+	m.loadSnippets()
+	m.focusSnippet(snippet.ID)
+}
+
+func (m *Snippets) startForm(snippet storage.Snippet) tea.Cmd {
+	m.mode = snippetsForm
+	m.formStep = 0
+	m.editingID = snippet.ID
+	m.formValues = []string{snippet.Name, snippet.Language, strings.Join(snippet.Tags, ", ")}
+	return m.showFormStep()
+}
+
+func (m *Snippets) showFormStep() tea.Cmd {
+	m.input.Placeholder = snippetFields[m.formStep]
+	m.input.SetValue(m.formValues[m.formStep])
+	m.input.CursorEnd()
+	return m.input.Focus()
+}
+
+func (m *Snippets) updateForm(msg tea.KeyMsg) (Module, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.mode = snippetsBrowsing
+		m.input.Blur()
+		return m, nil
+	case tea.KeyEnter:
+		m.formValues[m.formStep] = strings.TrimSpace(m.input.Value())
+		if m.formStep == 0 && m.formValues[0] == "" {
+			return m, nil // the name is required
+		}
+		if m.formStep < len(snippetFields)-1 {
+			m.formStep++
+			return m, m.showFormStep()
+		}
+		m.mode = snippetsBrowsing
+		m.input.Blur()
+		return m, m.saveForm()
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// saveForm saves the form. A new snippet goes straight to the editor so its
+// body can be written.
+func (m *Snippets) saveForm() tea.Cmd {
+	tags := storage.ParseTags(m.formValues[2])
+	if m.editingID != "" {
+		for _, snippet := range m.snippets {
+			if snippet.ID == m.editingID {
+				snippet.Name = m.formValues[0]
+				snippet.Language = m.formValues[1]
+				snippet.Tags = tags
+				m.saveSnippet(snippet)
+			}
+		}
+		return nil
+	}
+
+	snippet := storage.Snippet{
+		ID:        uuid.New().String(),
+		ProjectID: m.projectID,
+		Name:      m.formValues[0],
+		Language:  m.formValues[1],
+		Tags:      tags,
+	}
+	if err := storage.CreateSnippet(m.db, snippet); err != nil {
+		log.Printf("Error creating snippet: %v", err)
+		return nil
+	}
+	m.filter.Reset()
+	m.loadSnippets()
+	m.focusSnippet(snippet.ID)
+	return m.startEditing(snippet)
+}
+
+func (m *Snippets) startEditing(snippet storage.Snippet) tea.Cmd {
+	m.mode = snippetsEditing
+	m.editingID = snippet.ID
+	m.editor.SetValue(snippet.Body)
+	return m.editor.Focus()
+}
+
+func (m *Snippets) updateEditing(msg tea.Msg) (Module, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "ctrl+s":
+			m.setBody(m.editingID, m.editor.Value())
+			m.editor.Blur()
+			m.mode = snippetsBrowsing
+			return m, nil
+		case "esc":
+			m.editor.Blur()
+			m.mode = snippetsBrowsing
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.editor, cmd = m.editor.Update(msg)
+	return m, cmd
+}
+
+func (m *Snippets) setBody(id, body string) {
+	for _, snippet := range m.snippets {
+		if snippet.ID == id {
+			snippet.Body = body
+			m.saveSnippet(snippet)
+			return
+		}
+	}
+}
+
+// openExternalEditor suspends the dashboard while $EDITOR (or vi) edits the
+// snippet. With keep unset the edit is a scratch copy, e.g. to fill in a
+// query's parameters, which is copied to the clipboard when the editor exits.
+func (m *Snippets) openExternalEditor(snippet storage.Snippet, keep bool) tea.Cmd {
+	ext, ok := snippetExtensions[snippetLanguage(snippet)]
+	if !ok {
+		ext = ".txt"
+	}
+	return editExternally(snippet.Body, "dashboard-snippet-*"+ext, func(content string, err error) tea.Msg {
+		return snippetEditedMsg{snippetID: snippet.ID, content: content, keep: keep, err: err}
+	})
+}
+
+func (m *Snippets) finishExternalEdit(msg snippetEditedMsg) {
+	if msg.err != nil {
+		log.Printf("Error editing snippet: %v", msg.err)
+		return
+	}
+	if msg.keep {
+		m.setBody(msg.snippetID, msg.content)
+		return
+	}
+	for _, snippet := range m.snippets {
+		if snippet.ID == msg.snippetID {
+			m.copy(snippet.Name, strings.TrimSuffix(msg.content, "\n"))
+		}
+	}
+}
+
+// snippetLanguage returns the snippet's language, guessing it from the
+// extension of its name when none is set.
+func snippetLanguage(snippet storage.Snippet) string {
+	if snippet.Language != "" {
+		return canonicalLanguage(snippet.Language)
+	}
+	if ext := filepath.Ext(snippet.Name); ext != "" && !strings.Contains(ext, " ") {
+		return canonicalLanguage(ext)
+	}
+	return ""
+}
+
+// matchSnippet scores a snippet against a search of one or more terms. Every
+// term must fuzzily match the name, a tag or the language, or appear
+// literally in the body. Matches in the name score highest; positions are
+// the runes of the name that matched.
+func matchSnippet(query string, snippet storage.Snippet) (int, []int, bool) {
+	total := 0
+	var positions []int
+	for _, term := range strings.Fields(query) {
+		score, matched, ok := fuzzyMatch(term, snippet.Name)
+		if ok {
+			total += 2 * score
+			positions = append(positions, matched...)
+			continue
+		}
+
+		best := 0
+		for _, field := range append([]string{snippet.Language}, snippet.Tags...) {
+			if score, _, ok := fuzzyMatch(term, field); ok && score > best {
+				best = score
+			}
+		}
+		if best == 0 && strings.Contains(strings.ToLower(snippet.Body), strings.ToLower(term)) {
+			best = 1
+		}
+		if best == 0 {
+			return 0, nil, false
+		}
+		total += best
+	}
+	return total, positions, true
+}
+
+// fuzzyMatch reports whether the runes of pattern appear in text in order,
+// ignoring case. The score rewards consecutive runes and matches at the
+// start of words, so "dpl" ranks "deploy" above "docker pull".
+func fuzzyMatch(pattern, text string) (int, []int, bool) {
+	p := []rune(strings.ToLower(pattern))
+	t := []rune(text)
+	if len(p) == 0 {
+		return 0, nil, true
+	}
+
+	score := 0
+	positions := make([]int, 0, len(p))
+	j := 0
+	for i := 0; i < len(t) && j < len(p); i++ {
+		if unicode.ToLower(t[i]) != p[j] {
+			continue
+		}
+		score++
+		if len(positions) > 0 && positions[len(positions)-1] == i-1 {
+			score += 3
+		}
+		if i == 0 || !unicode.IsLetter(t[i-1]) && !unicode.IsDigit(t[i-1]) {
+			score += 2
+		}
+		positions = append(positions, i)
+		j++
+	}
+	if j < len(p) {
+		return 0, nil, false
+	}
+	return score, positions, true
+}
+
+// highlightPositions renders text with base, underlining the runes at the
+// given positions.
+func highlightPositions(text string, positions []int, base lipgloss.Style) string {
+	if len(positions) == 0 {
+		return base.Render(text)
+	}
+	marked := make(map[int]bool, len(positions))
+	for _, i := range positions {
+		marked[i] = true
+	}
+	highlight := matchStyle.Inherit(base)
+	var s strings.Builder
+	for i, r := range []rune(text) {
+		if marked[i] {
+			s.WriteString(highlight.Render(string(r)))
+		} else {
+			s.WriteString(base.Render(string(r)))
+		}
+	}
+	return s.String()
+}
+
+func (m *Snippets) View() string {
+	if m.width == 0 {
+		return "loading..."
+	}
+	switch m.mode {
+	case snippetsForm:
+		step := gitDimStyle.Render(fmt.Sprintf("Step %d of %d", m.formStep+1, len(snippetFields)))
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center,
+			lipgloss.JoinVertical(lipgloss.Left, snippetFields[m.formStep], m.input.View(), step))
+	case snippetsEditing:
+		return lipgloss.JoinVertical(lipgloss.Left,
+			m.editor.View(),
+			gitDimStyle.Render("(ctrl+s) save, (esc) cancel"))
+	}
+
+	listWidth := m.width / 3
+	paneHeight := m.height - 10
+
+	var list []string
+	if view := m.filter.View(); view != "" {
+		list = append(list, view, "")
+	}
+	visible := m.visibleSnippets()
+	for i, match := range visible {
+		base := lipgloss.NewStyle()
+		if i == m.cursor {
+			base = base.Background(lipgloss.Color("57"))
+		}
+		name := truncate(match.Name, listWidth-6)
+		line := highlightPositions(name, match.positions, base)
+		if match.Global() {
+			line += base.Render(" ") + snippetGlobalStyle.Inherit(base).Render("◆")
+		}
+
+		var details []string
+		if language := snippetLanguage(match.Snippet); language != "" {
+			details = append(details, language)
+		}
+		for _, tag := range match.Tags {
+			details = append(details, "#"+tag)
+		}
+		line += "\n  " + gitDimStyle.Render(truncate(strings.Join(details, " "), listWidth-8))
+		list = append(list, lipgloss.NewStyle().Width(listWidth-4).Render(line))
+	}
+	if len(visible) == 0 {
+		if m.filter.Active() {
+			list = append(list, gitDimStyle.Render("No matching snippets."))
+		} else {
+			list = append(list, gitDimStyle.Render("No snippets. Press a to add one."))
+		}
+	}
+
+	listPane := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		Padding(0, 1).
+		Width(listWidth - 2).
+		Height(paneHeight).
+		Render(strings.Join(list, "\n"))
+
+	var header string
+	if snippet, ok := m.selectedSnippet(); ok {
+		scope := "this project"
+		if snippet.Global() {
+			scope = snippetGlobalStyle.Render("◆ global")
+		}
+		header = lipgloss.NewStyle().Bold(true).Render(snippet.Name) + gitDimStyle.Render("  ") + scope
+	}
+	previewPane := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("63")).
+		Padding(0, 1).
+		Width(m.width - listWidth - 4).
+		Height(paneHeight).
+		Render(lipgloss.JoinVertical(lipgloss.Left, header, "", m.preview.View()))
+
+	footer := "(enter/c) copy, (i) tweak in $EDITOR and copy, (b) edit body, (E) edit in $EDITOR, (a)dd, (e)dit, (g)lobal, (d)elete, (/) search"
+	if m.message != "" {
+		footer = m.message
+	}
+	return lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.JoinHorizontal(lipgloss.Top, listPane, previewPane),
+		gitDimStyle.Render(footer))
+}
@@ -0,0 +1,98 @@
+package module
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Ceinl/Go-dashboard/internal/storage"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	deploy, _, ok := fuzzyMatch("dpl", "deploy staging")
+	if !ok {
+		t.Fatal("expected dpl to match deploy")
+	}
+	pull, _, ok := fuzzyMatch("dpl", "docker pull")
+	if !ok || pull >= deploy {
+		t.Errorf("expected deploy (%d) to rank above docker pull (%d)", deploy, pull)
+	}
+	if _, positions, _ := fuzzyMatch("SQ", "slow queries"); len(positions) != 2 || positions[0] != 0 || positions[1] != 5 {
+		t.Errorf("unexpected positions %v", positions)
+	}
+	if _, _, ok := fuzzyMatch("xyz", "deploy"); ok {
+		t.Error("expected no match")
+	}
+}
+
+func TestHighlightCode(t *testing.T) {
+	code := "SELECT name FROM users -- all of them\nWHERE id = 42 AND note = 'it''s'"
+	highlighted := highlightCode(code, "postgres")
+	if ansi.Strip(highlighted) != code {
+		t.Errorf("highlighting changed the text:\n%s", ansi.Strip(highlighted))
+	}
+	if !strings.Contains(highlighted, codeKeywordStyle.Render("SELECT")) ||
+		!strings.Contains(highlighted, codeCommentStyle.Render("-- all of them")) ||
+		!strings.Contains(highlighted, codeNumberStyle.Render("42")) {
+		t.Errorf("expected keywords, comments and numbers to be styled, got %q", highlighted)
+	}
+
+	block := highlightCode("x := 1 /* start\nstill comment */ return", "go")
+	if !strings.Contains(block, codeCommentStyle.Render("still comment */")) || !strings.Contains(block, codeKeywordStyle.Render("return")) {
+		t.Errorf("expected the block comment to span lines, got %q", block)
+	}
+	if got := snippetLanguage(storage.Snippet{Name: "deploy.sh"}); got != "sh" {
+		t.Errorf("expected the language to be guessed from the name, got %q", got)
+	}
+}
+
+func TestSnippetsModule(t *testing.T) {
+	db, projectID := setupTestDB(t)
+	var copied []string
+	defer func(original func(string) error) { copyToClipboard = original }(copyToClipboard)
+	copyToClipboard = func(text string) error {
+		copied = append(copied, text)
+		return nil
+	}
+
+	m := NewSnippets(db, projectID)
+	m.Init()
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	// Name, language and tags, then the body in the editor.
+	m = typeKeys(m, "a", "h", "e", "a", "l", "t", "h", "enter", "s", "h", "enter", "h", "t", "t", "p", "enter")
+	snippets := m.(*Snippets)
+	if snippets.mode != snippetsEditing {
+		t.Fatalf("expected to edit the body of the new snippet, got mode %d", snippets.mode)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("curl -s localhost")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if len(snippets.snippets) != 1 || snippets.snippets[0].Body != "curl -s localhost" || snippets.snippets[0].Tags[0] != "http" {
+		t.Fatalf("expected the snippet to be saved, got %+v", snippets.snippets)
+	}
+
+	m = typeKeys(m, "enter")
+	if len(copied) != 1 || copied[0] != "curl -s localhost" {
+		t.Errorf("expected the body to be copied, got %q", copied)
+	}
+
+	m = typeKeys(m, "g")
+	if !snippets.snippets[0].Global() {
+		t.Error("expected the snippet to become global")
+	}
+	others, _ := storage.GetSnippets(db, "another project")
+	if len(others) != 1 {
+		t.Errorf("expected the global snippet in every project, got %d", len(others))
+	}
+
+	storage.CreateSnippet(db, storage.Snippet{ID: "sql", ProjectID: projectID, Name: "slow queries", Language: "sql", Body: "SELECT 1"})
+	snippets.loadSnippets()
+	m = typeKeys(m, "/", "s", "q", "enter")
+	if visible := snippets.visibleSnippets(); len(visible) != 1 || visible[0].Name != "slow queries" {
+		t.Errorf("expected the search to find the query, got %+v", visible)
+	}
+	if view := ansi.Strip(m.View()); !strings.Contains(view, "SELECT 1") {
+		t.Errorf("expected the selected snippet in the preview, got:\n%s", view)
+	}
+}
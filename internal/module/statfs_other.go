@@ -0,0 +1,10 @@
+//go:build !unix
+
+package module
+
+import "errors"
+
+// statfs is only implemented on Unix systems, where /proc exists.
+func statfs(path string) (uint64, uint64, error) {
+	return 0, 0, errors.New("disk usage is not supported on this platform")
+}
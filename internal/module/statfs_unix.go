@@ -0,0 +1,14 @@
+//go:build unix
+
+package module
+
+import "syscall"
+
+// statfs reports the size and free space of the filesystem mounted at path.
+func statfs(path string) (uint64, uint64, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return 0, 0, err
+	}
+	return uint64(st.Blocks) * uint64(st.Bsize), uint64(st.Bavail) * uint64(st.Bsize), nil
+}
@@ -0,0 +1,299 @@
+package module
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// SysmonConfig is read from the "sysmon" section of settings.json.
+type SysmonConfig struct {
+	IntervalSeconds int `json:"interval_seconds,omitempty"` // defaults to 2
+}
+
+const (
+	sysmonHistory   = 60 // samples kept for the sparklines
+	sysmonProcesses = 8
+)
+
+type sysmonSampleMsg struct {
+	owner      *Sysmon
+	generation int
+	sample     sysSample
+}
+
+type sysmonTickMsg struct {
+	owner      *Sysmon
+	generation int
+}
+
+// Sysmon shows CPU, memory, disk, network and process statistics read from
+// /proc. It is Persistent, so its history survives project switches.
+type Sysmon struct {
+	reader   procReader
+	interval time.Duration
+
+	prev       sysSample
+	stats      sysStats
+	cpuHistory []float64
+	memHistory []float64
+	rxHistory  []float64
+	txHistory  []float64
+	generation int
+	width      int
+	height     int
+}
+
+func NewSysmon(config SysmonConfig) Module {
+	return newSysmon(procReader{Root: "/proc", Statfs: statfs}, config)
+}
+
+func newSysmon(reader procReader, config SysmonConfig) *Sysmon {
+	interval := time.Duration(config.IntervalSeconds) * time.Second
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+	return &Sysmon{reader: reader, interval: interval}
+}
+
+func (m *Sysmon) Persistent() {}
+
+func (m *Sysmon) Init() tea.Cmd {
+	// Restart the sampling chain, which stops while the module isn't active.
+	return m.sample()
+}
+
+func (m *Sysmon) sample() tea.Cmd {
+	m.generation++
+	owner, generation, reader := m, m.generation, m.reader
+	return func() tea.Msg {
+		return sysmonSampleMsg{owner: owner, generation: generation, sample: reader.sample(time.Now())}
+	}
+}
+
+func (m *Sysmon) Update(msg tea.Msg) (Module, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	case sysmonSampleMsg:
+		if msg.owner != m || msg.generation != m.generation {
+			return m, nil
+		}
+		m.record(msg.sample)
+		return m, tea.Tick(m.interval, func(time.Time) tea.Msg {
+			return sysmonTickMsg{owner: msg.owner, generation: msg.generation}
+		})
+	case sysmonTickMsg:
+		if msg.owner == m && msg.generation == m.generation {
+			return m, m.sample()
+		}
+	case tea.KeyMsg:
+		if msg.String() == "r" {
+			return m, m.sample()
+		}
+	}
+	return m, nil
+}
+
+// record computes the rates since the previous sample and appends them to
+// the history.
+func (m *Sysmon) record(sample sysSample) {
+	first := m.prev.At.IsZero()
+	m.stats = computeStats(m.prev, sample, sysmonProcesses)
+	m.prev = sample
+	if first {
+		return
+	}
+
+	if len(m.stats.CPU) > 0 {
+		m.cpuHistory = appendHistory(m.cpuHistory, m.stats.CPU[0])
+	}
+	if mem := sample.Mem; mem.Total > 0 {
+		m.memHistory = appendHistory(m.memHistory, float64(mem.Total-mem.Available)/float64(mem.Total))
+	}
+	if sample.Errors["network"] == nil {
+		m.rxHistory = appendHistory(m.rxHistory, m.stats.NetRx)
+		m.txHistory = appendHistory(m.txHistory, m.stats.NetTx)
+	}
+}
+
+func appendHistory(history []float64, value float64) []float64 {
+	history = append(history, value)
+	if len(history) > sysmonHistory {
+		history = history[len(history)-sysmonHistory:]
+	}
+	return history
+}
+
+var (
+	sysmonHeaderStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212"))
+	sysmonDimStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	sysmonSparkStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("39"))
+)
+
+var sparkRunes = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline draws values scaled to max, or to the largest value when max
+// is 0. Only the last width values are drawn.
+func sparkline(values []float64, width int, max float64) string {
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	if max <= 0 {
+		for _, v := range values {
+			max = math.Max(max, v)
+		}
+	}
+	var b strings.Builder
+	for _, v := range values {
+		i := 0
+		if max > 0 {
+			i = int(v / max * float64(len(sparkRunes)-1))
+		}
+		i = int(math.Min(math.Max(float64(i), 0), float64(len(sparkRunes)-1)))
+		b.WriteRune(sparkRunes[i])
+	}
+	return sysmonSparkStyle.Render(b.String())
+}
+
+// usageBar draws a bar filled to frac, colored by how full it is.
+func usageBar(frac float64, width int) string {
+	frac = math.Min(math.Max(frac, 0), 1)
+	filled := int(math.Round(frac * float64(width)))
+	color := lipgloss.Color("42")
+	switch {
+	case frac >= 0.9:
+		color = lipgloss.Color("196")
+	case frac >= 0.7:
+		color = lipgloss.Color("214")
+	}
+	return lipgloss.NewStyle().Foreground(color).Render(strings.Repeat("█", filled)) +
+		sysmonDimStyle.Render(strings.Repeat("░", width-filled))
+}
+
+// formatBytes formats a byte count with a binary unit.
+func formatBytes(n float64) string {
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB"}
+	i := 0
+	for n >= 1024 && i < len(units)-1 {
+		n /= 1024
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%.0f %s", n, units[i])
+	}
+	return fmt.Sprintf("%.1f %s", n, units[i])
+}
+
+func (m *Sysmon) unavailable(section string) string {
+	return sysmonDimStyle.Render("  unavailable: " + m.prev.Errors[section].Error())
+}
+
+func (m *Sysmon) barWidth() int {
+	return max(10, min(40, m.width/3))
+}
+
+func (m *Sysmon) viewCPU() []string {
+	lines := []string{sysmonHeaderStyle.Render("CPU")}
+	if m.prev.Errors["load"] == nil {
+		load := m.prev.Load
+		lines = append(lines, fmt.Sprintf("  Load average %.2f %.2f %.2f", load[0], load[1], load[2]))
+	}
+	if m.prev.Errors["cpu"] != nil {
+		return append(lines, m.unavailable("cpu"))
+	}
+	if len(m.stats.CPU) > 0 {
+		lines = append(lines, fmt.Sprintf("  %-6s %s %5.1f%%  %s", "all", usageBar(m.stats.CPU[0], m.barWidth()),
+			m.stats.CPU[0]*100, sparkline(m.cpuHistory, m.width/3, 1)))
+	}
+	for i, usage := range m.stats.CPU[min(1, len(m.stats.CPU)):] {
+		lines = append(lines, fmt.Sprintf("  %-6s %s %5.1f%%", fmt.Sprintf("cpu%d", i), usageBar(usage, m.barWidth()), usage*100))
+	}
+	return lines
+}
+
+func (m *Sysmon) viewMemory() []string {
+	lines := []string{sysmonHeaderStyle.Render("Memory")}
+	if m.prev.Errors["memory"] != nil {
+		return append(lines, m.unavailable("memory"))
+	}
+	mem := m.prev.Mem
+	used := mem.Total - mem.Available
+	lines = append(lines, fmt.Sprintf("  %-6s %s %s / %s  %s", "RAM", usageBar(float64(used)/float64(mem.Total), m.barWidth()),
+		formatBytes(float64(used)), formatBytes(float64(mem.Total)), sparkline(m.memHistory, m.width/3, 1)))
+	if mem.SwapTotal > 0 {
+		swapUsed := mem.SwapTotal - mem.SwapFree
+		lines = append(lines, fmt.Sprintf("  %-6s %s %s / %s", "Swap", usageBar(float64(swapUsed)/float64(mem.SwapTotal), m.barWidth()),
+			formatBytes(float64(swapUsed)), formatBytes(float64(mem.SwapTotal))))
+	} else {
+		lines = append(lines, sysmonDimStyle.Render("  No swap"))
+	}
+	return lines
+}
+
+func (m *Sysmon) viewDisks() []string {
+	lines := []string{sysmonHeaderStyle.Render("Disks")}
+	if m.prev.Errors["disk"] != nil {
+		return append(lines, m.unavailable("disk"))
+	}
+	if len(m.prev.Mounts) == 0 {
+		return append(lines, sysmonDimStyle.Render("  No mounted disks"))
+	}
+	for _, mount := range m.prev.Mounts {
+		used := mount.Total - mount.Free
+		lines = append(lines, fmt.Sprintf("  %-16s %s %s / %s", truncate(mount.Path, 16),
+			usageBar(float64(used)/float64(mount.Total), m.barWidth()), formatBytes(float64(used)), formatBytes(float64(mount.Total))))
+	}
+	return lines
+}
+
+func (m *Sysmon) viewNetwork() []string {
+	lines := []string{sysmonHeaderStyle.Render("Network")}
+	if m.prev.Errors["network"] != nil {
+		return append(lines, m.unavailable("network"))
+	}
+	var names []string
+	for name := range m.prev.Net {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	lines = append(lines,
+		fmt.Sprintf("  ↓ %12s/s  %s", formatBytes(m.stats.NetRx), sparkline(m.rxHistory, m.width/3, 0)),
+		fmt.Sprintf("  ↑ %12s/s  %s", formatBytes(m.stats.NetTx), sparkline(m.txHistory, m.width/3, 0)),
+		sysmonDimStyle.Render("  Interfaces: "+strings.Join(names, ", ")))
+	return lines
+}
+
+func (m *Sysmon) viewProcesses() []string {
+	lines := []string{sysmonHeaderStyle.Render("Top processes")}
+	if m.prev.Errors["processes"] != nil {
+		return append(lines, m.unavailable("processes"))
+	}
+	if len(m.stats.TopCPU) == 0 {
+		return append(lines, sysmonDimStyle.Render("  Measuring..."))
+	}
+	lines = append(lines, sysmonDimStyle.Render(fmt.Sprintf("  %7s  %-20s %6s %10s", "PID", "NAME", "CPU", "MEMORY")))
+	for _, p := range m.stats.TopCPU {
+		lines = append(lines, fmt.Sprintf("  %7d  %-20s %5.1f%% %10s", p.PID, truncate(p.Name, 20), p.CPU*100, formatBytes(float64(p.RSS))))
+	}
+	return lines
+}
+
+func (m *Sysmon) View() string {
+	if m.prev.At.IsZero() {
+		return sysmonDimStyle.Render("Reading system statistics...")
+	}
+
+	var sections []string
+	for _, section := range [][]string{m.viewCPU(), m.viewMemory(), m.viewDisks(), m.viewNetwork(), m.viewProcesses()} {
+		sections = append(sections, strings.Join(section, "\n"))
+	}
+	footer := sysmonDimStyle.Render(fmt.Sprintf("Every %s, (r)efresh now", m.interval))
+	return lipgloss.NewStyle().Padding(0, 1).Render(strings.Join(append(sections, footer), "\n\n"))
+}
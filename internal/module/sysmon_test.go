@@ -0,0 +1,135 @@
+package module
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// writeProcFile writes a file below a fake /proc root, creating directories.
+func writeProcFile(t *testing.T, root, name, content string) {
+	t.Helper()
+	path := filepath.Join(root, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("failed to create %s: %v", filepath.Dir(path), err)
+	}
+	writeFile(t, path, content)
+}
+
+// procStat builds a /proc/[pid]/stat line with the given CPU ticks and RSS
+// in pages.
+func procStat(pid int, name string, utime, stime, rss int) string {
+	fields := make([]string, 52)
+	for i := range fields {
+		fields[i] = "0"
+	}
+	fields[0], fields[1], fields[2] = strconv.Itoa(pid), "("+name+")", "S"
+	fields[13], fields[14], fields[23] = strconv.Itoa(utime), strconv.Itoa(stime), strconv.Itoa(rss)
+	return strings.Join(fields, " ") + "\n"
+}
+
+func writeFakeProc(t *testing.T, root string, step int) {
+	t.Helper()
+	// Between the two steps each core spends 100 ticks: cpu0 75 busy, cpu1 25.
+	writeProcFile(t, root, "stat", []string{
+		"cpu  200 0 100 700 0 0 0 0 0 0\ncpu0 100 0 50 350 0 0 0 0 0 0\ncpu1 100 0 50 350 0 0 0 0 0 0\nintr 1 2 3\n",
+		"cpu  275 0 125 800 0 0 0 0 0 0\ncpu0 150 0 75 375 0 0 0 0 0 0\ncpu1 125 0 50 425 0 0 0 0 0 0\nintr 1 2 3\n",
+	}[step])
+	writeProcFile(t, root, "loadavg", "0.50 0.25 0.10 1/100 1234\n")
+	writeProcFile(t, root, "meminfo", "MemTotal:       1000 kB\nMemFree:         100 kB\nMemAvailable:    250 kB\nSwapTotal:       400 kB\nSwapFree:        300 kB\n")
+	writeProcFile(t, root, "mounts", "/dev/sda1 / ext4 rw 0 0\nproc /proc proc rw 0 0\n/dev/sdb1 /mnt/my\\040disk ext4 rw 0 0\n")
+	writeProcFile(t, root, "net/dev", []string{
+		"Inter-|   Receive\n face |bytes packets\n    lo: 999 0 0 0 0 0 0 0 999 0 0 0 0 0 0 0\n  eth0: 1000 0 0 0 0 0 0 0 500 0 0 0 0 0 0 0\n",
+		"Inter-|   Receive\n face |bytes packets\n    lo: 9999 0 0 0 0 0 0 0 9999 0 0 0 0 0 0 0\n  eth0: 3000 0 0 0 0 0 0 0 1500 0 0 0 0 0 0 0\n",
+	}[step])
+	writeProcFile(t, root, "1/stat", procStat(1, "init", 10, 10, 100))
+	writeProcFile(t, root, "42/stat", procStat(42, "web (worker)", 100+150*step, 50+50*step, 200))
+}
+
+func fakeStatfs(path string) (uint64, uint64, error) {
+	switch path {
+	case "/":
+		return 100 << 30, 40 << 30, nil
+	case "/mnt/my disk":
+		return 10 << 30, 10 << 30, nil
+	}
+	return 0, 0, errors.New("not mounted")
+}
+
+func TestProcReaderSample(t *testing.T) {
+	root := t.TempDir()
+	reader := procReader{Root: root, Statfs: fakeStatfs}
+
+	writeFakeProc(t, root, 0)
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	first := reader.sample(start)
+	if len(first.Errors) != 0 {
+		t.Fatalf("unexpected errors: %v", first.Errors)
+	}
+	if len(first.CPUs) != 3 {
+		t.Fatalf("expected total and 2 cores, got %d", len(first.CPUs))
+	}
+	if first.Load != [3]float64{0.5, 0.25, 0.1} {
+		t.Errorf("unexpected load %v", first.Load)
+	}
+	if first.Mem != (memInfo{Total: 1000 * 1024, Available: 250 * 1024, SwapTotal: 400 * 1024, SwapFree: 300 * 1024}) {
+		t.Errorf("unexpected memory %+v", first.Mem)
+	}
+	if len(first.Mounts) != 2 || first.Mounts[0].Path != "/" || first.Mounts[1].Path != "/mnt/my disk" {
+		t.Errorf("unexpected mounts %+v", first.Mounts)
+	}
+	if _, ok := first.Net["lo"]; ok || len(first.Net) != 1 {
+		t.Errorf("expected only eth0, got %v", first.Net)
+	}
+	if p := first.Procs[42]; p.Name != "web (worker)" || p.Ticks != 150 || p.RSS != 200*uint64(os.Getpagesize()) {
+		t.Errorf("unexpected process %+v", p)
+	}
+
+	writeFakeProc(t, root, 1)
+	second := reader.sample(start.Add(2 * time.Second))
+	stats := computeStats(first, second, 1)
+
+	want := []float64{0.5, 0.75, 0.25}
+	for i, usage := range stats.CPU {
+		if math.Abs(usage-want[i]) > 1e-9 {
+			t.Errorf("CPU %d: expected %.2f, got %.2f", i, want[i], usage)
+		}
+	}
+	if stats.NetRx != 1000 || stats.NetTx != 500 {
+		t.Errorf("expected 1000/500 B/s, got %v/%v", stats.NetRx, stats.NetTx)
+	}
+	// 200 ticks over 2 seconds at 100 ticks per second is one full core.
+	if len(stats.TopCPU) != 1 || stats.TopCPU[0].PID != 42 || math.Abs(stats.TopCPU[0].CPU-1) > 1e-9 {
+		t.Errorf("unexpected top processes %+v", stats.TopCPU)
+	}
+}
+
+func TestSysmonMissingFiles(t *testing.T) {
+	root := t.TempDir()
+	writeProcFile(t, root, "loadavg", "1.00 0.50 0.25 1/100 1234\n")
+
+	m := newSysmon(procReader{Root: root, Statfs: fakeStatfs}, SysmonConfig{})
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	msg := m.Init()()
+	m.Update(msg)
+
+	for _, section := range []string{"cpu", "memory", "disk", "network"} {
+		if m.prev.Errors[section] == nil {
+			t.Errorf("expected an error for %s", section)
+		}
+	}
+	view := m.View()
+	if !strings.Contains(view, "unavailable") {
+		t.Errorf("expected unavailable sections, got:\n%s", view)
+	}
+	if !strings.Contains(view, "Load average 1.00 0.50 0.25") {
+		t.Errorf("expected the load average, got:\n%s", view)
+	}
+}
@@ -0,0 +1,297 @@
+package module
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Ceinl/Go-dashboard/internal/storage"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/google/uuid"
+)
+
+// TimerConfig holds the focus timer intervals, in minutes. It is read from
+// the "timer" section of settings.json; zero values fall back to the classic
+// 25/5/15 Pomodoro schedule.
+type TimerConfig struct {
+	WorkMinutes      int `json:"work_minutes,omitempty"`
+	BreakMinutes     int `json:"break_minutes,omitempty"`
+	LongBreakMinutes int `json:"long_break_minutes,omitempty"`
+	LongBreakEvery   int `json:"long_break_every,omitempty"` // work sessions between long breaks
+}
+
+func (c TimerConfig) withDefaults() TimerConfig {
+	if c.WorkMinutes <= 0 {
+		c.WorkMinutes = 25
+	}
+	if c.BreakMinutes <= 0 {
+		c.BreakMinutes = 5
+	}
+	if c.LongBreakMinutes <= 0 {
+		c.LongBreakMinutes = 15
+	}
+	if c.LongBreakEvery <= 0 {
+		c.LongBreakEvery = 4
+	}
+	return c
+}
+
+type timerPhase int
+
+const (
+	phaseWork timerPhase = iota
+	phaseBreak
+	phaseLongBreak
+)
+
+func (p timerPhase) String() string {
+	switch p {
+	case phaseBreak:
+		return "Break"
+	case phaseLongBreak:
+		return "Long break"
+	}
+	return "Work"
+}
+
+// StartFocusMsg attaches a task to the focus timer and starts a work session.
+// The Kanban board sends it for the selected card.
+type StartFocusMsg struct {
+	Task storage.Task
+}
+
+// timerTickMsg drives the countdown. Each run of the timer gets a new
+// generation so that ticks from an earlier chain are ignored.
+type timerTickMsg struct {
+	generation int
+}
+
+// Timer is a Pomodoro-style focus timer. It is Persistent, so switching
+// projects doesn't reset a running session.
+type Timer struct {
+	db     *sql.DB
+	config TimerConfig
+
+	phase        timerPhase
+	running      bool
+	endsAt       time.Time     // when the current phase ends, while running
+	remaining    time.Duration // time left, while paused
+	startedAt    time.Time     // start of the current work session
+	completed    int           // work sessions finished since the last long break
+	generation   int
+	task         storage.Task // attached task, if any
+	width        int
+	height       int
+	lastFinished string // shown briefly after a phase ends
+}
+
+func NewTimer(db *sql.DB, config TimerConfig) Module {
+	config = config.withDefaults()
+	return &Timer{
+		db:        db,
+		config:    config,
+		remaining: time.Duration(config.WorkMinutes) * time.Minute,
+	}
+}
+
+func (m *Timer) Persistent() {}
+
+func (m *Timer) Init() tea.Cmd {
+	if m.running {
+		// The tick chain may have stopped while the module wasn't active.
+		return m.tick()
+	}
+	return nil
+}
+
+func (m *Timer) tick() tea.Cmd {
+	m.generation++
+	generation := m.generation
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return timerTickMsg{generation: generation}
+	})
+}
+
+func (m *Timer) Update(msg tea.Msg) (Module, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	case timerTickMsg:
+		if msg.generation != m.generation || !m.running {
+			return m, nil
+		}
+		if !time.Now().Before(m.endsAt) {
+			m.finishPhase(time.Now(), true)
+			return m, nil
+		}
+		return m, m.tick()
+	case StartFocusMsg:
+		m.task = msg.Task
+		m.setPhase(phaseWork)
+		return m, m.start()
+	case tea.KeyMsg:
+		switch msg.String() {
+		case " ", "enter":
+			if m.running {
+				m.pause()
+				return m, nil
+			}
+			return m, m.start()
+		case "r":
+			m.running = false
+			m.setPhase(m.phase)
+		case "s":
+			m.finishPhase(time.Now(), false)
+		case "x":
+			m.task = storage.Task{}
+		}
+	}
+	return m, nil
+}
+
+func (m *Timer) start() tea.Cmd {
+	m.running = true
+	m.endsAt = time.Now().Add(m.remaining)
+	if m.phase == phaseWork && m.startedAt.IsZero() {
+		m.startedAt = time.Now()
+	}
+	m.lastFinished = ""
+	return m.tick()
+}
+
+func (m *Timer) pause() {
+	m.running = false
+	m.remaining = time.Until(m.endsAt)
+}
+
+func (m *Timer) setPhase(phase timerPhase) {
+	m.phase = phase
+	m.startedAt = time.Time{}
+	switch phase {
+	case phaseBreak:
+		m.remaining = time.Duration(m.config.BreakMinutes) * time.Minute
+	case phaseLongBreak:
+		m.remaining = time.Duration(m.config.LongBreakMinutes) * time.Minute
+	default:
+		m.remaining = time.Duration(m.config.WorkMinutes) * time.Minute
+	}
+}
+
+// finishPhase moves on to the next phase, which waits for the user to start
+// it. Work sessions that ran to the end are recorded against the attached
+// task; skipped ones are not.
+func (m *Timer) finishPhase(now time.Time, ranToEnd bool) {
+	m.running = false
+
+	if m.phase != phaseWork {
+		m.lastFinished = m.phase.String() + " over"
+		m.setPhase(phaseWork)
+		return
+	}
+
+	if !ranToEnd {
+		m.lastFinished = "Work session skipped"
+		m.setPhase(phaseBreak)
+		return
+	}
+
+	m.lastFinished = "Work session complete"
+	m.completed++
+	m.recordSession(now)
+	if m.completed >= m.config.LongBreakEvery {
+		m.completed = 0
+		m.setPhase(phaseLongBreak)
+	} else {
+		m.setPhase(phaseBreak)
+	}
+}
+
+func (m *Timer) recordSession(now time.Time) {
+	if m.task.ID == "" || m.startedAt.IsZero() {
+		return
+	}
+	session := storage.FocusSession{
+		ID:        uuid.New().String(),
+		TaskID:    m.task.ID,
+		StartedAt: m.startedAt,
+		EndedAt:   now,
+	}
+	if err := storage.CreateFocusSession(m.db, session); err != nil {
+		log.Printf("Error recording focus session: %v", err)
+	}
+}
+
+func (m *Timer) timeLeft() time.Duration {
+	if m.running {
+		if left := time.Until(m.endsAt); left > 0 {
+			return left
+		}
+		return 0
+	}
+	return m.remaining
+}
+
+func formatCountdown(d time.Duration) string {
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%02d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
+
+// StatusText shows the countdown in the status bar while a session is in
+// progress.
+func (m *Timer) StatusText() string {
+	if !m.running && m.remaining == m.fullLength() {
+		return ""
+	}
+	text := "◷ " + m.phase.String() + " " + formatCountdown(m.timeLeft())
+	if !m.running {
+		text += " (paused)"
+	}
+	return text
+}
+
+func (m *Timer) fullLength() time.Duration {
+	switch m.phase {
+	case phaseBreak:
+		return time.Duration(m.config.BreakMinutes) * time.Minute
+	case phaseLongBreak:
+		return time.Duration(m.config.LongBreakMinutes) * time.Minute
+	}
+	return time.Duration(m.config.WorkMinutes) * time.Minute
+}
+
+func (m *Timer) View() string {
+	phaseColor := lipgloss.Color("212")
+	if m.phase != phaseWork {
+		phaseColor = lipgloss.Color("42")
+	}
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+
+	state := "paused"
+	if m.running {
+		state = "running"
+	}
+
+	lines := []string{
+		lipgloss.NewStyle().Bold(true).Foreground(phaseColor).Render(m.phase.String()),
+		"",
+		lipgloss.NewStyle().Bold(true).Padding(0, 2).Border(lipgloss.RoundedBorder()).BorderForeground(phaseColor).Render(formatCountdown(m.timeLeft())),
+		dimStyle.Render(state),
+		"",
+	}
+	if m.task.ID != "" {
+		lines = append(lines, "Task: "+m.task.Title)
+	} else {
+		lines = append(lines, dimStyle.Render("No task attached. Press f on a Kanban card to focus on it."))
+	}
+	lines = append(lines, dimStyle.Render(fmt.Sprintf("Session %d of %d before a long break", m.completed+1, m.config.LongBreakEvery)))
+	if m.lastFinished != "" {
+		lines = append(lines, "", m.lastFinished)
+	}
+	lines = append(lines, "", dimStyle.Render("(space) start/pause, (r)eset, (s)kip, (x) detach task"))
+
+	return lipgloss.Place(m.width, m.height-10, lipgloss.Center, lipgloss.Center,
+		lipgloss.JoinVertical(lipgloss.Center, lines...))
+}
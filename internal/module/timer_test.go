@@ -0,0 +1,48 @@
+package module
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Ceinl/Go-dashboard/internal/storage"
+	"github.com/google/uuid"
+)
+
+func TestTimerRecordsCompletedWorkSessions(t *testing.T) {
+	db, projectID := setupTestDB(t)
+	task := storage.Task{ID: uuid.New().String(), ProjectID: projectID, Title: "focus", Status: ToDo}
+	if err := storage.CreateTask(db, task); err != nil {
+		t.Fatalf("failed to create task: %v", err)
+	}
+
+	m, _ := NewTimer(db, TimerConfig{LongBreakEvery: 2}).Update(StartFocusMsg{Task: task})
+	timer := m.(*Timer)
+	if !timer.running || timer.phase != phaseWork {
+		t.Fatalf("expected a running work session")
+	}
+
+	timer.finishPhase(time.Now(), true)
+	if timer.phase != phaseBreak || timer.running {
+		t.Errorf("expected a paused break after the first session, got %v (running %v)", timer.phase, timer.running)
+	}
+
+	// Skipped work sessions are not recorded.
+	timer.finishPhase(time.Now(), true)
+	timer.start()
+	timer.finishPhase(time.Now(), false)
+
+	timer.setPhase(phaseWork)
+	timer.start()
+	timer.finishPhase(time.Now(), true)
+	if timer.phase != phaseLongBreak {
+		t.Errorf("expected a long break after two sessions, got %v", timer.phase)
+	}
+
+	counts, err := storage.GetFocusSessionCounts(db, projectID)
+	if err != nil {
+		t.Fatalf("failed to get focus session counts: %v", err)
+	}
+	if counts[task.ID] != 2 {
+		t.Errorf("expected 2 recorded sessions, got %d", counts[task.ID])
+	}
+}
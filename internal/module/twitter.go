@@ -60,6 +60,10 @@ func (m *Twitter) Update(msg tea.Msg) (Module, tea.Cmd) {
 		m.drafts.SetHeight(m.height - 5)
 		m.editor.SetWidth(m.width * 2 / 3)
 	case tea.KeyMsg:
+		if m.drafts.SettingFilter() {
+			// Let the list's own "/" filter consume the keys while typing.
+			break
+		}
 		switch msg.String() {
 		case "ctrl+s":
 			if m.editing {
@@ -107,6 +111,13 @@ func (m *Twitter) Update(msg tea.Msg) (Module, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// CapturingInput reports whether the module needs every key press.
+func (m *Twitter) CapturingInput() bool {
+	return m.editing || m.drafts.SettingFilter()
+}
+
+func (m *Twitter) Filters() {}
+
 func (m *Twitter) View() string {
 	if m.width == 0 {
 		return "loading..."
@@ -116,7 +127,7 @@ func (m *Twitter) View() string {
 	if m.editing {
 		helpView = "(ctrl+s) save, (esc) cancel"
 	} else {
-		helpView = "(n)ew, (enter) edit, (j/k) navigate"
+		helpView = "(n)ew, (enter) edit, (j/k) navigate, (/) filter"
 	}
 
 	draftsView := m.drafts.View()
@@ -148,6 +159,18 @@ func (m *Twitter) loadTweets() {
 	m.drafts.SetItems(items)
 }
 
+// FocusItem selects the draft with the given ID.
+func (m *Twitter) FocusItem(id string) bool {
+	m.drafts.ResetFilter()
+	for i, item := range m.drafts.Items() {
+		if item.(storage.Tweet).ID == id {
+			m.drafts.Select(i)
+			return true
+		}
+	}
+	return false
+}
+
 func (m *Twitter) saveDraft() {
 	content := m.editor.Value()
 	if m.projectID != "" && content != "" {
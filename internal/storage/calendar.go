@@ -0,0 +1,55 @@
+package storage
+
+import "database/sql"
+
+// DueTask is a task with a due date, together with the name of its project.
+type DueTask struct {
+	Task
+	ProjectName string
+	WorkspaceID string
+}
+
+// GetDueTasks returns the tasks with a due date in a workspace, or only in
+// one project when projectID is set, ordered by due date.
+func GetDueTasks(db *sql.DB, workspaceID, projectID string) ([]DueTask, error) {
+	rows, err := db.Query(`
+		SELECT t.id, t.project_id, t.title, t.status, COALESCE(t.description, ''), t.due_date,
+			COALESCE(t.repo_path, ''), COALESCE(t.branch, ''), p.name, p.workspace_id
+		FROM tasks t JOIN projects p ON p.id = t.project_id
+		WHERE p.workspace_id = ? AND (? = '' OR p.id = ?) AND COALESCE(t.due_date, '') != ''
+		ORDER BY t.due_date, t.title`, workspaceID, projectID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []DueTask
+	for rows.Next() {
+		var task DueTask
+		if err := rows.Scan(&task.ID, &task.ProjectID, &task.Title, &task.Status, &task.Description, &task.DueDate,
+			&task.RepoPath, &task.Branch, &task.ProjectName, &task.WorkspaceID); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, rows.Err()
+}
+
+// GetWorkspaceCalendarPath returns the path of the .ics file whose events the
+// calendar shows for a workspace, or "" if none is configured.
+func GetWorkspaceCalendarPath(db *sql.DB, workspaceID string) (string, error) {
+	var path string
+	err := db.QueryRow("SELECT calendar_path FROM workspaces WHERE id = ?", workspaceID).Scan(&path)
+	return path, err
+}
+
+func SetWorkspaceCalendarPath(db *sql.DB, workspaceID, path string) error {
+	stmt, err := db.Prepare("UPDATE workspaces SET calendar_path = ? WHERE id = ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(path, workspaceID)
+	return err
+}
@@ -0,0 +1,194 @@
+package storage
+
+import (
+	"database/sql"
+)
+
+// ChecklistItem is one step of a task's checklist. Items are kept in
+// Position order. Their events in the activity log have the task's title as
+// Summary and the item's text as OldValue or NewValue.
+type ChecklistItem struct {
+	ID       string
+	TaskID   string
+	Position int
+	Text     string
+	Done     bool
+}
+
+// ChecklistProgress counts the checked and total items of a checklist.
+type ChecklistProgress struct {
+	Done  int
+	Total int
+}
+
+// Complete reports whether the checklist has items and all are checked.
+func (p ChecklistProgress) Complete() bool {
+	return p.Total > 0 && p.Done == p.Total
+}
+
+func GetChecklist(db *sql.DB, taskID string) ([]ChecklistItem, error) {
+	rows, err := db.Query("SELECT id, task_id, position, text, done FROM checklist_items WHERE task_id = ? ORDER BY position, rowid", taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []ChecklistItem
+	for rows.Next() {
+		var item ChecklistItem
+		if err := rows.Scan(&item.ID, &item.TaskID, &item.Position, &item.Text, &item.Done); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// GetChecklistProgress returns the progress of every task with a checklist
+// in a project, by task ID.
+func GetChecklistProgress(db *sql.DB, projectID string) (map[string]ChecklistProgress, error) {
+	rows, err := db.Query(`
+		SELECT c.task_id, SUM(c.done), COUNT(*)
+		FROM checklist_items c JOIN tasks t ON t.id = c.task_id
+		WHERE t.project_id = ?
+		GROUP BY c.task_id`, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	progress := make(map[string]ChecklistProgress)
+	for rows.Next() {
+		var taskID string
+		var p ChecklistProgress
+		if err := rows.Scan(&taskID, &p.Done, &p.Total); err != nil {
+			return nil, err
+		}
+		progress[taskID] = p
+	}
+	return progress, rows.Err()
+}
+
+// CreateChecklistItem appends an item to the end of its task's checklist.
+func CreateChecklistItem(db *sql.DB, item ChecklistItem) error {
+	stmt, err := db.Prepare(`
+		INSERT INTO checklist_items(id, task_id, position, text, done)
+		VALUES(?, ?, (SELECT COALESCE(MAX(position), -1) + 1 FROM checklist_items WHERE task_id = ?), ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(item.ID, item.TaskID, item.TaskID, item.Text, item.Done)
+	if err != nil {
+		return err
+	}
+
+	var projectID, title sql.NullString
+	err = db.QueryRow("SELECT project_id, title FROM tasks WHERE id = ?", item.TaskID).Scan(&projectID, &title)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	return recordEvent(db, Event{ProjectID: projectID.String, EntityType: EntityChecklist, EntityID: item.ID, Action: ActionCreate, Summary: title.String, NewValue: item.Text})
+}
+
+// UpdateChecklistItem saves the text and checked state of an item. Checking
+// or unchecking it is logged as ActionCheck or ActionUncheck.
+func UpdateChecklistItem(db *sql.DB, item ChecklistItem) error {
+	var projectID, title, oldText sql.NullString
+	var wasDone bool
+	err := db.QueryRow(`
+		SELECT t.project_id, t.title, c.text, c.done
+		FROM checklist_items c LEFT JOIN tasks t ON t.id = c.task_id
+		WHERE c.id = ?`, item.ID).Scan(&projectID, &title, &oldText, &wasDone)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	stmt, err := db.Prepare("UPDATE checklist_items SET text = ?, done = ? WHERE id = ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(item.Text, item.Done, item.ID)
+	if err != nil {
+		return err
+	}
+
+	event := Event{ProjectID: projectID.String, EntityType: EntityChecklist, EntityID: item.ID, Action: ActionUpdate, Summary: title.String, OldValue: oldText.String, NewValue: item.Text}
+	if item.Done != wasDone {
+		event.Action, event.OldValue = ActionUncheck, ""
+		if item.Done {
+			event.Action = ActionCheck
+		}
+	}
+	return recordEvent(db, event)
+}
+
+func DeleteChecklistItem(db *sql.DB, id string) error {
+	var projectID, title, text sql.NullString
+	err := db.QueryRow(`
+		SELECT t.project_id, t.title, c.text
+		FROM checklist_items c LEFT JOIN tasks t ON t.id = c.task_id
+		WHERE c.id = ?`, id).Scan(&projectID, &title, &text)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	stmt, err := db.Prepare("DELETE FROM checklist_items WHERE id = ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(id)
+	if err != nil {
+		return err
+	}
+	return recordEvent(db, Event{ProjectID: projectID.String, EntityType: EntityChecklist, EntityID: id, Action: ActionDelete, Summary: title.String, OldValue: text.String})
+}
+
+// ReorderChecklist saves the order of a checklist, given as item IDs. It is
+// logged as an update of the task.
+func ReorderChecklist(db *sql.DB, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	var taskID, projectID, title sql.NullString
+	err := db.QueryRow(`
+		SELECT t.id, t.project_id, t.title
+		FROM checklist_items c JOIN tasks t ON t.id = c.task_id
+		WHERE c.id = ?`, ids[0]).Scan(&taskID, &projectID, &title)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare("UPDATE checklist_items SET position = ? WHERE id = ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for i, id := range ids {
+		if _, err := stmt.Exec(i, id); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	if !taskID.Valid {
+		return nil
+	}
+	return recordEvent(db, Event{ProjectID: projectID.String, EntityType: EntityTask, EntityID: taskID.String, Action: ActionUpdate, Summary: title.String})
+}
@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestChecklistOrderAndProgress(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	projectID := uuid.New().String()
+	task := Task{ID: uuid.New().String(), ProjectID: projectID, Title: "Release", Status: "To Do", AutoDone: true}
+	if err := CreateTask(db, task); err != nil {
+		t.Fatalf("failed to create task: %v", err)
+	}
+	if got, _ := GetTask(db, task.ID); !got.AutoDone {
+		t.Error("expected AutoDone to be saved")
+	}
+
+	var ids []string
+	for _, text := range []string{"Tag", "Build", "Announce"} {
+		item := ChecklistItem{ID: uuid.New().String(), TaskID: task.ID, Text: text}
+		if err := CreateChecklistItem(db, item); err != nil {
+			t.Fatalf("failed to create item: %v", err)
+		}
+		ids = append(ids, item.ID)
+	}
+	if err := ReorderChecklist(db, []string{ids[1], ids[0], ids[2]}); err != nil {
+		t.Fatalf("failed to reorder: %v", err)
+	}
+	items, err := GetChecklist(db, task.ID)
+	if err != nil || len(items) != 3 || items[0].Text != "Build" || items[1].Text != "Tag" {
+		t.Fatalf("expected the reordered checklist, got %+v (%v)", items, err)
+	}
+
+	items[0].Done = true
+	if err := UpdateChecklistItem(db, items[0]); err != nil {
+		t.Fatalf("failed to check item: %v", err)
+	}
+	progress, err := GetChecklistProgress(db, projectID)
+	if err != nil || progress[task.ID] != (ChecklistProgress{Done: 1, Total: 3}) || progress[task.ID].Complete() {
+		t.Errorf("expected 1 of 3 items done, got %v (%v)", progress, err)
+	}
+
+	// A new item goes to the end, after the reordered ones.
+	if err := CreateChecklistItem(db, ChecklistItem{ID: uuid.New().String(), TaskID: task.ID, Text: "Celebrate"}); err != nil {
+		t.Fatalf("failed to create item: %v", err)
+	}
+	if items, _ = GetChecklist(db, task.ID); items[3].Text != "Celebrate" {
+		t.Errorf("expected the new item last, got %+v", items)
+	}
+
+	if err := DeleteTask(db, task.ID); err != nil {
+		t.Fatalf("failed to delete task: %v", err)
+	}
+	if items, _ = GetChecklist(db, task.ID); len(items) != 0 {
+		t.Errorf("expected the checklist to be deleted with the task, got %d items", len(items))
+	}
+}
@@ -0,0 +1,158 @@
+package storage
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// RunLogLimit is how many run logs are kept per command.
+const RunLogLimit = 10
+
+// Command is a saved shell command of a project, run by the runner module.
+type Command struct {
+	ID        string
+	ProjectID string
+	Name      string
+	Command   string
+	WorkDir   string
+	Env       string // KEY=VALUE pairs, one per line
+}
+
+// EnvList returns the command's environment variables as KEY=VALUE strings.
+func (c Command) EnvList() []string {
+	var env []string
+	for _, line := range strings.Split(c.Env, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			env = append(env, line)
+		}
+	}
+	return env
+}
+
+// CommandRun is the log of one finished run of a command.
+type CommandRun struct {
+	ID        string
+	CommandID string
+	StartedAt time.Time
+	EndedAt   time.Time
+	ExitCode  int
+	Output    string
+}
+
+// Duration returns how long the run took.
+func (r CommandRun) Duration() time.Duration {
+	return r.EndedAt.Sub(r.StartedAt)
+}
+
+func GetCommandsForProject(db *sql.DB, projectID string) ([]Command, error) {
+	rows, err := db.Query("SELECT id, project_id, name, command, work_dir, env FROM commands WHERE project_id = ? ORDER BY name", projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var commands []Command
+	for rows.Next() {
+		var command Command
+		if err := rows.Scan(&command.ID, &command.ProjectID, &command.Name, &command.Command, &command.WorkDir, &command.Env); err != nil {
+			return nil, err
+		}
+		commands = append(commands, command)
+	}
+	return commands, rows.Err()
+}
+
+func CreateCommand(db *sql.DB, command Command) error {
+	stmt, err := db.Prepare("INSERT INTO commands(id, project_id, name, command, work_dir, env) VALUES(?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(command.ID, command.ProjectID, command.Name, command.Command, command.WorkDir, command.Env)
+	if err != nil {
+		return err
+	}
+	return recordEvent(db, Event{ProjectID: command.ProjectID, EntityType: EntityCommand, EntityID: command.ID, Action: ActionCreate, Summary: command.Name, NewValue: command.Command})
+}
+
+func UpdateCommand(db *sql.DB, command Command) error {
+	stmt, err := db.Prepare("UPDATE commands SET name = ?, command = ?, work_dir = ?, env = ? WHERE id = ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(command.Name, command.Command, command.WorkDir, command.Env, command.ID)
+	if err != nil {
+		return err
+	}
+	return recordEvent(db, Event{ProjectID: command.ProjectID, EntityType: EntityCommand, EntityID: command.ID, Action: ActionUpdate, Summary: command.Name, NewValue: command.Command})
+}
+
+func DeleteCommand(db *sql.DB, id string) error {
+	var projectID, name sql.NullString
+	err := db.QueryRow("SELECT project_id, name FROM commands WHERE id = ?", id).Scan(&projectID, &name)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	if _, err := db.Exec("DELETE FROM command_runs WHERE command_id = ?", id); err != nil {
+		return err
+	}
+	stmt, err := db.Prepare("DELETE FROM commands WHERE id = ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(id)
+	if err != nil {
+		return err
+	}
+	return recordEvent(db, Event{ProjectID: projectID.String, EntityType: EntityCommand, EntityID: id, Action: ActionDelete, Summary: name.String})
+}
+
+// CreateCommandRun saves the log of a finished run and drops the oldest logs
+// of the command beyond RunLogLimit.
+func CreateCommandRun(db *sql.DB, run CommandRun) error {
+	stmt, err := db.Prepare("INSERT INTO command_runs(id, command_id, started_at, ended_at, exit_code, output) VALUES(?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(run.ID, run.CommandID, formatTimestamp(run.StartedAt), formatTimestamp(run.EndedAt), run.ExitCode, run.Output)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		DELETE FROM command_runs WHERE command_id = ? AND id NOT IN (
+			SELECT id FROM command_runs WHERE command_id = ? ORDER BY started_at DESC LIMIT ?
+		)`, run.CommandID, run.CommandID, RunLogLimit)
+	return err
+}
+
+// GetCommandRuns returns the kept run logs of a command, newest first.
+func GetCommandRuns(db *sql.DB, commandID string) ([]CommandRun, error) {
+	rows, err := db.Query("SELECT id, command_id, started_at, ended_at, exit_code, output FROM command_runs WHERE command_id = ? ORDER BY started_at DESC", commandID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []CommandRun
+	for rows.Next() {
+		var run CommandRun
+		var startedAt, endedAt string
+		if err := rows.Scan(&run.ID, &run.CommandID, &startedAt, &endedAt, &run.ExitCode, &run.Output); err != nil {
+			return nil, err
+		}
+		run.StartedAt = parseTimestamp(startedAt)
+		run.EndedAt = parseTimestamp(endedAt)
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCommandRunsArePruned(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	command := Command{ID: uuid.New().String(), ProjectID: uuid.New().String(), Name: "tests", Command: "go test ./...", Env: "A=1\n\nB=two words\n"}
+	if err := CreateCommand(db, command); err != nil {
+		t.Fatalf("failed to create command: %v", err)
+	}
+	if env := command.EnvList(); len(env) != 2 || env[1] != "B=two words" {
+		t.Errorf("unexpected environment %q", env)
+	}
+
+	start := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	for i := 0; i < RunLogLimit+3; i++ {
+		run := CommandRun{
+			ID:        uuid.New().String(),
+			CommandID: command.ID,
+			StartedAt: start.Add(time.Duration(i) * time.Minute),
+			EndedAt:   start.Add(time.Duration(i)*time.Minute + 5*time.Second),
+			ExitCode:  i % 2,
+			Output:    fmt.Sprintf("run %d", i),
+		}
+		if err := CreateCommandRun(db, run); err != nil {
+			t.Fatalf("failed to save run: %v", err)
+		}
+	}
+
+	runs, err := GetCommandRuns(db, command.ID)
+	if err != nil {
+		t.Fatalf("failed to get runs: %v", err)
+	}
+	if len(runs) != RunLogLimit {
+		t.Fatalf("expected %d runs, got %d", RunLogLimit, len(runs))
+	}
+	if want := fmt.Sprintf("run %d", RunLogLimit+2); runs[0].Output != want || runs[0].Duration() != 5*time.Second {
+		t.Errorf("expected newest run %q lasting 5s, got %q lasting %v", want, runs[0].Output, runs[0].Duration())
+	}
+
+	if err := DeleteCommand(db, command.ID); err != nil {
+		t.Fatalf("failed to delete command: %v", err)
+	}
+	if runs, _ := GetCommandRuns(db, command.ID); len(runs) != 0 {
+		t.Errorf("expected runs to be deleted with the command, got %d", len(runs))
+	}
+}
@@ -0,0 +1,155 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+)
+
+// ErrDependencyCycle is returned when a dependency would make a task
+// transitively block itself.
+var ErrDependencyCycle = errors.New("the dependency would create a cycle")
+
+// LinkedTask is a task on the other end of a dependency, which may belong to
+// another project, together with that project's name.
+type LinkedTask struct {
+	Task
+	ProjectName string
+}
+
+const linkedTaskColumns = `t.id, t.project_id, t.title, t.status, COALESCE(t.description, ''), COALESCE(t.due_date, ''),
+	COALESCE(t.repo_path, ''), COALESCE(t.branch, ''), t.auto_done, COALESCE(p.name, '')`
+
+func scanLinkedTasks(rows *sql.Rows) ([]LinkedTask, error) {
+	defer rows.Close()
+
+	var tasks []LinkedTask
+	for rows.Next() {
+		var task LinkedTask
+		if err := rows.Scan(&task.ID, &task.ProjectID, &task.Title, &task.Status, &task.Description, &task.DueDate,
+			&task.RepoPath, &task.Branch, &task.AutoDone, &task.ProjectName); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, rows.Err()
+}
+
+// AddDependency records that taskID is blocked by blockedBy. It returns
+// ErrDependencyCycle if blockedBy is already, directly or not, blocked by
+// taskID.
+func AddDependency(db *sql.DB, taskID, blockedBy string) error {
+	if taskID == blockedBy {
+		return ErrDependencyCycle
+	}
+	var cycle int
+	err := db.QueryRow(`
+		WITH RECURSIVE blockers(id) AS (
+			SELECT blocked_by FROM task_dependencies WHERE task_id = ?
+			UNION
+			SELECT d.blocked_by FROM task_dependencies d JOIN blockers b ON d.task_id = b.id
+		)
+		SELECT COUNT(*) FROM blockers WHERE id = ?`, blockedBy, taskID).Scan(&cycle)
+	if err != nil {
+		return err
+	}
+	if cycle > 0 {
+		return ErrDependencyCycle
+	}
+
+	result, err := db.Exec("INSERT OR IGNORE INTO task_dependencies(task_id, blocked_by) VALUES(?, ?)", taskID, blockedBy)
+	if err != nil {
+		return err
+	}
+	return recordDependencyEvent(db, result, ActionCreate, taskID, blockedBy)
+}
+
+func RemoveDependency(db *sql.DB, taskID, blockedBy string) error {
+	result, err := db.Exec("DELETE FROM task_dependencies WHERE task_id = ? AND blocked_by = ?", taskID, blockedBy)
+	if err != nil {
+		return err
+	}
+	return recordDependencyEvent(db, result, ActionDelete, taskID, blockedBy)
+}
+
+// recordDependencyEvent logs a dependency that was added or removed, in the
+// project of the blocked task, with that task's title as Summary and the
+// blocker's as NewValue. Nothing is logged if result changed no row.
+func recordDependencyEvent(db *sql.DB, result sql.Result, action, taskID, blockedBy string) error {
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return err
+	}
+	var projectID, title, blocker sql.NullString
+	err := db.QueryRow(`
+		SELECT t.project_id, t.title, (SELECT title FROM tasks WHERE id = ?)
+		FROM tasks t WHERE t.id = ?`, blockedBy, taskID).Scan(&projectID, &title, &blocker)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	return recordEvent(db, Event{ProjectID: projectID.String, EntityType: EntityBlocker, EntityID: taskID, Action: action, Summary: title.String, NewValue: blocker.String})
+}
+
+// GetBlockers returns the tasks that directly block taskID.
+func GetBlockers(db *sql.DB, taskID string) ([]LinkedTask, error) {
+	rows, err := db.Query(`
+		SELECT `+linkedTaskColumns+`
+		FROM task_dependencies d JOIN tasks t ON t.id = d.blocked_by LEFT JOIN projects p ON p.id = t.project_id
+		WHERE d.task_id = ?
+		ORDER BY t.title`, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return scanLinkedTasks(rows)
+}
+
+// GetDependents returns the tasks that taskID directly blocks.
+func GetDependents(db *sql.DB, taskID string) ([]LinkedTask, error) {
+	rows, err := db.Query(`
+		SELECT `+linkedTaskColumns+`
+		FROM task_dependencies d JOIN tasks t ON t.id = d.task_id LEFT JOIN projects p ON p.id = t.project_id
+		WHERE d.blocked_by = ?
+		ORDER BY t.title`, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return scanLinkedTasks(rows)
+}
+
+// GetOpenBlockers returns, for every blocked task of a project, the titles
+// of the tasks blocking it that aren't Done yet.
+func GetOpenBlockers(db *sql.DB, projectID string) (map[string][]string, error) {
+	rows, err := db.Query(`
+		SELECT d.task_id, b.title
+		FROM task_dependencies d
+		JOIN tasks t ON t.id = d.task_id
+		JOIN tasks b ON b.id = d.blocked_by
+		WHERE t.project_id = ? AND b.status != ?
+		ORDER BY b.title`, projectID, DoneStatus)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	blockers := make(map[string][]string)
+	for rows.Next() {
+		var taskID, title string
+		if err := rows.Scan(&taskID, &title); err != nil {
+			return nil, err
+		}
+		blockers[taskID] = append(blockers[taskID], title)
+	}
+	return blockers, rows.Err()
+}
+
+// GetTasksForWorkspace returns every task of a workspace with its project
+// name, ordered by project and title, e.g. to pick a blocking task from.
+func GetTasksForWorkspace(db *sql.DB, workspaceID string) ([]LinkedTask, error) {
+	rows, err := db.Query(`
+		SELECT `+linkedTaskColumns+`
+		FROM tasks t JOIN projects p ON p.id = t.project_id
+		WHERE p.workspace_id = ?
+		ORDER BY p.name, t.title`, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	return scanLinkedTasks(rows)
+}
@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestDependenciesRejectCycles(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	workspace := Workspace{ID: uuid.New().String(), Name: "Work"}
+	if err := CreateWorkspace(db, workspace); err != nil {
+		t.Fatalf("failed to create workspace: %v", err)
+	}
+	api := Project{ID: uuid.New().String(), WorkspaceID: workspace.ID, Name: "API"}
+	web := Project{ID: uuid.New().String(), WorkspaceID: workspace.ID, Name: "Web"}
+	for _, project := range []Project{api, web} {
+		if err := CreateProject(db, project); err != nil {
+			t.Fatalf("failed to create project: %v", err)
+		}
+	}
+
+	schema := Task{ID: uuid.New().String(), ProjectID: api.ID, Title: "Schema", Status: DoneStatus}
+	endpoint := Task{ID: uuid.New().String(), ProjectID: api.ID, Title: "Endpoint", Status: "In Progress"}
+	page := Task{ID: uuid.New().String(), ProjectID: web.ID, Title: "Page", Status: "To Do"}
+	for _, task := range []Task{schema, endpoint, page} {
+		if err := CreateTask(db, task); err != nil {
+			t.Fatalf("failed to create task: %v", err)
+		}
+	}
+
+	// Page is blocked by Endpoint, which is blocked by Schema.
+	if err := AddDependency(db, page.ID, endpoint.ID); err != nil {
+		t.Fatalf("failed to add dependency: %v", err)
+	}
+	if err := AddDependency(db, endpoint.ID, schema.ID); err != nil {
+		t.Fatalf("failed to add dependency: %v", err)
+	}
+	for _, pair := range [][2]string{{schema.ID, page.ID}, {endpoint.ID, page.ID}, {page.ID, page.ID}} {
+		if err := AddDependency(db, pair[0], pair[1]); !errors.Is(err, ErrDependencyCycle) {
+			t.Errorf("expected a cycle error, got %v", err)
+		}
+	}
+
+	blockers, err := GetBlockers(db, page.ID)
+	if err != nil || len(blockers) != 1 || blockers[0].Title != "Endpoint" || blockers[0].ProjectName != "API" {
+		t.Errorf("expected Endpoint from API to block Page, got %+v (%v)", blockers, err)
+	}
+	dependents, err := GetDependents(db, schema.ID)
+	if err != nil || len(dependents) != 1 || dependents[0].ID != endpoint.ID {
+		t.Errorf("expected Schema to block Endpoint, got %+v (%v)", dependents, err)
+	}
+
+	// Schema is Done, so only Page is still blocked.
+	open, err := GetOpenBlockers(db, api.ID)
+	if err != nil || len(open) != 0 {
+		t.Errorf("expected no open blockers in API, got %v (%v)", open, err)
+	}
+	open, err = GetOpenBlockers(db, web.ID)
+	if err != nil || len(open[page.ID]) != 1 || open[page.ID][0] != "Endpoint" {
+		t.Errorf("expected Page to be blocked by Endpoint, got %v (%v)", open, err)
+	}
+
+	if err := DeleteTask(db, endpoint.ID); err != nil {
+		t.Fatalf("failed to delete task: %v", err)
+	}
+	if blockers, _ := GetBlockers(db, page.ID); len(blockers) != 0 {
+		t.Errorf("expected the dependency to go with the deleted task, got %+v", blockers)
+	}
+}
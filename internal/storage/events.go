@@ -0,0 +1,148 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Entity types recorded in the activity log.
+const (
+	EntityWorkspace = "workspace"
+	EntityProject   = "project"
+	EntityTask      = "task"
+	EntityLink      = "link"
+	EntityTweet     = "tweet"
+	EntityNote      = "note"
+	EntityRepo      = "repository"
+	EntityCommand   = "command"
+	EntityLogFile   = "log file"
+	EntityFeed      = "feed"
+	EntityHabit     = "habit"
+	EntitySnippet   = "snippet"
+	EntityJournal   = "journal entry"
+	EntityMilestone = "milestone"
+	EntityChecklist = "checklist item"
+	EntityBlocker   = "blocker"
+	EntityTimeEntry = "time entry"
+	EntityWIPLimit  = "WIP limit"
+)
+
+// Actions recorded in the activity log. ActionMove is used for tasks whose
+// status changed, with OldValue and NewValue holding the two columns. A task
+// moved to another project gets ActionTransferOut in the project it left,
+// with OldValue holding its status and NewValue the project it went to, and
+// ActionTransferIn in the one it joined, with OldValue holding the project it
+// came from and NewValue its status.
+//
+// Events of checklist items, blockers, time entries and WIP limits carry the
+// task or column they belong to as their Summary, see the functions writing
+// them. ActionCheck and ActionUncheck are used for checklist items.
+const (
+	ActionCreate      = "create"
+	ActionUpdate      = "update"
+	ActionDelete      = "delete"
+	ActionMove        = "move"
+	ActionTransferOut = "transfer out"
+	ActionTransferIn  = "transfer in"
+	ActionCheck       = "check"
+	ActionUncheck     = "uncheck"
+)
+
+// timestampLayout is how event times are stored. It sorts lexically and is
+// understood by SQLite's date functions.
+const timestampLayout = "2006-01-02 15:04:05.000"
+
+// Event is an entry in the append-only activity log.
+type Event struct {
+	ID          int64
+	WorkspaceID string
+	ProjectID   string
+	EntityType  string
+	EntityID    string
+	Action      string
+	Summary     string // usually the item's title at the time of the event
+	OldValue    string
+	NewValue    string
+	CreatedAt   time.Time
+}
+
+// formatTimestamp converts t to the layout used in the database.
+func formatTimestamp(t time.Time) string {
+	return t.UTC().Format(timestampLayout)
+}
+
+// parseTimestamp reads a time stored with formatTimestamp.
+func parseTimestamp(s string) time.Time {
+	t, err := time.ParseInLocation(timestampLayout, s, time.UTC)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// execer runs statements, on the database or within a transaction.
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// recordEvent appends to the activity log. When the event has a project but
+// no workspace, the workspace is looked up from the project.
+func recordEvent(db execer, event Event) error {
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	_, err := db.Exec(`
+		INSERT INTO events(workspace_id, project_id, entity_type, entity_id, action, summary, old_value, new_value, created_at)
+		VALUES(COALESCE(NULLIF(?, ''), (SELECT workspace_id FROM projects WHERE id = ?), ''), ?, ?, ?, ?, ?, ?, ?, ?)`,
+		event.WorkspaceID, event.ProjectID, event.ProjectID, event.EntityType, event.EntityID, event.Action,
+		event.Summary, event.OldValue, event.NewValue, formatTimestamp(event.CreatedAt))
+	return err
+}
+
+const eventColumns = "id, workspace_id, project_id, entity_type, entity_id, action, summary, old_value, new_value, created_at"
+
+func scanEvents(rows *sql.Rows) ([]Event, error) {
+	defer rows.Close()
+
+	var events []Event
+	for rows.Next() {
+		var event Event
+		var createdAt string
+		if err := rows.Scan(&event.ID, &event.WorkspaceID, &event.ProjectID, &event.EntityType, &event.EntityID,
+			&event.Action, &event.Summary, &event.OldValue, &event.NewValue, &createdAt); err != nil {
+			return nil, err
+		}
+		event.CreatedAt = parseTimestamp(createdAt)
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// GetEventsForProject returns the most recent events of a project, newest first.
+func GetEventsForProject(db *sql.DB, projectID string, limit int) ([]Event, error) {
+	rows, err := db.Query("SELECT "+eventColumns+" FROM events WHERE project_id = ? ORDER BY created_at DESC, id DESC LIMIT ?", projectID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanEvents(rows)
+}
+
+// GetEventsForWorkspace returns the most recent events of a workspace and
+// all of its projects, newest first.
+func GetEventsForWorkspace(db *sql.DB, workspaceID string, limit int) ([]Event, error) {
+	rows, err := db.Query("SELECT "+eventColumns+" FROM events WHERE workspace_id = ? ORDER BY created_at DESC, id DESC LIMIT ?", workspaceID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanEvents(rows)
+}
+
+// GetEventsForEntity returns the full history of a single item, oldest first.
+func GetEventsForEntity(db *sql.DB, entityType, entityID string) ([]Event, error) {
+	rows, err := db.Query("SELECT "+eventColumns+" FROM events WHERE entity_type = ? AND entity_id = ? ORDER BY created_at, id", entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+	return scanEvents(rows)
+}
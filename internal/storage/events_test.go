@@ -0,0 +1,183 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestTaskHistoryRecordsStatusTransitions(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	workspace := Workspace{ID: uuid.New().String(), Name: "Work"}
+	if err := CreateWorkspace(db, workspace); err != nil {
+		t.Fatalf("failed to create workspace: %v", err)
+	}
+	project := Project{ID: uuid.New().String(), WorkspaceID: workspace.ID, Name: "API"}
+	if err := CreateProject(db, project); err != nil {
+		t.Fatalf("failed to create project: %v", err)
+	}
+
+	task := Task{ID: uuid.New().String(), ProjectID: project.ID, Title: "Ship it", Status: "To Do"}
+	if err := CreateTask(db, task); err != nil {
+		t.Fatalf("failed to create task: %v", err)
+	}
+	task.Status = "In Progress"
+	if err := UpdateTask(db, task); err != nil {
+		t.Fatalf("failed to update task: %v", err)
+	}
+	task.Title = "Ship it today"
+	if err := UpdateTask(db, task); err != nil {
+		t.Fatalf("failed to update task: %v", err)
+	}
+	if err := DeleteTask(db, task.ID); err != nil {
+		t.Fatalf("failed to delete task: %v", err)
+	}
+	// Saving the deleted task again logs nothing.
+	task.Status = "Done"
+	if err := UpdateTask(db, task); err != nil {
+		t.Fatalf("failed to update deleted task: %v", err)
+	}
+
+	history, err := GetEventsForEntity(db, EntityTask, task.ID)
+	if err != nil {
+		t.Fatalf("failed to get task history: %v", err)
+	}
+	wantActions := []string{ActionCreate, ActionMove, ActionUpdate, ActionDelete}
+	if len(history) != len(wantActions) {
+		t.Fatalf("expected %d events, got %d", len(wantActions), len(history))
+	}
+	for i, action := range wantActions {
+		if history[i].Action != action {
+			t.Errorf("event %d: expected action %q, got %q", i, action, history[i].Action)
+		}
+		if history[i].WorkspaceID != workspace.ID {
+			t.Errorf("event %d: expected workspace %q, got %q", i, workspace.ID, history[i].WorkspaceID)
+		}
+		if history[i].CreatedAt.IsZero() {
+			t.Errorf("event %d: missing timestamp", i)
+		}
+	}
+	if history[1].OldValue != "To Do" || history[1].NewValue != "In Progress" {
+		t.Errorf("unexpected move event: %q -> %q", history[1].OldValue, history[1].NewValue)
+	}
+
+	// Workspace feed includes the project and all task events, newest first.
+	feed, err := GetEventsForWorkspace(db, workspace.ID, 100)
+	if err != nil {
+		t.Fatalf("failed to get workspace events: %v", err)
+	}
+	if len(feed) != 6 {
+		t.Fatalf("expected 6 workspace events, got %d", len(feed))
+	}
+	if feed[0].Action != ActionDelete || feed[len(feed)-1].EntityType != EntityWorkspace {
+		t.Errorf("workspace feed is not ordered newest first")
+	}
+
+	if err := DeleteProject(db, project.ID); err != nil {
+		t.Fatalf("failed to delete project: %v", err)
+	}
+	if err := DeleteWorkspace(db, workspace.ID); err != nil {
+		t.Fatalf("failed to delete workspace: %v", err)
+	}
+}
+
+func TestActivityLogRecordsTaskDetails(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	workspace := Workspace{ID: uuid.New().String(), Name: "Work"}
+	if err := CreateWorkspace(db, workspace); err != nil {
+		t.Fatalf("failed to create workspace: %v", err)
+	}
+	project := Project{ID: uuid.New().String(), WorkspaceID: workspace.ID, Name: "API"}
+	if err := CreateProject(db, project); err != nil {
+		t.Fatalf("failed to create project: %v", err)
+	}
+	release := Task{ID: uuid.New().String(), ProjectID: project.ID, Title: "Release", Status: "To Do"}
+	review := Task{ID: uuid.New().String(), ProjectID: project.ID, Title: "Review", Status: "To Do"}
+	for _, task := range []Task{release, review} {
+		if err := CreateTask(db, task); err != nil {
+			t.Fatalf("failed to create task: %v", err)
+		}
+	}
+
+	tag := ChecklistItem{ID: uuid.New().String(), TaskID: release.ID, Text: "Tag"}
+	build := ChecklistItem{ID: uuid.New().String(), TaskID: release.ID, Text: "Build"}
+	for _, item := range []ChecklistItem{tag, build} {
+		if err := CreateChecklistItem(db, item); err != nil {
+			t.Fatalf("failed to create item: %v", err)
+		}
+	}
+	tag.Done = true
+	if err := UpdateChecklistItem(db, tag); err != nil {
+		t.Fatalf("failed to check item: %v", err)
+	}
+	if err := ReorderChecklist(db, []string{build.ID, tag.ID}); err != nil {
+		t.Fatalf("failed to reorder: %v", err)
+	}
+	if err := DeleteChecklistItem(db, build.ID); err != nil {
+		t.Fatalf("failed to delete item: %v", err)
+	}
+
+	// Adding a dependency twice only logs it once.
+	for range 2 {
+		if err := AddDependency(db, release.ID, review.ID); err != nil {
+			t.Fatalf("failed to add dependency: %v", err)
+		}
+	}
+	if err := RemoveDependency(db, release.ID, review.ID); err != nil {
+		t.Fatalf("failed to remove dependency: %v", err)
+	}
+
+	start := time.Date(2024, 3, 5, 9, 0, 0, 0, time.UTC)
+	if err := StartTimeEntry(db, TimeEntry{ID: uuid.New().String(), TaskID: release.ID, StartedAt: start}); err != nil {
+		t.Fatalf("failed to start time entry: %v", err)
+	}
+	if err := StopTimeEntry(db, start.Add(90*time.Minute)); err != nil {
+		t.Fatalf("failed to stop time entry: %v", err)
+	}
+
+	if err := SetWIPLimit(db, project.ID, "In Progress", 3); err != nil {
+		t.Fatalf("failed to set WIP limit: %v", err)
+	}
+	if err := SetWIPStrict(db, project.ID, true); err != nil {
+		t.Fatalf("failed to set strict WIP limits: %v", err)
+	}
+	if err := SetWIPLimit(db, project.ID, "In Progress", 0); err != nil {
+		t.Fatalf("failed to remove WIP limit: %v", err)
+	}
+
+	events, err := GetEventsForProject(db, project.ID, 100)
+	if err != nil {
+		t.Fatalf("failed to get events: %v", err)
+	}
+	want := []struct{ entityType, action, summary, value string }{
+		{EntityWIPLimit, ActionDelete, "In Progress", ""},
+		{EntityWIPLimit, ActionUpdate, "", "strict"},
+		{EntityWIPLimit, ActionUpdate, "In Progress", "3"},
+		{EntityTimeEntry, ActionUpdate, "Release", "1h30m0s"},
+		{EntityTimeEntry, ActionCreate, "Release", ""},
+		{EntityBlocker, ActionDelete, "Release", "Review"},
+		{EntityBlocker, ActionCreate, "Release", "Review"},
+		{EntityChecklist, ActionDelete, "Release", ""},
+		{EntityTask, ActionUpdate, "Release", ""},
+		{EntityChecklist, ActionCheck, "Release", "Tag"},
+		{EntityChecklist, ActionCreate, "Release", "Build"},
+		{EntityChecklist, ActionCreate, "Release", "Tag"},
+	}
+	if len(events) < len(want) {
+		t.Fatalf("expected at least %d events, got %+v", len(want), events)
+	}
+	for i, w := range want {
+		e := events[i]
+		if e.EntityType != w.entityType || e.Action != w.action || e.Summary != w.summary || e.NewValue != w.value {
+			t.Errorf("event %d: expected %+v, got %+v", i, w, e)
+		}
+	}
+	if events[7].OldValue != "Build" {
+		t.Errorf("expected the deleted item's text, got %q", events[7].OldValue)
+	}
+}
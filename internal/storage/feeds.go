@@ -0,0 +1,185 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Feed is an RSS or Atom feed followed by a project. Source is a URL or a
+// local file path. ETag and LastModified are the validators of the last
+// response, sent back to make the next fetch conditional.
+type Feed struct {
+	ID           string
+	ProjectID    string
+	Source       string
+	Title        string
+	ETag         string
+	LastModified string
+	FetchedAt    time.Time
+}
+
+// FeedItem is an entry of a feed. GUID identifies it within the feed across
+// fetches.
+type FeedItem struct {
+	ID          string
+	FeedID      string
+	GUID        string
+	Title       string
+	Link        string
+	PublishedAt time.Time
+	Read        bool
+}
+
+// optionalTimestamp stores the zero time as an empty string.
+func optionalTimestamp(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return formatTimestamp(t)
+}
+
+func GetFeedsForProject(db *sql.DB, projectID string) ([]Feed, error) {
+	rows, err := db.Query("SELECT id, project_id, source, title, etag, last_modified, fetched_at FROM feeds WHERE project_id = ? ORDER BY rowid", projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var feeds []Feed
+	for rows.Next() {
+		var feed Feed
+		var fetchedAt string
+		if err := rows.Scan(&feed.ID, &feed.ProjectID, &feed.Source, &feed.Title, &feed.ETag, &feed.LastModified, &fetchedAt); err != nil {
+			return nil, err
+		}
+		feed.FetchedAt = parseTimestamp(fetchedAt)
+		feeds = append(feeds, feed)
+	}
+	return feeds, rows.Err()
+}
+
+func CreateFeed(db *sql.DB, feed Feed) error {
+	stmt, err := db.Prepare("INSERT INTO feeds(id, project_id, source, title) VALUES(?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(feed.ID, feed.ProjectID, feed.Source, feed.Title)
+	if err != nil {
+		return err
+	}
+	return recordEvent(db, Event{ProjectID: feed.ProjectID, EntityType: EntityFeed, EntityID: feed.ID, Action: ActionCreate, Summary: feed.Source})
+}
+
+// UpdateFeedFetch saves the title and cache validators of a fetched feed.
+// Fetches aren't recorded in the activity log.
+func UpdateFeedFetch(db *sql.DB, feed Feed) error {
+	stmt, err := db.Prepare("UPDATE feeds SET title = ?, etag = ?, last_modified = ?, fetched_at = ? WHERE id = ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(feed.Title, feed.ETag, feed.LastModified, optionalTimestamp(feed.FetchedAt), feed.ID)
+	return err
+}
+
+func DeleteFeed(db *sql.DB, id string) error {
+	var projectID, source sql.NullString
+	err := db.QueryRow("SELECT project_id, source FROM feeds WHERE id = ?", id).Scan(&projectID, &source)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	if _, err := db.Exec("DELETE FROM feed_items WHERE feed_id = ?", id); err != nil {
+		return err
+	}
+	stmt, err := db.Prepare("DELETE FROM feeds WHERE id = ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(id)
+	if err != nil {
+		return err
+	}
+	return recordEvent(db, Event{ProjectID: projectID.String, EntityType: EntityFeed, EntityID: id, Action: ActionDelete, Summary: source.String})
+}
+
+// SaveFeedItems stores the items of a fetch. Items already known by GUID
+// are updated but keep their ID and read state.
+func SaveFeedItems(db *sql.DB, feedID string, items []FeedItem) error {
+	stmt, err := db.Prepare(`
+		INSERT INTO feed_items(id, feed_id, guid, title, link, published_at) VALUES(?, ?, ?, ?, ?, ?)
+		ON CONFLICT(feed_id, guid) DO UPDATE SET title = excluded.title, link = excluded.link, published_at = excluded.published_at`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, item := range items {
+		if _, err := stmt.Exec(item.ID, feedID, item.GUID, item.Title, item.Link, optionalTimestamp(item.PublishedAt)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetFeedItems returns the items of a feed, newest first.
+func GetFeedItems(db *sql.DB, feedID string) ([]FeedItem, error) {
+	rows, err := db.Query("SELECT id, feed_id, guid, title, link, published_at, read FROM feed_items WHERE feed_id = ? ORDER BY published_at DESC, rowid", feedID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []FeedItem
+	for rows.Next() {
+		var item FeedItem
+		var publishedAt string
+		if err := rows.Scan(&item.ID, &item.FeedID, &item.GUID, &item.Title, &item.Link, &publishedAt, &item.Read); err != nil {
+			return nil, err
+		}
+		item.PublishedAt = parseTimestamp(publishedAt)
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func SetFeedItemRead(db *sql.DB, id string, read bool) error {
+	_, err := db.Exec("UPDATE feed_items SET read = ? WHERE id = ?", read, id)
+	return err
+}
+
+// MarkFeedRead marks every item of a feed as read.
+func MarkFeedRead(db *sql.DB, feedID string) error {
+	_, err := db.Exec("UPDATE feed_items SET read = 1 WHERE feed_id = ?", feedID)
+	return err
+}
+
+// GetUnreadCounts returns the number of unread items of each feed of a
+// project, by feed ID.
+func GetUnreadCounts(db *sql.DB, projectID string) (map[string]int, error) {
+	rows, err := db.Query(`
+		SELECT feed_items.feed_id, COUNT(*) FROM feed_items
+		JOIN feeds ON feeds.id = feed_items.feed_id
+		WHERE feeds.project_id = ? AND feed_items.read = 0
+		GROUP BY feed_items.feed_id`, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var feedID string
+		var count int
+		if err := rows.Scan(&feedID, &count); err != nil {
+			return nil, err
+		}
+		counts[feedID] = count
+	}
+	return counts, rows.Err()
+}
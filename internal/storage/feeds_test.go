@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestFeedItemsKeepReadState(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	projectID := uuid.New().String()
+	feed := Feed{ID: uuid.New().String(), ProjectID: projectID, Source: "https://example.com/feed.xml"}
+	if err := CreateFeed(db, feed); err != nil {
+		t.Fatalf("failed to create feed: %v", err)
+	}
+
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	items := []FeedItem{
+		{ID: uuid.New().String(), GUID: "a", Title: "Older", PublishedAt: day},
+		{ID: uuid.New().String(), GUID: "b", Title: "Newer", PublishedAt: day.AddDate(0, 0, 1)},
+	}
+	if err := SaveFeedItems(db, feed.ID, items); err != nil {
+		t.Fatalf("failed to save items: %v", err)
+	}
+	if err := SetFeedItemRead(db, items[0].ID, true); err != nil {
+		t.Fatalf("failed to mark item read: %v", err)
+	}
+
+	// A later fetch returns the same items with new IDs and a changed title.
+	refetched := []FeedItem{
+		{ID: uuid.New().String(), GUID: "a", Title: "Older, edited", PublishedAt: day},
+		{ID: uuid.New().String(), GUID: "c", Title: "Newest", PublishedAt: day.AddDate(0, 0, 2)},
+	}
+	if err := SaveFeedItems(db, feed.ID, refetched); err != nil {
+		t.Fatalf("failed to save items again: %v", err)
+	}
+
+	got, err := GetFeedItems(db, feed.ID)
+	if err != nil {
+		t.Fatalf("failed to get items: %v", err)
+	}
+	if len(got) != 3 || got[0].GUID != "c" || got[2].GUID != "a" {
+		t.Fatalf("expected 3 items newest first, got %+v", got)
+	}
+	if got[2].ID != items[0].ID || !got[2].Read || got[2].Title != "Older, edited" {
+		t.Errorf("expected the known item to keep its ID and read state, got %+v", got[2])
+	}
+
+	counts, err := GetUnreadCounts(db, projectID)
+	if err != nil || counts[feed.ID] != 2 {
+		t.Errorf("expected 2 unread items, got %v (%v)", counts, err)
+	}
+
+	if err := DeleteFeed(db, feed.ID); err != nil {
+		t.Fatalf("failed to delete feed: %v", err)
+	}
+	if got, _ := GetFeedItems(db, feed.ID); len(got) != 0 {
+		t.Errorf("expected the items to be deleted with the feed, got %d", len(got))
+	}
+}
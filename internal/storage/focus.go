@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+)
+
+// FocusSession is a completed work interval of the focus timer, recorded
+// against the task it was attached to.
+type FocusSession struct {
+	ID        string
+	TaskID    string
+	StartedAt time.Time
+	EndedAt   time.Time
+}
+
+func CreateFocusSession(db *sql.DB, session FocusSession) error {
+	stmt, err := db.Prepare("INSERT INTO focus_sessions(id, task_id, started_at, ended_at) VALUES(?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(session.ID, session.TaskID, formatTimestamp(session.StartedAt), formatTimestamp(session.EndedAt))
+	return err
+}
+
+// GetFocusSessionCounts returns the number of completed focus sessions per
+// task of a project, keyed by task ID.
+func GetFocusSessionCounts(db *sql.DB, projectID string) (map[string]int, error) {
+	rows, err := db.Query(`
+		SELECT f.task_id, COUNT(*)
+		FROM focus_sessions f JOIN tasks t ON t.id = f.task_id
+		WHERE t.project_id = ?
+		GROUP BY f.task_id`, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var taskID string
+		var n int
+		if err := rows.Scan(&taskID, &n); err != nil {
+			return nil, err
+		}
+		counts[taskID] = n
+	}
+	return counts, rows.Err()
+}
@@ -0,0 +1,134 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Habit frequencies: a daily habit is due every day, a weekly one once per
+// week.
+const (
+	HabitDaily  = "daily"
+	HabitWeekly = "weekly"
+)
+
+// Habit is a recurring personal goal tracked per workspace.
+type Habit struct {
+	ID          string
+	WorkspaceID string
+	Name        string
+	Frequency   string
+	CreatedAt   time.Time
+}
+
+func GetHabitsForWorkspace(db *sql.DB, workspaceID string) ([]Habit, error) {
+	rows, err := db.Query("SELECT id, workspace_id, name, frequency, created_at FROM habits WHERE workspace_id = ? ORDER BY created_at, rowid", workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var habits []Habit
+	for rows.Next() {
+		var habit Habit
+		var createdAt string
+		if err := rows.Scan(&habit.ID, &habit.WorkspaceID, &habit.Name, &habit.Frequency, &createdAt); err != nil {
+			return nil, err
+		}
+		habit.CreatedAt = parseTimestamp(createdAt)
+		habits = append(habits, habit)
+	}
+	return habits, rows.Err()
+}
+
+func CreateHabit(db *sql.DB, habit Habit) error {
+	stmt, err := db.Prepare("INSERT INTO habits(id, workspace_id, name, frequency, created_at) VALUES(?, ?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(habit.ID, habit.WorkspaceID, habit.Name, habit.Frequency, formatTimestamp(habit.CreatedAt))
+	if err != nil {
+		return err
+	}
+	return recordEvent(db, Event{WorkspaceID: habit.WorkspaceID, EntityType: EntityHabit, EntityID: habit.ID, Action: ActionCreate, Summary: habit.Name, NewValue: habit.Frequency})
+}
+
+func UpdateHabit(db *sql.DB, habit Habit) error {
+	stmt, err := db.Prepare("UPDATE habits SET name = ?, frequency = ? WHERE id = ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(habit.Name, habit.Frequency, habit.ID)
+	if err != nil {
+		return err
+	}
+	return recordEvent(db, Event{WorkspaceID: habit.WorkspaceID, EntityType: EntityHabit, EntityID: habit.ID, Action: ActionUpdate, Summary: habit.Name, NewValue: habit.Frequency})
+}
+
+func DeleteHabit(db *sql.DB, id string) error {
+	var workspaceID, name sql.NullString
+	err := db.QueryRow("SELECT workspace_id, name FROM habits WHERE id = ?", id).Scan(&workspaceID, &name)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	if _, err := db.Exec("DELETE FROM habit_checkins WHERE habit_id = ?", id); err != nil {
+		return err
+	}
+	stmt, err := db.Prepare("DELETE FROM habits WHERE id = ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(id)
+	if err != nil {
+		return err
+	}
+	return recordEvent(db, Event{WorkspaceID: workspaceID.String, EntityType: EntityHabit, EntityID: id, Action: ActionDelete, Summary: name.String})
+}
+
+// ToggleCheckIn checks a habit in on day, or removes the check-in if there
+// already is one. It reports whether the habit is now checked in.
+func ToggleCheckIn(db *sql.DB, habitID string, day time.Time) (bool, error) {
+	date := day.Format(DateLayout)
+	result, err := db.Exec("DELETE FROM habit_checkins WHERE habit_id = ? AND day = ?", habitID, date)
+	if err != nil {
+		return false, err
+	}
+	if removed, err := result.RowsAffected(); err != nil || removed > 0 {
+		return false, err
+	}
+	_, err = db.Exec("INSERT INTO habit_checkins(habit_id, day) VALUES(?, ?)", habitID, date)
+	return err == nil, err
+}
+
+// GetCheckIns returns the days, formatted with DateLayout, on which the
+// habits of a workspace were checked in, by habit ID.
+func GetCheckIns(db *sql.DB, workspaceID string) (map[string]map[string]bool, error) {
+	rows, err := db.Query(`
+		SELECT habit_checkins.habit_id, habit_checkins.day FROM habit_checkins
+		JOIN habits ON habits.id = habit_checkins.habit_id
+		WHERE habits.workspace_id = ?`, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	checkIns := make(map[string]map[string]bool)
+	for rows.Next() {
+		var habitID, day string
+		if err := rows.Scan(&habitID, &day); err != nil {
+			return nil, err
+		}
+		if checkIns[habitID] == nil {
+			checkIns[habitID] = make(map[string]bool)
+		}
+		checkIns[habitID][day] = true
+	}
+	return checkIns, rows.Err()
+}
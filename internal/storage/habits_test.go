@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestHabitCheckIns(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	workspaceID := uuid.New().String()
+	habit := Habit{ID: uuid.New().String(), WorkspaceID: workspaceID, Name: "Stretch", Frequency: HabitDaily, CreatedAt: time.Now()}
+	if err := CreateHabit(db, habit); err != nil {
+		t.Fatalf("failed to create habit: %v", err)
+	}
+
+	day := time.Date(2024, 3, 14, 0, 0, 0, 0, time.Local)
+	for _, d := range []time.Time{day, day.AddDate(0, 0, -1), day} {
+		if _, err := ToggleCheckIn(db, habit.ID, d); err != nil {
+			t.Fatalf("failed to toggle check-in: %v", err)
+		}
+	}
+
+	checkIns, err := GetCheckIns(db, workspaceID)
+	if err != nil {
+		t.Fatalf("failed to get check-ins: %v", err)
+	}
+	if days := checkIns[habit.ID]; len(days) != 1 || !days["2024-03-13"] {
+		t.Errorf("expected only the 13th to be checked in, got %v", days)
+	}
+
+	if err := DeleteHabit(db, habit.ID); err != nil {
+		t.Fatalf("failed to delete habit: %v", err)
+	}
+	if checkIns, _ := GetCheckIns(db, workspaceID); len(checkIns) != 0 {
+		t.Errorf("expected the check-ins to be deleted with the habit, got %v", checkIns)
+	}
+}
@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+)
+
+// JournalEntry is the free-form log of one day in a workspace.
+type JournalEntry struct {
+	WorkspaceID string
+	Day         time.Time
+	Content     string
+	UpdatedAt   time.Time // zero for a day that has no entry yet
+}
+
+// ActivityItem is something that happened on a day, for the journal's
+// summary: a completed task or an added link.
+type ActivityItem struct {
+	Title   string
+	Detail  string // the URL of a link
+	Project string
+	At      time.Time
+}
+
+// DayActivity is what a workspace got done on a day, taken from the
+// activity log.
+type DayActivity struct {
+	CompletedTasks []ActivityItem
+	AddedLinks     []ActivityItem
+}
+
+// GetJournalEntry returns the entry of a workspace for day, or an empty entry
+// when nothing has been written that day.
+func GetJournalEntry(db *sql.DB, workspaceID string, day time.Time) (JournalEntry, error) {
+	entry := JournalEntry{WorkspaceID: workspaceID, Day: day}
+	var updatedAt string
+	err := db.QueryRow("SELECT content, updated_at FROM journal_entries WHERE workspace_id = ? AND day = ?",
+		workspaceID, day.Format(DateLayout)).Scan(&entry.Content, &updatedAt)
+	if err == sql.ErrNoRows {
+		return entry, nil
+	}
+	entry.UpdatedAt = parseTimestamp(updatedAt)
+	return entry, err
+}
+
+// SaveJournalEntry creates or replaces the entry of a day. Saving an empty
+// entry removes it.
+func SaveJournalEntry(db *sql.DB, entry JournalEntry) error {
+	date := entry.Day.Format(DateLayout)
+	if entry.Content == "" {
+		if _, err := db.Exec("DELETE FROM journal_entries WHERE workspace_id = ? AND day = ?", entry.WorkspaceID, date); err != nil {
+			return err
+		}
+		return recordEvent(db, Event{WorkspaceID: entry.WorkspaceID, EntityType: EntityJournal, EntityID: date, Action: ActionDelete, Summary: date})
+	}
+
+	stmt, err := db.Prepare(`
+		INSERT INTO journal_entries(workspace_id, day, content, updated_at) VALUES(?, ?, ?, ?)
+		ON CONFLICT(workspace_id, day) DO UPDATE SET content = excluded.content, updated_at = excluded.updated_at`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(entry.WorkspaceID, date, entry.Content, formatTimestamp(time.Now()))
+	if err != nil {
+		return err
+	}
+	return recordEvent(db, Event{WorkspaceID: entry.WorkspaceID, EntityType: EntityJournal, EntityID: date, Action: ActionUpdate, Summary: date})
+}
+
+// GetJournalDays returns the days, formatted with DateLayout, on which a
+// workspace has a journal entry, oldest first.
+func GetJournalDays(db *sql.DB, workspaceID string) ([]string, error) {
+	rows, err := db.Query("SELECT day FROM journal_entries WHERE workspace_id = ? ORDER BY day", workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var days []string
+	for rows.Next() {
+		var day string
+		if err := rows.Scan(&day); err != nil {
+			return nil, err
+		}
+		days = append(days, day)
+	}
+	return days, rows.Err()
+}
+
+// GetDayActivity collects the tasks moved to Done and the links added in a
+// workspace during the local day that starts at day.
+func GetDayActivity(db *sql.DB, workspaceID string, day time.Time) (DayActivity, error) {
+	rows, err := db.Query(`
+		SELECT e.entity_type, e.entity_id, e.summary, e.new_value, COALESCE(p.name, ''), e.created_at
+		FROM events e LEFT JOIN projects p ON p.id = e.project_id
+		WHERE e.workspace_id = ? AND e.created_at >= ? AND e.created_at < ?
+			AND ((e.entity_type = ? AND e.action IN (?, ?) AND e.new_value = ?)
+				OR (e.entity_type = ? AND e.action = ?))
+		ORDER BY e.created_at, e.id`,
+		workspaceID, formatTimestamp(day), formatTimestamp(day.AddDate(0, 0, 1)),
+		EntityTask, ActionCreate, ActionMove, DoneStatus, EntityLink, ActionCreate)
+	if err != nil {
+		return DayActivity{}, err
+	}
+	defer rows.Close()
+
+	var activity DayActivity
+	completed := make(map[string]bool)
+	for rows.Next() {
+		var entityType, entityID, createdAt string
+		var item ActivityItem
+		if err := rows.Scan(&entityType, &entityID, &item.Title, &item.Detail, &item.Project, &createdAt); err != nil {
+			return DayActivity{}, err
+		}
+		item.At = parseTimestamp(createdAt)
+		if entityType == EntityLink {
+			activity.AddedLinks = append(activity.AddedLinks, item)
+			continue
+		}
+		// A task moved back and forth only counts once.
+		if !completed[entityID] {
+			completed[entityID] = true
+			item.Detail = ""
+			activity.CompletedTasks = append(activity.CompletedTasks, item)
+		}
+	}
+	return activity, rows.Err()
+}
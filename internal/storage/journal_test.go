@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestJournalEntries(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	workspaceID := uuid.New().String()
+	day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.Local)
+	entry, err := GetJournalEntry(db, workspaceID, day)
+	if err != nil || entry.Content != "" || !entry.UpdatedAt.IsZero() {
+		t.Fatalf("expected an empty entry, got %+v (%v)", entry, err)
+	}
+
+	for _, content := range []string{"First draft", "Shipped the parser"} {
+		if err := SaveJournalEntry(db, JournalEntry{WorkspaceID: workspaceID, Day: day, Content: content}); err != nil {
+			t.Fatalf("failed to save entry: %v", err)
+		}
+	}
+	other := day.AddDate(0, 0, -3)
+	if err := SaveJournalEntry(db, JournalEntry{WorkspaceID: workspaceID, Day: other, Content: "Planning"}); err != nil {
+		t.Fatalf("failed to save entry: %v", err)
+	}
+
+	entry, err = GetJournalEntry(db, workspaceID, day)
+	if err != nil || entry.Content != "Shipped the parser" || entry.UpdatedAt.IsZero() {
+		t.Errorf("expected the entry to be replaced, got %+v (%v)", entry, err)
+	}
+	days, err := GetJournalDays(db, workspaceID)
+	if err != nil || len(days) != 2 || days[0] != "2024-03-01" || days[1] != "2024-03-04" {
+		t.Errorf("expected two days oldest first, got %v (%v)", days, err)
+	}
+
+	if err := SaveJournalEntry(db, JournalEntry{WorkspaceID: workspaceID, Day: other}); err != nil {
+		t.Fatalf("failed to clear entry: %v", err)
+	}
+	if days, _ := GetJournalDays(db, workspaceID); len(days) != 1 {
+		t.Errorf("expected an empty entry to be removed, got %v", days)
+	}
+}
+
+func TestDayActivity(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	workspace := Workspace{ID: uuid.New().String(), Name: "Work"}
+	if err := CreateWorkspace(db, workspace); err != nil {
+		t.Fatalf("failed to create workspace: %v", err)
+	}
+	project := Project{ID: uuid.New().String(), WorkspaceID: workspace.ID, Name: "API"}
+	if err := CreateProject(db, project); err != nil {
+		t.Fatalf("failed to create project: %v", err)
+	}
+
+	task := Task{ID: uuid.New().String(), ProjectID: project.ID, Title: "Ship it", Status: "To Do"}
+	open := Task{ID: uuid.New().String(), ProjectID: project.ID, Title: "Still open", Status: "To Do"}
+	for _, task := range []Task{task, open} {
+		if err := CreateTask(db, task); err != nil {
+			t.Fatalf("failed to create task: %v", err)
+		}
+	}
+	// Done, reopened and done again counts once.
+	for _, status := range []string{DoneStatus, "In Progress", DoneStatus} {
+		task.Status = status
+		if err := UpdateTask(db, task); err != nil {
+			t.Fatalf("failed to update task: %v", err)
+		}
+	}
+	if err := CreateLink(db, Link{ID: uuid.New().String(), ProjectID: project.ID, Title: "Docs", URL: "https://example.com"}); err != nil {
+		t.Fatalf("failed to create link: %v", err)
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	activity, err := GetDayActivity(db, workspace.ID, today)
+	if err != nil {
+		t.Fatalf("failed to get activity: %v", err)
+	}
+	if len(activity.CompletedTasks) != 1 || activity.CompletedTasks[0].Title != "Ship it" || activity.CompletedTasks[0].Project != "API" {
+		t.Errorf("expected one completed task, got %+v", activity.CompletedTasks)
+	}
+	if len(activity.AddedLinks) != 1 || activity.AddedLinks[0].Detail != "https://example.com" {
+		t.Errorf("expected one added link, got %+v", activity.AddedLinks)
+	}
+
+	yesterday, err := GetDayActivity(db, workspace.ID, today.AddDate(0, 0, -1))
+	if err != nil || len(yesterday.CompletedTasks)+len(yesterday.AddedLinks) != 0 {
+		t.Errorf("expected nothing yesterday, got %+v (%v)", yesterday, err)
+	}
+}
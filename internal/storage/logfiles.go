@@ -0,0 +1,64 @@
+package storage
+
+import "database/sql"
+
+// LogFile is a local log file followed by the logtail module.
+type LogFile struct {
+	ID        string
+	ProjectID string
+	Path      string
+}
+
+// GetLogFilesForProject returns the log files of a project in the order
+// they were added.
+func GetLogFilesForProject(db *sql.DB, projectID string) ([]LogFile, error) {
+	rows, err := db.Query("SELECT id, project_id, path FROM log_files WHERE project_id = ? ORDER BY rowid", projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var files []LogFile
+	for rows.Next() {
+		var file LogFile
+		if err := rows.Scan(&file.ID, &file.ProjectID, &file.Path); err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, rows.Err()
+}
+
+func CreateLogFile(db *sql.DB, file LogFile) error {
+	stmt, err := db.Prepare("INSERT INTO log_files(id, project_id, path) VALUES(?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(file.ID, file.ProjectID, file.Path)
+	if err != nil {
+		return err
+	}
+	return recordEvent(db, Event{ProjectID: file.ProjectID, EntityType: EntityLogFile, EntityID: file.ID, Action: ActionCreate, Summary: file.Path})
+}
+
+func DeleteLogFile(db *sql.DB, id string) error {
+	var projectID, path sql.NullString
+	err := db.QueryRow("SELECT project_id, path FROM log_files WHERE id = ?", id).Scan(&projectID, &path)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	stmt, err := db.Prepare("DELETE FROM log_files WHERE id = ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(id)
+	if err != nil {
+		return err
+	}
+	return recordEvent(db, Event{ProjectID: projectID.String, EntityType: EntityLogFile, EntityID: id, Action: ActionDelete, Summary: path.String})
+}
@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+)
+
+// TaskTransition is a change of a task's status taken from the activity
+// log. From is empty when the task was created or moved in from another
+// project, and To when it was deleted or moved out to another one.
+type TaskTransition struct {
+	TaskID string
+	From   string
+	To     string
+	At     time.Time
+}
+
+// GetTaskTransitions returns every status change of the tasks of a project,
+// oldest first.
+func GetTaskTransitions(db *sql.DB, projectID string) ([]TaskTransition, error) {
+	rows, err := db.Query(`
+		SELECT entity_id, action, old_value, new_value, created_at FROM events
+		WHERE project_id = ? AND entity_type = ? AND action IN (?, ?, ?, ?, ?)
+		ORDER BY created_at, id`,
+		projectID, EntityTask, ActionCreate, ActionMove, ActionDelete, ActionTransferIn, ActionTransferOut)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transitions []TaskTransition
+	for rows.Next() {
+		var transition TaskTransition
+		var action, oldValue, newValue, at string
+		if err := rows.Scan(&transition.TaskID, &action, &oldValue, &newValue, &at); err != nil {
+			return nil, err
+		}
+		switch action {
+		case ActionCreate, ActionTransferIn:
+			transition.To = newValue
+		case ActionMove:
+			transition.From, transition.To = oldValue, newValue
+		case ActionDelete, ActionTransferOut:
+			transition.From = oldValue
+		}
+		transition.At = parseTimestamp(at)
+		transitions = append(transitions, transition)
+	}
+	return transitions, rows.Err()
+}
@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestGetTaskTransitions(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	projectID := uuid.New().String()
+	task := Task{ID: uuid.New().String(), ProjectID: projectID, Title: "Ship it", Status: "To Do"}
+	if err := CreateTask(db, task); err != nil {
+		t.Fatalf("failed to create task: %v", err)
+	}
+	task.Title = "Ship it today"
+	if err := UpdateTask(db, task); err != nil {
+		t.Fatalf("failed to update task: %v", err)
+	}
+	task.Status = DoneStatus
+	if err := UpdateTask(db, task); err != nil {
+		t.Fatalf("failed to update task: %v", err)
+	}
+	if err := DeleteTask(db, task.ID); err != nil {
+		t.Fatalf("failed to delete task: %v", err)
+	}
+
+	transitions, err := GetTaskTransitions(db, projectID)
+	if err != nil {
+		t.Fatalf("failed to get transitions: %v", err)
+	}
+	want := []struct{ from, to string }{{"", "To Do"}, {"To Do", DoneStatus}, {DoneStatus, ""}}
+	if len(transitions) != len(want) {
+		t.Fatalf("expected %d transitions, got %+v", len(want), transitions)
+	}
+	for i, w := range want {
+		if got := transitions[i]; got.TaskID != task.ID || got.From != w.from || got.To != w.to || got.At.IsZero() {
+			t.Errorf("transition %d: expected %q → %q, got %+v", i, w.from, w.to, got)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Milestone is a named deadline of a project, such as a launch or a demo day.
+type Milestone struct {
+	ID        string
+	ProjectID string
+	Name      string
+	Target    time.Time
+}
+
+// GetMilestonesForProject returns a project's milestones, soonest first.
+func GetMilestonesForProject(db *sql.DB, projectID string) ([]Milestone, error) {
+	rows, err := db.Query("SELECT id, project_id, name, target_at FROM milestones WHERE project_id = ? ORDER BY target_at, rowid", projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var milestones []Milestone
+	for rows.Next() {
+		var milestone Milestone
+		var target string
+		if err := rows.Scan(&milestone.ID, &milestone.ProjectID, &milestone.Name, &target); err != nil {
+			return nil, err
+		}
+		milestone.Target = parseTimestamp(target)
+		milestones = append(milestones, milestone)
+	}
+	return milestones, rows.Err()
+}
+
+func CreateMilestone(db *sql.DB, milestone Milestone) error {
+	stmt, err := db.Prepare("INSERT INTO milestones(id, project_id, name, target_at) VALUES(?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(milestone.ID, milestone.ProjectID, milestone.Name, formatTimestamp(milestone.Target))
+	if err != nil {
+		return err
+	}
+	return recordEvent(db, Event{ProjectID: milestone.ProjectID, EntityType: EntityMilestone, EntityID: milestone.ID, Action: ActionCreate, Summary: milestone.Name, NewValue: milestone.Target.Local().Format(DateLayout)})
+}
+
+func UpdateMilestone(db *sql.DB, milestone Milestone) error {
+	stmt, err := db.Prepare("UPDATE milestones SET name = ?, target_at = ? WHERE id = ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(milestone.Name, formatTimestamp(milestone.Target), milestone.ID)
+	if err != nil {
+		return err
+	}
+	return recordEvent(db, Event{ProjectID: milestone.ProjectID, EntityType: EntityMilestone, EntityID: milestone.ID, Action: ActionUpdate, Summary: milestone.Name, NewValue: milestone.Target.Local().Format(DateLayout)})
+}
+
+func DeleteMilestone(db *sql.DB, id string) error {
+	var projectID, name sql.NullString
+	err := db.QueryRow("SELECT project_id, name FROM milestones WHERE id = ?", id).Scan(&projectID, &name)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	stmt, err := db.Prepare("DELETE FROM milestones WHERE id = ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(id)
+	if err != nil {
+		return err
+	}
+	return recordEvent(db, Event{ProjectID: projectID.String, EntityType: EntityMilestone, EntityID: id, Action: ActionDelete, Summary: name.String})
+}
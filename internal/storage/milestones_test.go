@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestMilestonesSoonestFirst(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	projectID := uuid.New().String()
+	launch := time.Date(2024, 6, 1, 9, 30, 0, 0, time.Local)
+	demo := Milestone{ID: uuid.New().String(), ProjectID: projectID, Name: "Demo day", Target: launch.AddDate(0, 1, 0)}
+	for _, milestone := range []Milestone{demo, {ID: uuid.New().String(), ProjectID: projectID, Name: "Launch", Target: launch}} {
+		if err := CreateMilestone(db, milestone); err != nil {
+			t.Fatalf("failed to create milestone: %v", err)
+		}
+	}
+
+	milestones, err := GetMilestonesForProject(db, projectID)
+	if err != nil || len(milestones) != 2 || milestones[0].Name != "Launch" || !milestones[0].Target.Equal(launch) {
+		t.Fatalf("expected the launch first, got %+v (%v)", milestones, err)
+	}
+
+	demo.Target = launch.AddDate(0, 0, -1)
+	if err := UpdateMilestone(db, demo); err != nil {
+		t.Fatalf("failed to update milestone: %v", err)
+	}
+	if milestones, _ = GetMilestonesForProject(db, projectID); milestones[0].ID != demo.ID {
+		t.Errorf("expected the moved milestone first, got %+v", milestones)
+	}
+
+	if err := DeleteMilestone(db, demo.ID); err != nil {
+		t.Fatalf("failed to delete milestone: %v", err)
+	}
+	if milestones, _ = GetMilestonesForProject(db, projectID); len(milestones) != 1 {
+		t.Errorf("expected 1 milestone, got %d", len(milestones))
+	}
+}
@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Note is a Markdown document attached to a project.
+type Note struct {
+	ID        string
+	ProjectID string
+	Title     string
+	Content   string
+	UpdatedAt time.Time
+}
+
+// GetNotesForProject returns a project's notes, most recently edited first.
+func GetNotesForProject(db *sql.DB, projectID string) ([]Note, error) {
+	rows, err := db.Query("SELECT id, project_id, COALESCE(title, ''), COALESCE(content, ''), COALESCE(updated_at, '') FROM notes WHERE project_id = ? ORDER BY updated_at DESC", projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notes []Note
+	for rows.Next() {
+		var note Note
+		var updatedAt string
+		if err := rows.Scan(&note.ID, &note.ProjectID, &note.Title, &note.Content, &updatedAt); err != nil {
+			return nil, err
+		}
+		note.UpdatedAt = parseTimestamp(updatedAt)
+		notes = append(notes, note)
+	}
+
+	return notes, rows.Err()
+}
+
+func CreateNote(db *sql.DB, note Note) error {
+	stmt, err := db.Prepare("INSERT INTO notes(id, project_id, title, content, updated_at) VALUES(?, ?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(note.ID, note.ProjectID, note.Title, note.Content, formatTimestamp(time.Now()))
+	if err != nil {
+		return err
+	}
+	return recordEvent(db, Event{ProjectID: note.ProjectID, EntityType: EntityNote, EntityID: note.ID, Action: ActionCreate, Summary: note.Title})
+}
+
+func UpdateNote(db *sql.DB, note Note) error {
+	stmt, err := db.Prepare("UPDATE notes SET title = ?, content = ?, updated_at = ? WHERE id = ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(note.Title, note.Content, formatTimestamp(time.Now()), note.ID)
+	if err != nil {
+		return err
+	}
+	return recordEvent(db, Event{ProjectID: note.ProjectID, EntityType: EntityNote, EntityID: note.ID, Action: ActionUpdate, Summary: note.Title})
+}
+
+func DeleteNote(db *sql.DB, id string) error {
+	var projectID, title sql.NullString
+	err := db.QueryRow("SELECT project_id, title FROM notes WHERE id = ?", id).Scan(&projectID, &title)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	stmt, err := db.Prepare("DELETE FROM notes WHERE id = ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(id)
+	if err != nil {
+		return err
+	}
+	return recordEvent(db, Event{ProjectID: projectID.String, EntityType: EntityNote, EntityID: id, Action: ActionDelete, Summary: title.String})
+}
@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+)
+
+// ProjectSummary holds the figures shown for a project on the workspace overview.
+type ProjectSummary struct {
+	Project      Project
+	TaskCounts   map[string]int // keyed by task status
+	OverdueTasks int
+	Links        int
+	Drafts       int
+	LastActivity time.Time // zero when nothing has been recorded
+}
+
+// GetProjectSummaries returns a summary for every project in a workspace, in
+// the same order as GetAllProjectsForWorkspace.
+func GetProjectSummaries(db *sql.DB, workspaceID string, today time.Time) ([]ProjectSummary, error) {
+	projects, err := GetAllProjectsForWorkspace(db, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]ProjectSummary, len(projects))
+	byID := make(map[string]*ProjectSummary, len(projects))
+	for i, p := range projects {
+		summaries[i] = ProjectSummary{Project: p, TaskCounts: make(map[string]int)}
+		byID[p.ID] = &summaries[i]
+	}
+
+	rows, err := db.Query(`
+		SELECT t.project_id, COALESCE(t.status, ''), COUNT(*),
+			SUM(CASE WHEN COALESCE(t.due_date, '') != '' AND t.due_date < ? AND t.status != ? THEN 1 ELSE 0 END)
+		FROM tasks t JOIN projects p ON p.id = t.project_id
+		WHERE p.workspace_id = ?
+		GROUP BY t.project_id, t.status`, today.Format(DateLayout), DoneStatus, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var projectID, status string
+		var count, overdue int
+		if err := rows.Scan(&projectID, &status, &count, &overdue); err != nil {
+			return nil, err
+		}
+		if s, ok := byID[projectID]; ok {
+			s.TaskCounts[status] += count
+			s.OverdueTasks += overdue
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	counts := []struct {
+		query string
+		set   func(*ProjectSummary, int)
+	}{
+		{"SELECT l.project_id, COUNT(*) FROM links l JOIN projects p ON p.id = l.project_id WHERE p.workspace_id = ? GROUP BY l.project_id",
+			func(s *ProjectSummary, n int) { s.Links = n }},
+		{"SELECT t.project_id, COUNT(*) FROM tweets t JOIN projects p ON p.id = t.project_id WHERE p.workspace_id = ? GROUP BY t.project_id",
+			func(s *ProjectSummary, n int) { s.Drafts = n }},
+	}
+	for _, c := range counts {
+		if err := scanProjectCounts(db, c.query, workspaceID, func(projectID string, n int) {
+			if s, ok := byID[projectID]; ok {
+				c.set(s, n)
+			}
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	activity, err := db.Query("SELECT project_id, MAX(created_at) FROM events WHERE workspace_id = ? AND project_id != '' GROUP BY project_id", workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer activity.Close()
+	for activity.Next() {
+		var projectID, last string
+		if err := activity.Scan(&projectID, &last); err != nil {
+			return nil, err
+		}
+		if s, ok := byID[projectID]; ok {
+			s.LastActivity = parseTimestamp(last)
+		}
+	}
+
+	return summaries, activity.Err()
+}
+
+func scanProjectCounts(db *sql.DB, query, workspaceID string, set func(projectID string, n int)) error {
+	rows, err := db.Query(query, workspaceID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var projectID string
+		var n int
+		if err := rows.Scan(&projectID, &n); err != nil {
+			return err
+		}
+		set(projectID, n)
+	}
+	return rows.Err()
+}
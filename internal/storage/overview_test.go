@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestGetProjectSummaries(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	workspace := Workspace{ID: uuid.New().String(), Name: "Work"}
+	if err := CreateWorkspace(db, workspace); err != nil {
+		t.Fatalf("failed to create workspace: %v", err)
+	}
+	busy := Project{ID: uuid.New().String(), WorkspaceID: workspace.ID, Name: "Busy", Status: "active"}
+	idle := Project{ID: uuid.New().String(), WorkspaceID: workspace.ID, Name: "Idle"}
+	for _, p := range []Project{busy, idle} {
+		if err := CreateProject(db, p); err != nil {
+			t.Fatalf("failed to create project: %v", err)
+		}
+	}
+
+	today := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	tasks := []Task{
+		{Title: "late", Status: "To Do", DueDate: "2026-03-09"},
+		{Title: "late but done", Status: DoneStatus, DueDate: "2026-03-01"},
+		{Title: "due today", Status: "In Progress", DueDate: "2026-03-10"},
+		{Title: "no date", Status: "To Do"},
+	}
+	for _, task := range tasks {
+		task.ID = uuid.New().String()
+		task.ProjectID = busy.ID
+		if err := CreateTask(db, task); err != nil {
+			t.Fatalf("failed to create task: %v", err)
+		}
+	}
+	if err := CreateLink(db, Link{ID: uuid.New().String(), ProjectID: busy.ID, Title: "docs", URL: "https://example.com"}); err != nil {
+		t.Fatalf("failed to create link: %v", err)
+	}
+	if err := CreateTweet(db, Tweet{ID: uuid.New().String(), ProjectID: busy.ID, Content: "hello"}); err != nil {
+		t.Fatalf("failed to create tweet: %v", err)
+	}
+
+	summaries, err := GetProjectSummaries(db, workspace.ID, today)
+	if err != nil {
+		t.Fatalf("failed to get summaries: %v", err)
+	}
+	if len(summaries) != 2 {
+		t.Fatalf("expected 2 summaries, got %d", len(summaries))
+	}
+
+	var got ProjectSummary
+	for _, s := range summaries {
+		if s.Project.ID == busy.ID {
+			got = s
+		} else if s.Links != 0 || s.Drafts != 0 || len(s.TaskCounts) != 0 {
+			t.Errorf("idle project should be empty, got %+v", s)
+		}
+	}
+	if got.TaskCounts["To Do"] != 2 || got.TaskCounts["In Progress"] != 1 || got.TaskCounts[DoneStatus] != 1 {
+		t.Errorf("unexpected task counts: %v", got.TaskCounts)
+	}
+	if got.OverdueTasks != 1 {
+		t.Errorf("expected 1 overdue task, got %d", got.OverdueTasks)
+	}
+	if got.Links != 1 || got.Drafts != 1 {
+		t.Errorf("expected 1 link and 1 draft, got %d and %d", got.Links, got.Drafts)
+	}
+	if got.LastActivity.IsZero() {
+		t.Error("expected last activity to be set")
+	}
+
+	for _, p := range []Project{busy, idle} {
+		if err := DeleteProject(db, p.ID); err != nil {
+			t.Fatalf("failed to delete project: %v", err)
+		}
+	}
+	if err := DeleteWorkspace(db, workspace.ID); err != nil {
+		t.Fatalf("failed to delete workspace: %v", err)
+	}
+}
@@ -0,0 +1,305 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Recurrence frequencies, named as in RFC 5545.
+const (
+	FreqDaily   = "DAILY"
+	FreqWeekly  = "WEEKLY"
+	FreqMonthly = "MONTHLY"
+)
+
+var weekdayCodes = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// Recurrence is the schedule of a recurring task, a subset of an iCalendar
+// RRULE: a frequency, an interval and, for weekly and monthly rules, the
+// weekdays or the day of the month.
+type Recurrence struct {
+	Freq     string
+	Interval int            // repeat every Interval days, weeks or months
+	Weekdays []time.Weekday // weekly only, empty means the weekday of the first occurrence
+	MonthDay int            // monthly only, 0 means the day of the first occurrence
+}
+
+// ParseRecurrence reads a rule written as "daily", "weekly", "weekly mon,thu",
+// "monthly", "monthly 15", or an RRULE using FREQ, INTERVAL, BYDAY and
+// BYMONTHDAY such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH".
+func ParseRecurrence(s string) (Recurrence, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if strings.Contains(s, "=") {
+		return parseRRule(s)
+	}
+
+	fields := strings.Fields(strings.ToLower(s))
+	if len(fields) == 0 || len(fields) > 2 {
+		return Recurrence{}, fmt.Errorf("can't read %q as a schedule", s)
+	}
+	rule := Recurrence{Interval: 1}
+	switch fields[0] {
+	case "daily":
+		rule.Freq = FreqDaily
+	case "weekly":
+		rule.Freq = FreqWeekly
+	case "monthly":
+		rule.Freq = FreqMonthly
+	default:
+		return Recurrence{}, fmt.Errorf("can't read %q as a schedule, use daily, weekly or monthly", s)
+	}
+	if len(fields) == 1 {
+		return rule, nil
+	}
+
+	var err error
+	switch rule.Freq {
+	case FreqWeekly:
+		rule.Weekdays, err = parseWeekdays(fields[1])
+	case FreqMonthly:
+		rule.MonthDay, err = parseMonthDay(fields[1])
+	default:
+		err = fmt.Errorf("daily schedules take no days")
+	}
+	return rule, err
+}
+
+func parseRRule(s string) (Recurrence, error) {
+	rule := Recurrence{Interval: 1}
+	for _, part := range strings.Split(strings.ToUpper(s), ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return Recurrence{}, fmt.Errorf("can't read %q in the rule", part)
+		}
+		var err error
+		switch key {
+		case "FREQ":
+			rule.Freq = value
+		case "INTERVAL":
+			rule.Interval, err = strconv.Atoi(value)
+			if err == nil && rule.Interval < 1 {
+				err = fmt.Errorf("the interval must be at least 1")
+			}
+		case "BYDAY":
+			rule.Weekdays, err = parseWeekdays(value)
+		case "BYMONTHDAY":
+			rule.MonthDay, err = parseMonthDay(value)
+		default:
+			err = fmt.Errorf("%s isn't supported in rules", key)
+		}
+		if err != nil {
+			return Recurrence{}, err
+		}
+	}
+
+	switch rule.Freq {
+	case FreqDaily, FreqWeekly, FreqMonthly:
+	default:
+		return Recurrence{}, fmt.Errorf("the rule needs FREQ=DAILY, WEEKLY or MONTHLY")
+	}
+	if len(rule.Weekdays) > 0 && rule.Freq != FreqWeekly {
+		return Recurrence{}, fmt.Errorf("BYDAY is only supported with FREQ=WEEKLY")
+	}
+	if rule.MonthDay != 0 && rule.Freq != FreqMonthly {
+		return Recurrence{}, fmt.Errorf("BYMONTHDAY is only supported with FREQ=MONTHLY")
+	}
+	return rule, nil
+}
+
+// parseWeekdays reads a comma-separated list of weekdays, either as RRULE
+// codes ("MO") or English names ("mon", "monday").
+func parseWeekdays(s string) ([]time.Weekday, error) {
+	var days []time.Weekday
+	for _, name := range strings.Split(strings.ToLower(s), ",") {
+		found := false
+		for day := time.Sunday; day <= time.Saturday; day++ {
+			full := strings.ToLower(day.String())
+			if name == strings.ToLower(weekdayCodes[day]) || name == full[:3] || name == full {
+				days = append(days, day)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%q isn't a weekday", name)
+		}
+	}
+	return days, nil
+}
+
+func parseMonthDay(s string) (int, error) {
+	day, err := strconv.Atoi(s)
+	if err != nil || day < 1 || day > 31 {
+		return 0, fmt.Errorf("%q isn't a day of the month", s)
+	}
+	return day, nil
+}
+
+// String formats the rule as an RRULE, the form stored in Task.Recurrence.
+func (r Recurrence) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, fmt.Sprintf("INTERVAL=%d", r.Interval))
+	}
+	if len(r.Weekdays) > 0 {
+		codes := make([]string, len(r.Weekdays))
+		for i, day := range r.Weekdays {
+			codes[i] = weekdayCodes[day]
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	if r.MonthDay != 0 {
+		parts = append(parts, fmt.Sprintf("BYMONTHDAY=%d", r.MonthDay))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Describe explains the rule in words, e.g. "every 2 weeks on Mon, Thu".
+func (r Recurrence) Describe() string {
+	unit := map[string]string{FreqDaily: "day", FreqWeekly: "week", FreqMonthly: "month"}[r.Freq]
+	s := "every " + unit
+	if r.Interval > 1 {
+		s = fmt.Sprintf("every %d %ss", r.Interval, unit)
+	}
+	if len(r.Weekdays) > 0 {
+		names := make([]string, len(r.Weekdays))
+		for i, day := range r.Weekdays {
+			names[i] = day.String()[:3]
+		}
+		s += " on " + strings.Join(names, ", ")
+	}
+	if r.MonthDay != 0 {
+		s += fmt.Sprintf(" on day %d", r.MonthDay)
+	}
+	return s
+}
+
+// Anchor fills in what the rule leaves to its first occurrence: the weekday
+// of a weekly rule and the day of a monthly one.
+func (r Recurrence) Anchor(first time.Time) Recurrence {
+	if r.Freq == FreqWeekly && len(r.Weekdays) == 0 {
+		r.Weekdays = []time.Weekday{first.Weekday()}
+	}
+	if r.Freq == FreqMonthly && r.MonthDay == 0 {
+		r.MonthDay = first.Day()
+	}
+	return r
+}
+
+// First returns the first occurrence on or after day.
+func (r Recurrence) First(day time.Time) time.Time {
+	day = dateOnly(day)
+	for !r.matches(day) {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day
+}
+
+// Next returns the first occurrence after day, counting the interval from
+// day. Monthly rules on a day the month doesn't have fall on its last day.
+func (r Recurrence) Next(day time.Time) time.Time {
+	day = dateOnly(day)
+	interval := max(r.Interval, 1)
+	switch r.Freq {
+	case FreqWeekly:
+		if len(r.Weekdays) == 0 {
+			return day.AddDate(0, 0, 7*interval)
+		}
+		// The rest of this week, then the weeks the interval skips to.
+		for next := day.AddDate(0, 0, 1); next.Weekday() != time.Monday; next = next.AddDate(0, 0, 1) {
+			if r.matches(next) {
+				return next
+			}
+		}
+		sinceMonday := (int(day.Weekday()) + 6) % 7
+		for next := day.AddDate(0, 0, 7*interval-sinceMonday); ; next = next.AddDate(0, 0, 1) {
+			if r.matches(next) {
+				return next
+			}
+		}
+	case FreqMonthly:
+		monthDay := r.MonthDay
+		if monthDay == 0 {
+			monthDay = day.Day()
+		}
+		if day.Day() < monthDay && clampDay(day.Year(), day.Month(), monthDay) > day.Day() {
+			return time.Date(day.Year(), day.Month(), clampDay(day.Year(), day.Month(), monthDay), 0, 0, 0, 0, time.UTC)
+		}
+		month := time.Date(day.Year(), day.Month()+time.Month(interval), 1, 0, 0, 0, 0, time.UTC)
+		return time.Date(month.Year(), month.Month(), clampDay(month.Year(), month.Month(), monthDay), 0, 0, 0, 0, time.UTC)
+	}
+	return day.AddDate(0, 0, interval)
+}
+
+func (r Recurrence) matches(day time.Time) bool {
+	switch r.Freq {
+	case FreqWeekly:
+		if len(r.Weekdays) == 0 {
+			return true
+		}
+		for _, weekday := range r.Weekdays {
+			if day.Weekday() == weekday {
+				return true
+			}
+		}
+		return false
+	case FreqMonthly:
+		return r.MonthDay == 0 || day.Day() == clampDay(day.Year(), day.Month(), r.MonthDay)
+	}
+	return true
+}
+
+// clampDay limits day to the length of the month.
+func clampDay(year int, month time.Month, day int) int {
+	return min(day, time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day())
+}
+
+// dateOnly drops the time of day, keeping the calendar date.
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// GetRecurringTasks returns the templates of all recurring tasks, the tasks
+// with a schedule.
+func GetRecurringTasks(db *sql.DB) ([]Task, error) {
+	rows, err := db.Query("SELECT " + taskColumns + " FROM tasks WHERE COALESCE(recurrence, '') != ''")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []Task
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, rows.Err()
+}
+
+// CountOpenOccurrences counts the unfinished tasks of a recurring series:
+// the template and the tasks created from it.
+func CountOpenOccurrences(db *sql.DB, templateID string) (int, error) {
+	var count int
+	err := db.QueryRow("SELECT COUNT(*) FROM tasks WHERE (id = ? OR template_id = ?) AND status != ?",
+		templateID, templateID, DoneStatus).Scan(&count)
+	return count, err
+}
+
+// SetNextRun records when the next occurrence of a recurring task is due.
+// It isn't recorded in the activity log.
+func SetNextRun(db *sql.DB, templateID, nextRun string) error {
+	stmt, err := db.Prepare("UPDATE tasks SET next_run = ? WHERE id = ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(nextRun, templateID)
+	return err
+}
@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRecurrence(t *testing.T) {
+	tests := []struct {
+		input, rrule, describe string
+	}{
+		{"daily", "FREQ=DAILY", "every day"},
+		{"weekly mon,Thursday", "FREQ=WEEKLY;BYDAY=MO,TH", "every week on Mon, Thu"},
+		{"monthly 15", "FREQ=MONTHLY;BYMONTHDAY=15", "every month on day 15"},
+		{"RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=FR", "FREQ=WEEKLY;INTERVAL=2;BYDAY=FR", "every 2 weeks on Fri"},
+	}
+	for _, tt := range tests {
+		rule, err := ParseRecurrence(tt.input)
+		if err != nil {
+			t.Errorf("ParseRecurrence(%q) failed: %v", tt.input, err)
+			continue
+		}
+		if rule.String() != tt.rrule || rule.Describe() != tt.describe {
+			t.Errorf("ParseRecurrence(%q) = %q (%s), want %q (%s)", tt.input, rule, rule.Describe(), tt.rrule, tt.describe)
+		}
+	}
+
+	for _, input := range []string{"", "yearly", "weekly funday", "FREQ=DAILY;BYDAY=MO", "FREQ=WEEKLY;COUNT=3", "monthly 32"} {
+		if _, err := ParseRecurrence(input); err == nil {
+			t.Errorf("expected ParseRecurrence(%q) to fail", input)
+		}
+	}
+}
+
+func TestRecurrenceNext(t *testing.T) {
+	date := func(s string) time.Time {
+		d, _ := time.Parse(DateLayout, s)
+		return d
+	}
+	tests := []struct {
+		rule      string
+		from      string
+		first     string
+		following string
+	}{
+		{"daily", "2024-02-28", "2024-02-28", "2024-02-29"},
+		{"FREQ=DAILY;INTERVAL=3", "2024-02-28", "2024-02-28", "2024-03-02"},
+		// 2024-05-01 is a Wednesday.
+		{"weekly mon,thu", "2024-05-01", "2024-05-02", "2024-05-06"},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO", "2024-05-01", "2024-05-06", "2024-05-20"},
+		{"monthly 31", "2024-01-31", "2024-01-31", "2024-02-29"},
+		{"monthly 31", "2024-02-29", "2024-02-29", "2024-03-31"},
+	}
+	for _, tt := range tests {
+		rule, err := ParseRecurrence(tt.rule)
+		if err != nil {
+			t.Fatalf("ParseRecurrence(%q) failed: %v", tt.rule, err)
+		}
+		first := rule.First(date(tt.from))
+		if got := first.Format(DateLayout); got != tt.first {
+			t.Errorf("%s: first from %s = %s, want %s", tt.rule, tt.from, got, tt.first)
+		}
+		if got := rule.Next(first).Format(DateLayout); got != tt.following {
+			t.Errorf("%s: next after %s = %s, want %s", tt.rule, tt.first, got, tt.following)
+		}
+	}
+}
+
+func TestUpdateTaskKeepsSchedulerFields(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	template := Task{ID: "template", ProjectID: "project", Title: "Report", Status: "To Do", Recurrence: "FREQ=WEEKLY", NextRun: "2024-05-06"}
+	occurrence := Task{ID: "occurrence", ProjectID: "project", Title: "Report", Status: "To Do", TemplateID: template.ID}
+	for _, task := range []Task{template, occurrence} {
+		if err := CreateTask(db, task); err != nil {
+			t.Fatalf("failed to create task: %v", err)
+		}
+	}
+	if err := SetNextRun(db, template.ID, "2024-05-13"); err != nil {
+		t.Fatalf("failed to set next run: %v", err)
+	}
+
+	// Saving copies loaded before the scheduler ran must not roll it back.
+	template.AutoDone = true
+	occurrence.TemplateID = ""
+	for _, task := range []Task{template, occurrence} {
+		if err := UpdateTask(db, task); err != nil {
+			t.Fatalf("failed to update task: %v", err)
+		}
+	}
+	if saved, err := GetTask(db, template.ID); err != nil || saved.NextRun != "2024-05-13" || !saved.AutoDone {
+		t.Errorf("expected the next run to be kept, got %+v (%v)", saved, err)
+	}
+	if saved, err := GetTask(db, occurrence.ID); err != nil || saved.TemplateID != template.ID {
+		t.Errorf("expected the occurrence to stay in its series, got %+v (%v)", saved, err)
+	}
+}
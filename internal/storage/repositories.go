@@ -0,0 +1,64 @@
+package storage
+
+import "database/sql"
+
+// Repository is a local git repository associated with a project.
+type Repository struct {
+	ID        string
+	ProjectID string
+	Path      string
+}
+
+// GetRepositoriesForProject returns a project's repositories in the order
+// they were added.
+func GetRepositoriesForProject(db *sql.DB, projectID string) ([]Repository, error) {
+	rows, err := db.Query("SELECT id, project_id, path FROM repositories WHERE project_id = ? ORDER BY rowid", projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var repos []Repository
+	for rows.Next() {
+		var repo Repository
+		if err := rows.Scan(&repo.ID, &repo.ProjectID, &repo.Path); err != nil {
+			return nil, err
+		}
+		repos = append(repos, repo)
+	}
+	return repos, rows.Err()
+}
+
+func CreateRepository(db *sql.DB, repo Repository) error {
+	stmt, err := db.Prepare("INSERT INTO repositories(id, project_id, path) VALUES(?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(repo.ID, repo.ProjectID, repo.Path)
+	if err != nil {
+		return err
+	}
+	return recordEvent(db, Event{ProjectID: repo.ProjectID, EntityType: EntityRepo, EntityID: repo.ID, Action: ActionCreate, Summary: repo.Path})
+}
+
+func DeleteRepository(db *sql.DB, id string) error {
+	var projectID, path sql.NullString
+	err := db.QueryRow("SELECT project_id, path FROM repositories WHERE id = ?", id).Scan(&projectID, &path)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	stmt, err := db.Prepare("DELETE FROM repositories WHERE id = ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(id)
+	if err != nil {
+		return err
+	}
+	return recordEvent(db, Event{ProjectID: projectID.String, EntityType: EntityRepo, EntityID: id, Action: ActionDelete, Summary: path.String})
+}
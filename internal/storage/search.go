@@ -0,0 +1,280 @@
+package storage
+
+import (
+	"database/sql"
+	"log"
+	"sort"
+	"strings"
+)
+
+// Kinds of items that can show up in search results.
+const (
+	SearchKindTask  = "task"
+	SearchKindLink  = "link"
+	SearchKindTweet = "tweet"
+)
+
+// SearchResult is a single match returned by Search, along with enough
+// context to jump to the item's workspace and project.
+type SearchResult struct {
+	Kind          string
+	ItemID        string
+	ProjectID     string
+	ProjectName   string
+	WorkspaceID   string
+	WorkspaceName string
+	Title         string
+	Body          string
+	Rank          float64 // lower is better
+}
+
+// searchIndexSchema creates the FTS5 index together with the triggers that
+// keep it in sync with the tasks, links and tweets tables.
+const searchIndexSchema = `
+CREATE VIRTUAL TABLE IF NOT EXISTS search_index USING fts5(
+	kind UNINDEXED,
+	item_id UNINDEXED,
+	project_id UNINDEXED,
+	title,
+	body,
+	tokenize = 'unicode61'
+);
+
+CREATE TRIGGER IF NOT EXISTS tasks_search_insert AFTER INSERT ON tasks BEGIN
+	INSERT INTO search_index(kind, item_id, project_id, title, body)
+	VALUES ('task', new.id, new.project_id, new.title, COALESCE(new.description, ''));
+END;
+CREATE TRIGGER IF NOT EXISTS tasks_search_update AFTER UPDATE ON tasks BEGIN
+	DELETE FROM search_index WHERE kind = 'task' AND item_id = old.id;
+	INSERT INTO search_index(kind, item_id, project_id, title, body)
+	VALUES ('task', new.id, new.project_id, new.title, COALESCE(new.description, ''));
+END;
+CREATE TRIGGER IF NOT EXISTS tasks_search_delete AFTER DELETE ON tasks BEGIN
+	DELETE FROM search_index WHERE kind = 'task' AND item_id = old.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS links_search_insert AFTER INSERT ON links BEGIN
+	INSERT INTO search_index(kind, item_id, project_id, title, body)
+	VALUES ('link', new.id, new.project_id, new.title, new.url);
+END;
+CREATE TRIGGER IF NOT EXISTS links_search_update AFTER UPDATE ON links BEGIN
+	DELETE FROM search_index WHERE kind = 'link' AND item_id = old.id;
+	INSERT INTO search_index(kind, item_id, project_id, title, body)
+	VALUES ('link', new.id, new.project_id, new.title, new.url);
+END;
+CREATE TRIGGER IF NOT EXISTS links_search_delete AFTER DELETE ON links BEGIN
+	DELETE FROM search_index WHERE kind = 'link' AND item_id = old.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS tweets_search_insert AFTER INSERT ON tweets BEGIN
+	INSERT INTO search_index(kind, item_id, project_id, title, body)
+	VALUES ('tweet', new.id, new.project_id, '', new.content);
+END;
+CREATE TRIGGER IF NOT EXISTS tweets_search_update AFTER UPDATE ON tweets BEGIN
+	DELETE FROM search_index WHERE kind = 'tweet' AND item_id = old.id;
+	INSERT INTO search_index(kind, item_id, project_id, title, body)
+	VALUES ('tweet', new.id, new.project_id, '', new.content);
+END;
+CREATE TRIGGER IF NOT EXISTS tweets_search_delete AFTER DELETE ON tweets BEGIN
+	DELETE FROM search_index WHERE kind = 'tweet' AND item_id = old.id;
+END;
+`
+
+// searchTriggers are the triggers of searchIndexSchema. They are dropped when
+// the database is opened by a build without FTS5, which can't run them.
+var searchTriggers = []string{
+	"tasks_search_insert", "tasks_search_update", "tasks_search_delete",
+	"links_search_insert", "links_search_update", "links_search_delete",
+	"tweets_search_insert", "tweets_search_update", "tweets_search_delete",
+}
+
+// rebuildSearchIndexStmt fills the index from scratch. It is needed when the
+// index is created against a database that already holds data, or when a
+// build without FTS5 changed the data while the triggers were dropped.
+const rebuildSearchIndexStmt = `
+DELETE FROM search_index;
+INSERT INTO search_index(kind, item_id, project_id, title, body)
+	SELECT 'task', id, project_id, title, COALESCE(description, '') FROM tasks;
+INSERT INTO search_index(kind, item_id, project_id, title, body)
+	SELECT 'link', id, project_id, title, url FROM links;
+INSERT INTO search_index(kind, item_id, project_id, title, body)
+	SELECT 'tweet', id, project_id, '', content FROM tweets;
+`
+
+// initSearchIndex sets up the full-text index. SQLite builds without FTS5
+// (go-sqlite3 needs the sqlite_fts5 build tag) are detected here, in which
+// case the triggers of an index left by a build with FTS5 are dropped, as
+// every write to the indexed tables would fail on them, and Search falls back
+// to plain LIKE queries.
+func initSearchIndex(db *sql.DB) error {
+	available, err := fts5Available(db)
+	if err != nil {
+		return err
+	}
+	if !available {
+		log.Println("FTS5 is not available, global search will use LIKE queries")
+		for _, trigger := range searchTriggers {
+			if _, err := db.Exec("DROP TRIGGER IF EXISTS " + trigger); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	exists, err := hasSearchIndex(db)
+	if err != nil {
+		return err
+	}
+	if _, err := db.Exec(searchIndexSchema); err != nil {
+		return err
+	}
+
+	if !exists {
+		log.Println("Running migration: building search index")
+		if _, err := db.Exec(rebuildSearchIndexStmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fts5Available reports whether the SQLite library was built with FTS5.
+func fts5Available(db *sql.DB) (bool, error) {
+	var used bool
+	err := db.QueryRow("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&used)
+	return used, err
+}
+
+// hasSearchIndex reports whether the index exists and is kept up to date,
+// which it isn't once a build without FTS5 dropped its triggers.
+func hasSearchIndex(db *sql.DB) (bool, error) {
+	var tables, triggers int
+	err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'search_index'").Scan(&tables)
+	if err != nil {
+		return false, err
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(searchTriggers)), ", ")
+	args := make([]any, len(searchTriggers))
+	for i, trigger := range searchTriggers {
+		args[i] = trigger
+	}
+	err = db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'trigger' AND name IN ("+placeholders+")", args...).Scan(&triggers)
+	return tables > 0 && triggers == len(searchTriggers), err
+}
+
+// Search looks for query in task titles and descriptions, link titles and
+// URLs, and tweet drafts across every workspace. Results are ordered by
+// relevance, best match first.
+func Search(db *sql.DB, query string, limit int) ([]SearchResult, error) {
+	terms := strings.Fields(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	indexed, err := hasSearchIndex(db)
+	if err != nil {
+		return nil, err
+	}
+	if indexed {
+		return searchFTS(db, terms, limit)
+	}
+	return searchLike(db, terms, limit)
+}
+
+// ftsQuery turns free text into an FTS5 query where every term is quoted
+// (so punctuation can't break the syntax) and matched as a prefix.
+func ftsQuery(terms []string) string {
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = `"` + strings.ReplaceAll(term, `"`, `""`) + `"*`
+	}
+	return strings.Join(quoted, " ")
+}
+
+func searchFTS(db *sql.DB, terms []string, limit int) ([]SearchResult, error) {
+	rows, err := db.Query(`
+		SELECT s.kind, s.item_id, s.project_id, COALESCE(p.name, ''), p.workspace_id, COALESCE(w.name, ''),
+			s.title, s.body, bm25(search_index, 0, 0, 0, 10.0, 1.0) AS rank
+		FROM search_index s
+		JOIN projects p ON p.id = s.project_id
+		JOIN workspaces w ON w.id = p.workspace_id
+		WHERE search_index MATCH ?
+		ORDER BY rank
+		LIMIT ?`, ftsQuery(terms), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []SearchResult
+	for rows.Next() {
+		var r SearchResult
+		if err := rows.Scan(&r.Kind, &r.ItemID, &r.ProjectID, &r.ProjectName, &r.WorkspaceID, &r.WorkspaceName, &r.Title, &r.Body, &r.Rank); err != nil {
+			return nil, err
+		}
+		results = append(results, r)
+	}
+	return results, rows.Err()
+}
+
+func searchLike(db *sql.DB, terms []string, limit int) ([]SearchResult, error) {
+	rows, err := db.Query(`
+		SELECT 'task', t.id, t.project_id, COALESCE(p.name, ''), p.workspace_id, COALESCE(w.name, ''), COALESCE(t.title, ''), COALESCE(t.description, '')
+		FROM tasks t JOIN projects p ON p.id = t.project_id JOIN workspaces w ON w.id = p.workspace_id
+		UNION ALL
+		SELECT 'link', l.id, l.project_id, COALESCE(p.name, ''), p.workspace_id, COALESCE(w.name, ''), COALESCE(l.title, ''), COALESCE(l.url, '')
+		FROM links l JOIN projects p ON p.id = l.project_id JOIN workspaces w ON w.id = p.workspace_id
+		UNION ALL
+		SELECT 'tweet', tw.id, tw.project_id, COALESCE(p.name, ''), p.workspace_id, COALESCE(w.name, ''), '', COALESCE(tw.content, '')
+		FROM tweets tw JOIN projects p ON p.id = tw.project_id JOIN workspaces w ON w.id = p.workspace_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []SearchResult
+	for rows.Next() {
+		var r SearchResult
+		if err := rows.Scan(&r.Kind, &r.ItemID, &r.ProjectID, &r.ProjectName, &r.WorkspaceID, &r.WorkspaceName, &r.Title, &r.Body); err != nil {
+			return nil, err
+		}
+		if rank, ok := likeRank(r, terms); ok {
+			r.Rank = rank
+			results = append(results, r)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Rank < results[j].Rank })
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// likeRank mirrors the FTS weighting closely enough for the fallback path:
+// every term has to match somewhere, and title hits count ten times more
+// than body hits. Ranks are negative so that lower is better, like bm25.
+func likeRank(r SearchResult, terms []string) (float64, bool) {
+	title := strings.ToLower(r.Title)
+	body := strings.ToLower(r.Body)
+
+	var score float64
+	for _, term := range terms {
+		term = strings.ToLower(term)
+		inTitle := strings.Contains(title, term)
+		inBody := strings.Contains(body, term)
+		if !inTitle && !inBody {
+			return 0, false
+		}
+		if inTitle {
+			score += 10
+		}
+		if inBody {
+			score++
+		}
+	}
+	return -score, true
+}
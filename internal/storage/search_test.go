@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestSearchAcrossProjects(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	workspace := Workspace{ID: uuid.New().String(), Name: "Work"}
+	if err := CreateWorkspace(db, workspace); err != nil {
+		t.Fatalf("failed to create workspace: %v", err)
+	}
+	project := Project{ID: uuid.New().String(), WorkspaceID: workspace.ID, Name: "API"}
+	if err := CreateProject(db, project); err != nil {
+		t.Fatalf("failed to create project: %v", err)
+	}
+
+	link := Link{ID: uuid.New().String(), ProjectID: project.ID, Title: "Notes about rate limits", URL: "https://example.com/limits"}
+	if err := CreateLink(db, link); err != nil {
+		t.Fatalf("failed to create link: %v", err)
+	}
+	task := Task{ID: uuid.New().String(), ProjectID: project.ID, Title: "Add retries", Status: "To Do", Description: "respect rate headers"}
+	if err := CreateTask(db, task); err != nil {
+		t.Fatalf("failed to create task: %v", err)
+	}
+	if err := CreateTweet(db, Tweet{ID: uuid.New().String(), ProjectID: project.ID, Content: "unrelated"}); err != nil {
+		t.Fatalf("failed to create tweet: %v", err)
+	}
+
+	results, err := Search(db, "rate", 10)
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	// The title match should outrank the description match.
+	if results[0].ItemID != link.ID {
+		t.Errorf("expected link to rank first, got %s %q", results[0].Kind, results[0].Title)
+	}
+	if results[0].WorkspaceID != workspace.ID || results[0].ProjectName != project.Name {
+		t.Errorf("unexpected result context: %+v", results[0])
+	}
+
+	// Updates and deletes must be reflected in the results.
+	task.Description = "nothing to see"
+	if err := UpdateTask(db, task); err != nil {
+		t.Fatalf("failed to update task: %v", err)
+	}
+	if err := DeleteLink(db, link.ID); err != nil {
+		t.Fatalf("failed to delete link: %v", err)
+	}
+	results, err = Search(db, "rate", 10)
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("expected no results after update and delete, got %d", len(results))
+	}
+
+	if err := DeleteProject(db, project.ID); err != nil {
+		t.Fatalf("failed to delete project: %v", err)
+	}
+	if err := DeleteWorkspace(db, workspace.ID); err != nil {
+		t.Fatalf("failed to delete workspace: %v", err)
+	}
+}
+
+func TestSearchWithoutFTS5AfterIndexedBuild(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	if available, err := fts5Available(db); err != nil || available {
+		t.Skip("this build has FTS5")
+	}
+
+	// Leave the database as a build with FTS5 would, with a plain table
+	// standing in for the index this build can't create.
+	if _, err := db.Exec("CREATE TABLE search_index(kind, item_id, project_id, title, body)"); err != nil {
+		t.Fatalf("failed to create index table: %v", err)
+	}
+	if _, err := db.Exec(searchIndexSchema[strings.Index(searchIndexSchema, "CREATE TRIGGER"):]); err != nil {
+		t.Fatalf("failed to create triggers: %v", err)
+	}
+	if err := initSearchIndex(db); err != nil {
+		t.Fatalf("failed to set up search: %v", err)
+	}
+	if indexed, err := hasSearchIndex(db); err != nil || indexed {
+		t.Fatalf("expected the index to be disabled, got %v (%v)", indexed, err)
+	}
+
+	workspace := Workspace{ID: uuid.New().String(), Name: "Work"}
+	if err := CreateWorkspace(db, workspace); err != nil {
+		t.Fatalf("failed to create workspace: %v", err)
+	}
+	project := Project{ID: uuid.New().String(), WorkspaceID: workspace.ID, Name: "API"}
+	if err := CreateProject(db, project); err != nil {
+		t.Fatalf("failed to create project: %v", err)
+	}
+	task := Task{ID: uuid.New().String(), ProjectID: project.ID, Title: "Add retries", Status: "To Do"}
+	if err := CreateTask(db, task); err != nil {
+		t.Fatalf("failed to create task: %v", err)
+	}
+	var stale int
+	if err := db.QueryRow("SELECT COUNT(*) FROM search_index").Scan(&stale); err != nil || stale != 0 {
+		t.Errorf("expected the triggers to be gone, got %d index rows (%v)", stale, err)
+	}
+	results, err := Search(db, "retries", 10)
+	if err != nil || len(results) != 1 || results[0].ItemID != task.ID {
+		t.Errorf("expected the task from the LIKE fallback, got %+v (%v)", results, err)
+	}
+}
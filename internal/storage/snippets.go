@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// Snippet is a named piece of reusable text, such as a shell command or a
+// SQL query. Snippets with an empty ProjectID are global and show up in
+// every project.
+type Snippet struct {
+	ID        string
+	ProjectID string
+	Name      string
+	Language  string
+	Tags      []string
+	Body      string
+	UpdatedAt time.Time
+}
+
+// Global reports whether the snippet is shared by every project.
+func (s Snippet) Global() bool {
+	return s.ProjectID == ""
+}
+
+// GetSnippets returns a project's snippets together with the global ones,
+// sorted by name.
+func GetSnippets(db *sql.DB, projectID string) ([]Snippet, error) {
+	rows, err := db.Query("SELECT id, project_id, name, language, tags, body, updated_at FROM snippets WHERE project_id = ? OR project_id = '' ORDER BY name COLLATE NOCASE, rowid", projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var snippets []Snippet
+	for rows.Next() {
+		var snippet Snippet
+		var tags, updatedAt string
+		if err := rows.Scan(&snippet.ID, &snippet.ProjectID, &snippet.Name, &snippet.Language, &tags, &snippet.Body, &updatedAt); err != nil {
+			return nil, err
+		}
+		snippet.Tags = ParseTags(tags)
+		snippet.UpdatedAt = parseTimestamp(updatedAt)
+		snippets = append(snippets, snippet)
+	}
+	return snippets, rows.Err()
+}
+
+func CreateSnippet(db *sql.DB, snippet Snippet) error {
+	stmt, err := db.Prepare("INSERT INTO snippets(id, project_id, name, language, tags, body, updated_at) VALUES(?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(snippet.ID, snippet.ProjectID, snippet.Name, snippet.Language, strings.Join(snippet.Tags, ","), snippet.Body, formatTimestamp(time.Now()))
+	if err != nil {
+		return err
+	}
+	return recordEvent(db, Event{ProjectID: snippet.ProjectID, EntityType: EntitySnippet, EntityID: snippet.ID, Action: ActionCreate, Summary: snippet.Name})
+}
+
+// UpdateSnippet saves every field of the snippet, including its project, so
+// it can also move a snippet between a project and the global scope.
+func UpdateSnippet(db *sql.DB, snippet Snippet) error {
+	stmt, err := db.Prepare("UPDATE snippets SET project_id = ?, name = ?, language = ?, tags = ?, body = ?, updated_at = ? WHERE id = ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(snippet.ProjectID, snippet.Name, snippet.Language, strings.Join(snippet.Tags, ","), snippet.Body, formatTimestamp(time.Now()), snippet.ID)
+	if err != nil {
+		return err
+	}
+	return recordEvent(db, Event{ProjectID: snippet.ProjectID, EntityType: EntitySnippet, EntityID: snippet.ID, Action: ActionUpdate, Summary: snippet.Name})
+}
+
+func DeleteSnippet(db *sql.DB, id string) error {
+	var projectID, name sql.NullString
+	err := db.QueryRow("SELECT project_id, name FROM snippets WHERE id = ?", id).Scan(&projectID, &name)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	stmt, err := db.Prepare("DELETE FROM snippets WHERE id = ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(id)
+	if err != nil {
+		return err
+	}
+	return recordEvent(db, Event{ProjectID: projectID.String, EntityType: EntitySnippet, EntityID: id, Action: ActionDelete, Summary: name.String})
+}
+
+// ParseTags parses a comma-separated tag list, dropping empty entries.
+func ParseTags(s string) []string {
+	var tags []string
+	for _, tag := range strings.Split(s, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
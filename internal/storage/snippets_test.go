@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestSnippetsIncludeGlobalOnes(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	projectID, otherID := uuid.New().String(), uuid.New().String()
+	for _, snippet := range []Snippet{
+		{ID: uuid.New().String(), ProjectID: projectID, Name: "slow queries", Language: "sql", Tags: []string{"db", "perf"}, Body: "SELECT 1;"},
+		{ID: uuid.New().String(), Name: "curl health", Tags: []string{"http"}},
+		{ID: uuid.New().String(), ProjectID: otherID, Name: "other project"},
+	} {
+		if err := CreateSnippet(db, snippet); err != nil {
+			t.Fatalf("failed to create snippet: %v", err)
+		}
+	}
+
+	snippets, err := GetSnippets(db, projectID)
+	if err != nil {
+		t.Fatalf("failed to get snippets: %v", err)
+	}
+	if len(snippets) != 2 || snippets[0].Name != "curl health" || !snippets[0].Global() {
+		t.Fatalf("expected the global snippet and the project's one, got %+v", snippets)
+	}
+	if got := snippets[1].Tags; len(got) != 2 || got[0] != "db" || got[1] != "perf" {
+		t.Errorf("expected the tags to round-trip, got %q", got)
+	}
+
+	// Making a project snippet global shares it with the other project.
+	snippets[1].ProjectID = ""
+	if err := UpdateSnippet(db, snippets[1]); err != nil {
+		t.Fatalf("failed to update snippet: %v", err)
+	}
+	if got, _ := GetSnippets(db, otherID); len(got) != 3 {
+		t.Errorf("expected 3 snippets in the other project, got %d", len(got))
+	}
+
+	if err := DeleteSnippet(db, snippets[0].ID); err != nil {
+		t.Fatalf("failed to delete snippet: %v", err)
+	}
+	if got, _ := GetSnippets(db, projectID); len(got) != 1 {
+		t.Errorf("expected 1 snippet after deleting, got %d", len(got))
+	}
+}
+
+func TestParseTags(t *testing.T) {
+	if got := ParseTags(" db, ,perf ,"); len(got) != 2 || got[0] != "db" || got[1] != "perf" {
+		t.Errorf("unexpected tags %q", got)
+	}
+	if got := ParseTags(""); got != nil {
+		t.Errorf("expected no tags, got %q", got)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"log"
 	"strings"
+	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 )
@@ -44,6 +45,30 @@ func InitDB(filepath string) (*sql.DB, error) {
 		project_id TEXT NOT NULL,
 		title TEXT,
 		status TEXT,
+		description TEXT,
+		due_date TEXT,
+		FOREIGN KEY(project_id) REFERENCES projects(id) ON DELETE CASCADE
+	);
+	CREATE TABLE IF NOT EXISTS checklist_items (
+		id TEXT NOT NULL PRIMARY KEY,
+		task_id TEXT NOT NULL,
+		position INTEGER NOT NULL,
+		text TEXT NOT NULL,
+		done INTEGER NOT NULL DEFAULT 0,
+		FOREIGN KEY(task_id) REFERENCES tasks(id) ON DELETE CASCADE
+	);
+	CREATE TABLE IF NOT EXISTS task_dependencies (
+		task_id TEXT NOT NULL,
+		blocked_by TEXT NOT NULL,
+		PRIMARY KEY(task_id, blocked_by),
+		FOREIGN KEY(task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+		FOREIGN KEY(blocked_by) REFERENCES tasks(id) ON DELETE CASCADE
+	);
+	CREATE TABLE IF NOT EXISTS wip_limits (
+		project_id TEXT NOT NULL,
+		status TEXT NOT NULL,
+		max_tasks INTEGER NOT NULL,
+		PRIMARY KEY(project_id, status),
 		FOREIGN KEY(project_id) REFERENCES projects(id) ON DELETE CASCADE
 	);
 	CREATE TABLE IF NOT EXISTS tweets (
@@ -52,6 +77,137 @@ func InitDB(filepath string) (*sql.DB, error) {
 		content TEXT,
 		FOREIGN KEY(project_id) REFERENCES projects(id) ON DELETE CASCADE
 	);
+	CREATE TABLE IF NOT EXISTS notes (
+		id TEXT NOT NULL PRIMARY KEY,
+		project_id TEXT NOT NULL,
+		title TEXT,
+		content TEXT,
+		updated_at TEXT,
+		FOREIGN KEY(project_id) REFERENCES projects(id) ON DELETE CASCADE
+	);
+	CREATE TABLE IF NOT EXISTS focus_sessions (
+		id TEXT NOT NULL PRIMARY KEY,
+		task_id TEXT NOT NULL,
+		started_at TEXT NOT NULL,
+		ended_at TEXT NOT NULL,
+		FOREIGN KEY(task_id) REFERENCES tasks(id) ON DELETE CASCADE
+	);
+	CREATE TABLE IF NOT EXISTS time_entries (
+		id TEXT NOT NULL PRIMARY KEY,
+		task_id TEXT NOT NULL,
+		started_at TEXT NOT NULL,
+		ended_at TEXT NOT NULL DEFAULT '',
+		FOREIGN KEY(task_id) REFERENCES tasks(id) ON DELETE CASCADE
+	);
+	CREATE TABLE IF NOT EXISTS repositories (
+		id TEXT NOT NULL PRIMARY KEY,
+		project_id TEXT NOT NULL,
+		path TEXT NOT NULL,
+		FOREIGN KEY(project_id) REFERENCES projects(id) ON DELETE CASCADE
+	);
+	CREATE TABLE IF NOT EXISTS log_files (
+		id TEXT NOT NULL PRIMARY KEY,
+		project_id TEXT NOT NULL,
+		path TEXT NOT NULL,
+		FOREIGN KEY(project_id) REFERENCES projects(id) ON DELETE CASCADE
+	);
+	CREATE TABLE IF NOT EXISTS feeds (
+		id TEXT NOT NULL PRIMARY KEY,
+		project_id TEXT NOT NULL,
+		source TEXT NOT NULL,
+		title TEXT NOT NULL DEFAULT '',
+		etag TEXT NOT NULL DEFAULT '',
+		last_modified TEXT NOT NULL DEFAULT '',
+		fetched_at TEXT NOT NULL DEFAULT '',
+		FOREIGN KEY(project_id) REFERENCES projects(id) ON DELETE CASCADE
+	);
+	CREATE TABLE IF NOT EXISTS feed_items (
+		id TEXT NOT NULL PRIMARY KEY,
+		feed_id TEXT NOT NULL,
+		guid TEXT NOT NULL,
+		title TEXT NOT NULL DEFAULT '',
+		link TEXT NOT NULL DEFAULT '',
+		published_at TEXT NOT NULL DEFAULT '',
+		read INTEGER NOT NULL DEFAULT 0,
+		UNIQUE(feed_id, guid),
+		FOREIGN KEY(feed_id) REFERENCES feeds(id) ON DELETE CASCADE
+	);
+	CREATE TABLE IF NOT EXISTS habits (
+		id TEXT NOT NULL PRIMARY KEY,
+		workspace_id TEXT NOT NULL,
+		name TEXT NOT NULL,
+		frequency TEXT NOT NULL DEFAULT 'daily',
+		created_at TEXT NOT NULL,
+		FOREIGN KEY(workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE
+	);
+	CREATE TABLE IF NOT EXISTS habit_checkins (
+		habit_id TEXT NOT NULL,
+		day TEXT NOT NULL,
+		PRIMARY KEY(habit_id, day),
+		FOREIGN KEY(habit_id) REFERENCES habits(id) ON DELETE CASCADE
+	);
+	CREATE TABLE IF NOT EXISTS journal_entries (
+		workspace_id TEXT NOT NULL,
+		day TEXT NOT NULL,
+		content TEXT NOT NULL DEFAULT '',
+		updated_at TEXT NOT NULL,
+		PRIMARY KEY(workspace_id, day),
+		FOREIGN KEY(workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE
+	);
+	CREATE TABLE IF NOT EXISTS milestones (
+		id TEXT NOT NULL PRIMARY KEY,
+		project_id TEXT NOT NULL,
+		name TEXT NOT NULL,
+		target_at TEXT NOT NULL,
+		FOREIGN KEY(project_id) REFERENCES projects(id) ON DELETE CASCADE
+	);
+	CREATE TABLE IF NOT EXISTS snippets (
+		id TEXT NOT NULL PRIMARY KEY,
+		project_id TEXT NOT NULL DEFAULT '',
+		name TEXT NOT NULL,
+		language TEXT NOT NULL DEFAULT '',
+		tags TEXT NOT NULL DEFAULT '',
+		body TEXT NOT NULL DEFAULT '',
+		updated_at TEXT NOT NULL
+	);
+	CREATE TABLE IF NOT EXISTS commands (
+		id TEXT NOT NULL PRIMARY KEY,
+		project_id TEXT NOT NULL,
+		name TEXT NOT NULL,
+		command TEXT NOT NULL,
+		work_dir TEXT NOT NULL DEFAULT '',
+		env TEXT NOT NULL DEFAULT '',
+		FOREIGN KEY(project_id) REFERENCES projects(id) ON DELETE CASCADE
+	);
+	CREATE TABLE IF NOT EXISTS command_runs (
+		id TEXT NOT NULL PRIMARY KEY,
+		command_id TEXT NOT NULL,
+		started_at TEXT NOT NULL,
+		ended_at TEXT NOT NULL,
+		exit_code INTEGER NOT NULL,
+		output TEXT NOT NULL DEFAULT '',
+		FOREIGN KEY(command_id) REFERENCES commands(id) ON DELETE CASCADE
+	);
+	CREATE TABLE IF NOT EXISTS events (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		workspace_id TEXT NOT NULL DEFAULT '',
+		project_id TEXT NOT NULL DEFAULT '',
+		entity_type TEXT NOT NULL,
+		entity_id TEXT NOT NULL,
+		action TEXT NOT NULL,
+		summary TEXT NOT NULL DEFAULT '',
+		old_value TEXT NOT NULL DEFAULT '',
+		new_value TEXT NOT NULL DEFAULT '',
+		created_at TEXT NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS events_project ON events(project_id, created_at);
+	CREATE INDEX IF NOT EXISTS events_workspace ON events(workspace_id, created_at);
+	CREATE INDEX IF NOT EXISTS events_entity ON events(entity_type, entity_id);
+	CREATE INDEX IF NOT EXISTS time_entries_task ON time_entries(task_id);
+	CREATE INDEX IF NOT EXISTS command_runs_command ON command_runs(command_id, started_at);
+	CREATE INDEX IF NOT EXISTS feed_items_feed ON feed_items(feed_id, published_at);
+	CREATE INDEX IF NOT EXISTS checklist_items_task ON checklist_items(task_id, position);
+	CREATE INDEX IF NOT EXISTS task_dependencies_blocker ON task_dependencies(blocked_by);
 	`
 	_, err = db.Exec(sqlStmt)
 	if err != nil {
@@ -67,63 +223,80 @@ func InitDB(filepath string) (*sql.DB, error) {
 }
 
 func runMigrations(db *sql.DB) error {
-	// Check if the active_modules column exists in projects
-	rows, err := db.Query("PRAGMA table_info(projects)")
-	if err != nil {
+	if err := addColumnIfMissing(db, "projects", "active_modules", "TEXT"); err != nil {
 		return err
 	}
-	defer rows.Close()
-
-	var columnExists bool
-	for rows.Next() {
-		var cid, notnull, pk int
-		var name, dtype, dflt_value sql.NullString
-		if err := rows.Scan(&cid, &name, &dtype, &notnull, &dflt_value, &pk); err != nil {
-			return err
-		}
-		if name.Valid && name.String == "active_modules" {
-			columnExists = true
-			break
-		}
+	if err := addColumnIfMissing(db, "workspaces", "active_modules", "TEXT"); err != nil {
+		return err
 	}
-
-	if !columnExists {
-		log.Println("Running migration: adding active_modules to projects table")
-		_, err := db.Exec("ALTER TABLE projects ADD COLUMN active_modules TEXT")
-		if err != nil {
+	if err := addColumnIfMissing(db, "tasks", "description", "TEXT"); err != nil {
+		return err
+	}
+	if err := addColumnIfMissing(db, "tasks", "due_date", "TEXT"); err != nil {
+		return err
+	}
+	if err := addColumnIfMissing(db, "tasks", "repo_path", "TEXT"); err != nil {
+		return err
+	}
+	if err := addColumnIfMissing(db, "tasks", "branch", "TEXT"); err != nil {
+		return err
+	}
+	if err := addColumnIfMissing(db, "tasks", "branch_base", "TEXT"); err != nil {
+		return err
+	}
+	if err := addColumnIfMissing(db, "workspaces", "calendar_path", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	if err := addColumnIfMissing(db, "tasks", "auto_done", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := addColumnIfMissing(db, "projects", "wip_strict", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	for _, column := range []string{"recurrence", "next_run", "template_id", "priority", "assignee", "labels"} {
+		if err := addColumnIfMissing(db, "tasks", column, "TEXT"); err != nil {
 			return err
 		}
 	}
 
-	// Check if the active_modules column exists in workspaces
-	rows, err = db.Query("PRAGMA table_info(workspaces)")
+	return initSearchIndex(db)
+}
+
+// columnExists reports whether table already has a column with the given name.
+func columnExists(db *sql.DB, table, column string) (bool, error) {
+	rows, err := db.Query("PRAGMA table_info(" + table + ")")
 	if err != nil {
-		return err
+		return false, err
 	}
 	defer rows.Close()
 
-	columnExists = false
 	for rows.Next() {
 		var cid, notnull, pk int
 		var name, dtype, dflt_value sql.NullString
 		if err := rows.Scan(&cid, &name, &dtype, &notnull, &dflt_value, &pk); err != nil {
-			return err
+			return false, err
 		}
-		if name.Valid && name.String == "active_modules" {
-			columnExists = true
-			break
+		if name.Valid && name.String == column {
+			return true, nil
 		}
 	}
+	return false, rows.Err()
+}
 
-	if !columnExists {
-		log.Println("Running migration: adding active_modules to workspaces table")
-		_, err := db.Exec("ALTER TABLE workspaces ADD COLUMN active_modules TEXT")
-		if err != nil {
-			return err
-		}
+// addColumnIfMissing adds a column to an existing table so that databases
+// created by older versions pick up new fields.
+func addColumnIfMissing(db *sql.DB, table, column, definition string) error {
+	exists, err := columnExists(db, table, column)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
 	}
 
-	return nil
+	log.Printf("Running migration: adding %s to %s table", column, table)
+	_, err = db.Exec("ALTER TABLE " + table + " ADD COLUMN " + column + " " + definition)
+	return err
 }
 
 type Workspace struct {
@@ -142,7 +315,10 @@ func CreateWorkspace(db *sql.DB, workspace Workspace) error {
 	defer stmt.Close()
 
 	_, err = stmt.Exec(workspace.ID, workspace.Name, workspace.Color, workspace.ActiveModules)
-	return err
+	if err != nil {
+		return err
+	}
+	return recordEvent(db, Event{WorkspaceID: workspace.ID, EntityType: EntityWorkspace, EntityID: workspace.ID, Action: ActionCreate, Summary: workspace.Name})
 }
 
 func GetWorkspace(db *sql.DB, id string) (Workspace, error) {
@@ -165,10 +341,18 @@ func UpdateWorkspace(db *sql.DB, workspace Workspace) error {
 	defer stmt.Close()
 
 	_, err = stmt.Exec(workspace.Name, workspace.Color, workspace.ActiveModules, workspace.ID)
-	return err
+	if err != nil {
+		return err
+	}
+	return recordEvent(db, Event{WorkspaceID: workspace.ID, EntityType: EntityWorkspace, EntityID: workspace.ID, Action: ActionUpdate, Summary: workspace.Name})
 }
 
 func DeleteWorkspace(db *sql.DB, id string) error {
+	var name sql.NullString
+	if err := db.QueryRow("SELECT name FROM workspaces WHERE id = ?", id).Scan(&name); err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
 	stmt, err := db.Prepare("DELETE FROM workspaces WHERE id = ?")
 	if err != nil {
 		return err
@@ -176,7 +360,10 @@ func DeleteWorkspace(db *sql.DB, id string) error {
 	defer stmt.Close()
 
 	_, err = stmt.Exec(id)
-	return err
+	if err != nil {
+		return err
+	}
+	return recordEvent(db, Event{WorkspaceID: id, EntityType: EntityWorkspace, EntityID: id, Action: ActionDelete, Summary: name.String})
 }
 
 func GetAllWorkspaces(db *sql.DB) ([]Workspace, error) {
@@ -229,7 +416,10 @@ func CreateProject(db *sql.DB, project Project) error {
 	defer stmt.Close()
 
 	_, err = stmt.Exec(project.ID, project.WorkspaceID, project.Name, project.Description, project.Status, project.ActiveModules)
-	return err
+	if err != nil {
+		return err
+	}
+	return recordEvent(db, Event{WorkspaceID: project.WorkspaceID, ProjectID: project.ID, EntityType: EntityProject, EntityID: project.ID, Action: ActionCreate, Summary: project.Name})
 }
 
 // UpdateProject updates a project in the database
@@ -241,7 +431,23 @@ func UpdateProject(db *sql.DB, project Project) error {
 	defer stmt.Close()
 
 	_, err = stmt.Exec(project.Name, project.Description, project.Status, project.ActiveModules, project.ID)
-	return err
+	if err != nil {
+		return err
+	}
+	return recordEvent(db, Event{ProjectID: project.ID, EntityType: EntityProject, EntityID: project.ID, Action: ActionUpdate, Summary: project.Name})
+}
+
+// GetProject retrieves a single project by ID
+func GetProject(db *sql.DB, id string) (Project, error) {
+	row := db.QueryRow("SELECT id, workspace_id, name, description, status, active_modules FROM projects WHERE id = ?", id)
+
+	var project Project
+	err := row.Scan(&project.ID, &project.WorkspaceID, &project.Name, &project.Description, &project.Status, &project.ActiveModules)
+	if err != nil {
+		return Project{}, err
+	}
+
+	return project, nil
 }
 
 // GetAllProjectsForWorkspace retrieves all projects for a given workspace
@@ -266,6 +472,12 @@ func GetAllProjectsForWorkspace(db *sql.DB, workspaceID string) ([]Project, erro
 
 // DeleteProject deletes a project from the database
 func DeleteProject(db *sql.DB, id string) error {
+	var workspaceID, name sql.NullString
+	err := db.QueryRow("SELECT workspace_id, name FROM projects WHERE id = ?", id).Scan(&workspaceID, &name)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
 	stmt, err := db.Prepare("DELETE FROM projects WHERE id = ?")
 	if err != nil {
 		return err
@@ -273,7 +485,10 @@ func DeleteProject(db *sql.DB, id string) error {
 	defer stmt.Close()
 
 	_, err = stmt.Exec(id)
-	return err
+	if err != nil {
+		return err
+	}
+	return recordEvent(db, Event{WorkspaceID: workspaceID.String, ProjectID: id, EntityType: EntityProject, EntityID: id, Action: ActionDelete, Summary: name.String})
 }
 
 
@@ -311,10 +526,19 @@ func CreateLink(db *sql.DB, link Link) error {
 	defer stmt.Close()
 
 	_, err = stmt.Exec(link.ID, link.ProjectID, link.Title, link.URL)
-	return err
+	if err != nil {
+		return err
+	}
+	return recordEvent(db, Event{ProjectID: link.ProjectID, EntityType: EntityLink, EntityID: link.ID, Action: ActionCreate, Summary: link.Title, NewValue: link.URL})
 }
 
 func DeleteLink(db *sql.DB, id string) error {
+	var projectID, title, url sql.NullString
+	err := db.QueryRow("SELECT project_id, title, url FROM links WHERE id = ?", id).Scan(&projectID, &title, &url)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
 	stmt, err := db.Prepare("DELETE FROM links WHERE id = ?")
 	if err != nil {
 		return err
@@ -322,18 +546,66 @@ func DeleteLink(db *sql.DB, id string) error {
 	defer stmt.Close()
 
 	_, err = stmt.Exec(id)
-	return err
+	if err != nil {
+		return err
+	}
+	return recordEvent(db, Event{ProjectID: projectID.String, EntityType: EntityLink, EntityID: id, Action: ActionDelete, Summary: title.String, OldValue: url.String})
 }
 
+// DoneStatus is the status of finished tasks. Done tasks are never overdue.
+const DoneStatus = "Done"
+
+// DateLayout is the format of calendar dates such as Task.DueDate.
+const DateLayout = "2006-01-02"
+
 type Task struct {
-	ID        string
-	ProjectID string
-	Title     string
-	Status    string
+	ID          string
+	ProjectID   string
+	Title       string
+	Status      string
+	Description string
+	DueDate     string // DateLayout, empty when the task has no due date
+	RepoPath    string // local repository the task's work happens in, if any
+	Branch      string // branch in RepoPath, empty when the task isn't linked
+	BranchBase  string // tip of RepoPath's main branch when Branch was linked
+	AutoDone    bool   // move the task to Done once its whole checklist is checked
+	Recurrence  string // schedule of a recurring task as an RRULE, see ParseRecurrence
+	NextRun     string // DateLayout, when the next occurrence of a recurring task is created
+	TemplateID  string // recurring task this one was created from, if any
+	Priority    string // one of Priorities, empty when not set
+	Assignee    string
+	Labels      []string
+}
+
+// Priorities are the task priorities, highest first.
+var Priorities = []string{"high", "medium", "low"}
+
+// Overdue reports whether the task is unfinished and its due date is before today.
+func (t Task) Overdue(today time.Time) bool {
+	return t.DueDate != "" && t.Status != DoneStatus && t.DueDate < today.Format(DateLayout)
+}
+
+const taskColumns = "id, project_id, title, status, COALESCE(description, ''), COALESCE(due_date, ''), COALESCE(repo_path, ''), COALESCE(branch, ''), COALESCE(branch_base, ''), auto_done, COALESCE(recurrence, ''), COALESCE(next_run, ''), COALESCE(template_id, ''), COALESCE(priority, ''), COALESCE(assignee, ''), COALESCE(labels, '')"
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTask(row rowScanner) (Task, error) {
+	var task Task
+	var labels string
+	err := row.Scan(&task.ID, &task.ProjectID, &task.Title, &task.Status, &task.Description, &task.DueDate, &task.RepoPath, &task.Branch, &task.BranchBase, &task.AutoDone, &task.Recurrence, &task.NextRun, &task.TemplateID,
+		&task.Priority, &task.Assignee, &labels)
+	task.Labels = ParseTags(labels)
+	return task, err
+}
+
+func GetTask(db *sql.DB, id string) (Task, error) {
+	return scanTask(db.QueryRow("SELECT "+taskColumns+" FROM tasks WHERE id = ?", id))
 }
 
 func GetTasksForProject(db *sql.DB, projectID string) ([]Task, error) {
-	rows, err := db.Query("SELECT id, project_id, title, status FROM tasks WHERE project_id = ?", projectID)
+	rows, err := db.Query("SELECT "+taskColumns+" FROM tasks WHERE project_id = ?", projectID)
 	if err != nil {
 		return nil, err
 	}
@@ -341,8 +613,8 @@ func GetTasksForProject(db *sql.DB, projectID string) ([]Task, error) {
 
 	var tasks []Task
 	for rows.Next() {
-		var task Task
-		if err := rows.Scan(&task.ID, &task.ProjectID, &task.Title, &task.Status); err != nil {
+		task, err := scanTask(rows)
+		if err != nil {
 			return nil, err
 		}
 		tasks = append(tasks, task)
@@ -352,28 +624,69 @@ func GetTasksForProject(db *sql.DB, projectID string) ([]Task, error) {
 }
 
 func CreateTask(db *sql.DB, task Task) error {
-	stmt, err := db.Prepare("INSERT INTO tasks(id, project_id, title, status) VALUES(?, ?, ?, ?)")
+	stmt, err := db.Prepare("INSERT INTO tasks(id, project_id, title, status, description, due_date, repo_path, branch, branch_base, auto_done, recurrence, next_run, template_id, priority, assignee, labels) VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
 
-	_, err = stmt.Exec(task.ID, task.ProjectID, task.Title, task.Status)
-	return err
+	_, err = stmt.Exec(task.ID, task.ProjectID, task.Title, task.Status, task.Description, task.DueDate, task.RepoPath, task.Branch, task.BranchBase, task.AutoDone, task.Recurrence, task.NextRun, task.TemplateID,
+		task.Priority, task.Assignee, strings.Join(task.Labels, ","))
+	if err != nil {
+		return err
+	}
+	return recordEvent(db, Event{ProjectID: task.ProjectID, EntityType: EntityTask, EntityID: task.ID, Action: ActionCreate, Summary: task.Title, NewValue: task.Status})
 }
 
+// UpdateTask saves a task. Status changes are logged as moves so that the
+// task's history shows every column it passed through. NextRun and
+// TemplateID belong to the recurring task scheduler and aren't saved, so
+// that an outdated copy of the task can't roll them back; see SetNextRun. A
+// task that no longer exists is left alone, without logging anything.
 func UpdateTask(db *sql.DB, task Task) error {
-	stmt, err := db.Prepare("UPDATE tasks SET title = ?, status = ? WHERE id = ?")
+	var projectID, oldStatus sql.NullString
+	err := db.QueryRow("SELECT project_id, status FROM tasks WHERE id = ?", task.ID).Scan(&projectID, &oldStatus)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	stmt, err := db.Prepare("UPDATE tasks SET title = ?, status = ?, description = ?, due_date = ?, repo_path = ?, branch = ?, branch_base = ?, auto_done = ?, recurrence = ?, priority = ?, assignee = ?, labels = ? WHERE id = ?")
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
 
-	_, err = stmt.Exec(task.Title, task.Status, task.ID)
-	return err
+	_, err = stmt.Exec(task.Title, task.Status, task.Description, task.DueDate, task.RepoPath, task.Branch, task.BranchBase, task.AutoDone, task.Recurrence,
+		task.Priority, task.Assignee, strings.Join(task.Labels, ","), task.ID)
+	if err != nil {
+		return err
+	}
+
+	event := Event{ProjectID: projectID.String, EntityType: EntityTask, EntityID: task.ID, Action: ActionUpdate, Summary: task.Title}
+	if oldStatus.String != task.Status {
+		event.Action = ActionMove
+		event.OldValue = oldStatus.String
+		event.NewValue = task.Status
+	}
+	return recordEvent(db, event)
 }
 
 func DeleteTask(db *sql.DB, id string) error {
+	var projectID, title, status sql.NullString
+	err := db.QueryRow("SELECT project_id, title, status FROM tasks WHERE id = ?", id).Scan(&projectID, &title, &status)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	if _, err := db.Exec("DELETE FROM checklist_items WHERE task_id = ?", id); err != nil {
+		return err
+	}
+	if _, err := db.Exec("DELETE FROM task_dependencies WHERE task_id = ? OR blocked_by = ?", id, id); err != nil {
+		return err
+	}
 	stmt, err := db.Prepare("DELETE FROM tasks WHERE id = ?")
 	if err != nil {
 		return err
@@ -381,7 +694,10 @@ func DeleteTask(db *sql.DB, id string) error {
 	defer stmt.Close()
 
 	_, err = stmt.Exec(id)
-	return err
+	if err != nil {
+		return err
+	}
+	return recordEvent(db, Event{ProjectID: projectID.String, EntityType: EntityTask, EntityID: id, Action: ActionDelete, Summary: title.String, OldValue: status.String})
 }
 
 type Tweet struct {
@@ -427,7 +743,10 @@ func CreateTweet(db *sql.DB, tweet Tweet) error {
 	defer stmt.Close()
 
 	_, err = stmt.Exec(tweet.ID, tweet.ProjectID, tweet.Content)
-	return err
+	if err != nil {
+		return err
+	}
+	return recordEvent(db, Event{ProjectID: tweet.ProjectID, EntityType: EntityTweet, EntityID: tweet.ID, Action: ActionCreate, Summary: tweet.Title()})
 }
 
 func UpdateTweet(db *sql.DB, tweet Tweet) error {
@@ -438,10 +757,28 @@ func UpdateTweet(db *sql.DB, tweet Tweet) error {
 	defer stmt.Close()
 
 	_, err = stmt.Exec(tweet.Content, tweet.ID)
-	return err
+	if err != nil {
+		return err
+	}
+
+	// The caller may not know the project, e.g. when only the content changed.
+	projectID := sql.NullString{String: tweet.ProjectID}
+	if tweet.ProjectID == "" {
+		err := db.QueryRow("SELECT project_id FROM tweets WHERE id = ?", tweet.ID).Scan(&projectID)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+	}
+	return recordEvent(db, Event{ProjectID: projectID.String, EntityType: EntityTweet, EntityID: tweet.ID, Action: ActionUpdate, Summary: tweet.Title()})
 }
 
 func DeleteTweet(db *sql.DB, id string) error {
+	var projectID, content sql.NullString
+	err := db.QueryRow("SELECT project_id, content FROM tweets WHERE id = ?", id).Scan(&projectID, &content)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
 	stmt, err := db.Prepare("DELETE FROM tweets WHERE id = ?")
 	if err != nil {
 		return err
@@ -449,5 +786,8 @@ func DeleteTweet(db *sql.DB, id string) error {
 	defer stmt.Close()
 
 	_, err = stmt.Exec(id)
-	return err
+	if err != nil {
+		return err
+	}
+	return recordEvent(db, Event{ProjectID: projectID.String, EntityType: EntityTweet, EntityID: id, Action: ActionDelete, Summary: Tweet{Content: content.String}.Title()})
 }
\ No newline at end of file
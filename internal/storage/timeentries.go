@@ -0,0 +1,235 @@
+package storage
+
+import (
+	"database/sql"
+	"sort"
+	"time"
+)
+
+// TimeEntry is a tracked stretch of work on a task. EndedAt is zero while
+// the entry is still running; at most one entry runs at a time.
+type TimeEntry struct {
+	ID        string
+	TaskID    string
+	StartedAt time.Time
+	EndedAt   time.Time
+}
+
+// Running reports whether the entry hasn't been stopped yet.
+func (e TimeEntry) Running() bool {
+	return e.EndedAt.IsZero()
+}
+
+// Duration returns how long the entry ran, counting a running entry up to now.
+func (e TimeEntry) Duration(now time.Time) time.Duration {
+	end := e.EndedAt
+	if e.Running() {
+		end = now
+	}
+	if end.Before(e.StartedAt) {
+		return 0
+	}
+	return end.Sub(e.StartedAt)
+}
+
+// TrackedEntry is a time entry together with the task, project and workspace
+// it belongs to.
+type TrackedEntry struct {
+	TimeEntry
+	TaskTitle     string
+	ProjectID     string
+	ProjectName   string
+	WorkspaceID   string
+	WorkspaceName string
+}
+
+const trackedEntryQuery = `
+	SELECT e.id, e.task_id, e.started_at, e.ended_at, t.title, p.id, p.name, w.id, w.name
+	FROM time_entries e
+	JOIN tasks t ON t.id = e.task_id
+	JOIN projects p ON p.id = t.project_id
+	JOIN workspaces w ON w.id = p.workspace_id`
+
+func scanTrackedEntries(rows *sql.Rows) ([]TrackedEntry, error) {
+	defer rows.Close()
+
+	var entries []TrackedEntry
+	for rows.Next() {
+		var entry TrackedEntry
+		var startedAt, endedAt string
+		if err := rows.Scan(&entry.ID, &entry.TaskID, &startedAt, &endedAt, &entry.TaskTitle,
+			&entry.ProjectID, &entry.ProjectName, &entry.WorkspaceID, &entry.WorkspaceName); err != nil {
+			return nil, err
+		}
+		entry.StartedAt = parseTimestamp(startedAt)
+		if endedAt != "" {
+			entry.EndedAt = parseTimestamp(endedAt)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// StartTimeEntry starts tracking time on a task at entry.StartedAt, stopping
+// whatever entry was running before. Starting and stopping an entry are
+// logged with the task's title as Summary, and the time tracked as NewValue
+// once stopped.
+func StartTimeEntry(db *sql.DB, entry TimeEntry) error {
+	if err := StopTimeEntry(db, entry.StartedAt); err != nil {
+		return err
+	}
+
+	stmt, err := db.Prepare("INSERT INTO time_entries(id, task_id, started_at) VALUES(?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(entry.ID, entry.TaskID, formatTimestamp(entry.StartedAt))
+	if err != nil {
+		return err
+	}
+
+	var projectID, title sql.NullString
+	err = db.QueryRow("SELECT project_id, title FROM tasks WHERE id = ?", entry.TaskID).Scan(&projectID, &title)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	return recordEvent(db, Event{ProjectID: projectID.String, EntityType: EntityTimeEntry, EntityID: entry.ID, Action: ActionCreate, Summary: title.String})
+}
+
+// StopTimeEntry stops the running entry, if there is one.
+func StopTimeEntry(db *sql.DB, now time.Time) error {
+	running, err := GetRunningTimeEntry(db)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := db.Prepare("UPDATE time_entries SET ended_at = ? WHERE ended_at = ''")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(formatTimestamp(now))
+	if err != nil || running == nil {
+		return err
+	}
+	return recordEvent(db, Event{ProjectID: running.ProjectID, EntityType: EntityTimeEntry, EntityID: running.ID, Action: ActionUpdate,
+		Summary: running.TaskTitle, NewValue: running.Duration(now).Round(time.Second).String()})
+}
+
+// GetRunningTimeEntry returns the running entry, or nil when no time is
+// being tracked.
+func GetRunningTimeEntry(db *sql.DB) (*TrackedEntry, error) {
+	rows, err := db.Query(trackedEntryQuery + " WHERE e.ended_at = '' ORDER BY e.started_at DESC LIMIT 1")
+	if err != nil {
+		return nil, err
+	}
+	entries, err := scanTrackedEntries(rows)
+	if err != nil || len(entries) == 0 {
+		return nil, err
+	}
+	return &entries[0], nil
+}
+
+// GetTrackedTimeForProject returns the total tracked time per task of a
+// project, keyed by task ID. Running entries count up to now.
+func GetTrackedTimeForProject(db *sql.DB, projectID string, now time.Time) (map[string]time.Duration, error) {
+	rows, err := db.Query(trackedEntryQuery+" WHERE p.id = ?", projectID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := scanTrackedEntries(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[string]time.Duration)
+	for _, entry := range entries {
+		totals[entry.TaskID] += entry.Duration(now)
+	}
+	return totals, nil
+}
+
+// Groupings for GetTimeReport.
+const (
+	GroupByTask      = "task"
+	GroupByProject   = "project"
+	GroupByWorkspace = "workspace"
+)
+
+// TimeReportRow is the time tracked on one task, project or workspace. Names
+// finer than the report's grouping are left empty.
+type TimeReportRow struct {
+	WorkspaceName string
+	ProjectName   string
+	TaskTitle     string
+	Duration      time.Duration
+}
+
+// GetTimeReport sums the time tracked between from and to across all
+// workspaces, grouped by task, project or workspace. Entries overlapping the
+// range only count the part inside it. Rows are ordered by workspace,
+// project and task name.
+func GetTimeReport(db *sql.DB, from, to time.Time, groupBy string, now time.Time) ([]TimeReportRow, error) {
+	rows, err := db.Query(trackedEntryQuery+" WHERE e.started_at < ? AND (e.ended_at = '' OR e.ended_at > ?)",
+		formatTimestamp(to), formatTimestamp(from))
+	if err != nil {
+		return nil, err
+	}
+	entries, err := scanTrackedEntries(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	var report []TimeReportRow
+	index := make(map[string]int)
+	for _, entry := range entries {
+		entry.StartedAt = laterOf(entry.StartedAt, from)
+		if entry.Running() {
+			entry.EndedAt = now
+		}
+		if entry.EndedAt.After(to) {
+			entry.EndedAt = to
+		}
+
+		row := TimeReportRow{WorkspaceName: entry.WorkspaceName}
+		key := entry.WorkspaceID
+		switch groupBy {
+		case GroupByTask:
+			row.ProjectName, row.TaskTitle = entry.ProjectName, entry.TaskTitle
+			key = entry.TaskID
+		case GroupByProject:
+			row.ProjectName = entry.ProjectName
+			key = entry.ProjectID
+		}
+
+		i, ok := index[key]
+		if !ok {
+			i = len(report)
+			index[key] = i
+			report = append(report, row)
+		}
+		report[i].Duration += entry.Duration(now)
+	}
+
+	sort.SliceStable(report, func(i, j int) bool {
+		a, b := report[i], report[j]
+		if a.WorkspaceName != b.WorkspaceName {
+			return a.WorkspaceName < b.WorkspaceName
+		}
+		if a.ProjectName != b.ProjectName {
+			return a.ProjectName < b.ProjectName
+		}
+		return a.TaskTitle < b.TaskTitle
+	})
+	return report, nil
+}
+
+func laterOf(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestTimeTracking(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	workspace := Workspace{ID: uuid.New().String(), Name: "Client"}
+	if err := CreateWorkspace(db, workspace); err != nil {
+		t.Fatalf("failed to create workspace: %v", err)
+	}
+	project := Project{ID: uuid.New().String(), WorkspaceID: workspace.ID, Name: "Website"}
+	if err := CreateProject(db, project); err != nil {
+		t.Fatalf("failed to create project: %v", err)
+	}
+	design := Task{ID: uuid.New().String(), ProjectID: project.ID, Title: "Design", Status: "To Do"}
+	build := Task{ID: uuid.New().String(), ProjectID: project.ID, Title: "Build", Status: "To Do"}
+	for _, task := range []Task{design, build} {
+		if err := CreateTask(db, task); err != nil {
+			t.Fatalf("failed to create task: %v", err)
+		}
+	}
+
+	day := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	at := func(hour int) time.Time { return day.Add(time.Duration(hour) * time.Hour) }
+
+	// Design runs 8-10 the day before the range and into it; starting Build
+	// at 11 stops Design, and Build is still running at 13.
+	if err := StartTimeEntry(db, TimeEntry{ID: uuid.New().String(), TaskID: design.ID, StartedAt: at(-2)}); err != nil {
+		t.Fatalf("failed to start time entry: %v", err)
+	}
+	if err := StartTimeEntry(db, TimeEntry{ID: uuid.New().String(), TaskID: build.ID, StartedAt: at(11)}); err != nil {
+		t.Fatalf("failed to start time entry: %v", err)
+	}
+
+	running, err := GetRunningTimeEntry(db)
+	if err != nil {
+		t.Fatalf("failed to get running entry: %v", err)
+	}
+	if running == nil || running.TaskID != build.ID || running.ProjectName != "Website" {
+		t.Fatalf("expected Build to be running, got %+v", running)
+	}
+
+	now := at(13)
+	report, err := GetTimeReport(db, day, day.AddDate(0, 0, 1), GroupByTask, now)
+	if err != nil {
+		t.Fatalf("failed to get report: %v", err)
+	}
+	want := map[string]time.Duration{"Design": 11 * time.Hour, "Build": 2 * time.Hour}
+	if len(report) != len(want) {
+		t.Fatalf("expected %d rows, got %+v", len(want), report)
+	}
+	for _, row := range report {
+		if row.Duration != want[row.TaskTitle] {
+			t.Errorf("%s: expected %v, got %v", row.TaskTitle, want[row.TaskTitle], row.Duration)
+		}
+	}
+
+	report, err = GetTimeReport(db, day, day.AddDate(0, 0, 1), GroupByWorkspace, now)
+	if err != nil {
+		t.Fatalf("failed to get report: %v", err)
+	}
+	if len(report) != 1 || report[0].Duration != 13*time.Hour || report[0].ProjectName != "" {
+		t.Errorf("expected a single 13h workspace row, got %+v", report)
+	}
+
+	if err := StopTimeEntry(db, now); err != nil {
+		t.Fatalf("failed to stop time entry: %v", err)
+	}
+	if running, _ := GetRunningTimeEntry(db); running != nil {
+		t.Errorf("expected no running entry, got %+v", running)
+	}
+	totals, err := GetTrackedTimeForProject(db, project.ID, now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("failed to get tracked time: %v", err)
+	}
+	if totals[design.ID] != 13*time.Hour || totals[build.ID] != 2*time.Hour {
+		t.Errorf("unexpected totals %v", totals)
+	}
+}
@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"database/sql"
+)
+
+// MoveTaskToProject moves a task to another project, which may be in another
+// workspace. The task keeps its ID, so its checklist, tracked time and
+// history come along. Dependencies only link tasks of the same workspace, so
+// those on tasks outside the new one are dropped; it returns how many were.
+// Either all of it happens, or nothing does.
+func MoveTaskToProject(db *sql.DB, taskID, projectID string) (int, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var from, to Project
+	var title, status string
+	err = tx.QueryRow(`
+		SELECT p.id, p.workspace_id, p.name, t.title, t.status
+		FROM tasks t JOIN projects p ON p.id = t.project_id
+		WHERE t.id = ?`, taskID).Scan(&from.ID, &from.WorkspaceID, &from.Name, &title, &status)
+	if err != nil {
+		return 0, err
+	}
+	err = tx.QueryRow("SELECT id, workspace_id, name FROM projects WHERE id = ?", projectID).Scan(&to.ID, &to.WorkspaceID, &to.Name)
+	if err != nil {
+		return 0, err
+	}
+	if from.ID == to.ID {
+		return 0, nil
+	}
+
+	if _, err := tx.Exec("UPDATE tasks SET project_id = ? WHERE id = ?", to.ID, taskID); err != nil {
+		return 0, err
+	}
+	var dropped int64
+	if from.WorkspaceID != to.WorkspaceID {
+		result, err := tx.Exec(`
+			DELETE FROM task_dependencies
+			WHERE (task_id = ? AND blocked_by IN (SELECT t.id FROM tasks t JOIN projects p ON p.id = t.project_id WHERE p.workspace_id != ?))
+				OR (blocked_by = ? AND task_id IN (SELECT t.id FROM tasks t JOIN projects p ON p.id = t.project_id WHERE p.workspace_id != ?))`,
+			taskID, to.WorkspaceID, taskID, to.WorkspaceID)
+		if err != nil {
+			return 0, err
+		}
+		if dropped, err = result.RowsAffected(); err != nil {
+			return 0, err
+		}
+	}
+
+	err = recordEvent(tx, Event{WorkspaceID: from.WorkspaceID, ProjectID: from.ID, EntityType: EntityTask, EntityID: taskID,
+		Action: ActionTransferOut, Summary: title, OldValue: status, NewValue: to.Name})
+	if err != nil {
+		return 0, err
+	}
+	err = recordEvent(tx, Event{WorkspaceID: to.WorkspaceID, ProjectID: to.ID, EntityType: EntityTask, EntityID: taskID,
+		Action: ActionTransferIn, Summary: title, OldValue: from.Name, NewValue: status})
+	if err != nil {
+		return 0, err
+	}
+	return int(dropped), tx.Commit()
+}
+
+// CopyBlockers makes taskID blocked by the tasks blocking fromID, leaving out
+// those in another workspace than taskID's. taskID must be a new task, one
+// that blocks nothing, so that no cycle can form.
+func CopyBlockers(db *sql.DB, fromID, taskID string) error {
+	_, err := db.Exec(`
+		INSERT OR IGNORE INTO task_dependencies(task_id, blocked_by)
+		SELECT ?, d.blocked_by
+		FROM task_dependencies d JOIN tasks b ON b.id = d.blocked_by JOIN projects p ON p.id = b.project_id
+		WHERE d.task_id = ? AND p.workspace_id = (
+			SELECT p.workspace_id FROM tasks t JOIN projects p ON p.id = t.project_id WHERE t.id = ?
+		)`, taskID, fromID, taskID)
+	return err
+}
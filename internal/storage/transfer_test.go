@@ -0,0 +1,190 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestMoveTaskToProject(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	work := Workspace{ID: uuid.New().String(), Name: "Work"}
+	home := Workspace{ID: uuid.New().String(), Name: "Home"}
+	for _, workspace := range []Workspace{work, home} {
+		if err := CreateWorkspace(db, workspace); err != nil {
+			t.Fatalf("failed to create workspace: %v", err)
+		}
+	}
+	api := Project{ID: uuid.New().String(), WorkspaceID: work.ID, Name: "API"}
+	web := Project{ID: uuid.New().String(), WorkspaceID: work.ID, Name: "Web"}
+	garden := Project{ID: uuid.New().String(), WorkspaceID: home.ID, Name: "Garden"}
+	for _, project := range []Project{api, web, garden} {
+		if err := CreateProject(db, project); err != nil {
+			t.Fatalf("failed to create project: %v", err)
+		}
+	}
+
+	schema := Task{ID: uuid.New().String(), ProjectID: api.ID, Title: "Schema", Status: "To Do"}
+	endpoint := Task{ID: uuid.New().String(), ProjectID: api.ID, Title: "Endpoint", Status: "In Progress"}
+	page := Task{ID: uuid.New().String(), ProjectID: web.ID, Title: "Page", Status: "To Do"}
+	for _, task := range []Task{schema, endpoint, page} {
+		if err := CreateTask(db, task); err != nil {
+			t.Fatalf("failed to create task: %v", err)
+		}
+	}
+	if err := CreateChecklistItem(db, ChecklistItem{ID: uuid.New().String(), TaskID: endpoint.ID, Text: "Tests"}); err != nil {
+		t.Fatalf("failed to create checklist item: %v", err)
+	}
+	// Page is blocked by Endpoint, which is blocked by Schema.
+	if err := AddDependency(db, page.ID, endpoint.ID); err != nil {
+		t.Fatalf("failed to add dependency: %v", err)
+	}
+	if err := AddDependency(db, endpoint.ID, schema.ID); err != nil {
+		t.Fatalf("failed to add dependency: %v", err)
+	}
+
+	// Within the workspace, dependencies stay.
+	if dropped, err := MoveTaskToProject(db, endpoint.ID, web.ID); err != nil || dropped != 0 {
+		t.Fatalf("failed to move task: %d dropped (%v)", dropped, err)
+	}
+	if moved, err := GetTask(db, endpoint.ID); err != nil || moved.ProjectID != web.ID || moved.Status != "In Progress" {
+		t.Errorf("expected Endpoint in Web, still In Progress, got %+v (%v)", moved, err)
+	}
+	if blockers, err := GetBlockers(db, page.ID); err != nil || len(blockers) != 1 {
+		t.Errorf("expected Page to stay blocked by Endpoint, got %+v (%v)", blockers, err)
+	}
+
+	// Across workspaces, they are dropped, but the checklist comes along.
+	if dropped, err := MoveTaskToProject(db, endpoint.ID, garden.ID); err != nil || dropped != 2 {
+		t.Fatalf("failed to move task: %d dropped (%v)", dropped, err)
+	}
+	for _, id := range []string{page.ID, endpoint.ID} {
+		if blockers, err := GetBlockers(db, id); err != nil || len(blockers) != 0 {
+			t.Errorf("expected no dependencies across workspaces, got %+v (%v)", blockers, err)
+		}
+	}
+	if items, err := GetChecklist(db, endpoint.ID); err != nil || len(items) != 1 {
+		t.Errorf("expected the checklist to move with the task, got %+v (%v)", items, err)
+	}
+
+	history, err := GetEventsForEntity(db, EntityTask, endpoint.ID)
+	if err != nil {
+		t.Fatalf("failed to get history: %v", err)
+	}
+	if len(history) != 5 || history[3].Action != ActionTransferOut || history[3].ProjectID != web.ID || history[3].NewValue != "Garden" ||
+		history[4].Action != ActionTransferIn || history[4].WorkspaceID != home.ID || history[4].OldValue != "Web" {
+		t.Errorf("expected the history to record both moves, got %+v", history)
+	}
+
+	transitions, err := GetTaskTransitions(db, web.ID)
+	if err != nil {
+		t.Fatalf("failed to get transitions: %v", err)
+	}
+	var endpointTransitions []TaskTransition
+	for _, transition := range transitions {
+		if transition.TaskID == endpoint.ID {
+			endpointTransitions = append(endpointTransitions, transition)
+		}
+	}
+	if len(endpointTransitions) != 2 || endpointTransitions[0].To != "In Progress" || endpointTransitions[1].From != "In Progress" || endpointTransitions[1].To != "" {
+		t.Errorf("expected Endpoint to join and leave Web In Progress, got %+v", endpointTransitions)
+	}
+}
+
+func TestCopyBlockers(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	work := Workspace{ID: uuid.New().String(), Name: "Work"}
+	home := Workspace{ID: uuid.New().String(), Name: "Home"}
+	for _, workspace := range []Workspace{work, home} {
+		if err := CreateWorkspace(db, workspace); err != nil {
+			t.Fatalf("failed to create workspace: %v", err)
+		}
+	}
+	api := Project{ID: uuid.New().String(), WorkspaceID: work.ID, Name: "API"}
+	garden := Project{ID: uuid.New().String(), WorkspaceID: home.ID, Name: "Garden"}
+	for _, project := range []Project{api, garden} {
+		if err := CreateProject(db, project); err != nil {
+			t.Fatalf("failed to create project: %v", err)
+		}
+	}
+
+	schema := Task{ID: uuid.New().String(), ProjectID: api.ID, Title: "Schema", Status: "To Do"}
+	endpoint := Task{ID: uuid.New().String(), ProjectID: api.ID, Title: "Endpoint", Status: "To Do"}
+	sameWorkspace := Task{ID: uuid.New().String(), ProjectID: api.ID, Title: "Endpoint", Status: "To Do"}
+	otherWorkspace := Task{ID: uuid.New().String(), ProjectID: garden.ID, Title: "Endpoint", Status: "To Do"}
+	for _, task := range []Task{schema, endpoint, sameWorkspace, otherWorkspace} {
+		if err := CreateTask(db, task); err != nil {
+			t.Fatalf("failed to create task: %v", err)
+		}
+	}
+	if err := AddDependency(db, endpoint.ID, schema.ID); err != nil {
+		t.Fatalf("failed to add dependency: %v", err)
+	}
+
+	for _, copied := range []Task{sameWorkspace, otherWorkspace} {
+		if err := CopyBlockers(db, endpoint.ID, copied.ID); err != nil {
+			t.Fatalf("failed to copy blockers: %v", err)
+		}
+	}
+	if blockers, err := GetBlockers(db, sameWorkspace.ID); err != nil || len(blockers) != 1 || blockers[0].ID != schema.ID {
+		t.Errorf("expected the copy to be blocked by Schema, got %+v (%v)", blockers, err)
+	}
+	if blockers, err := GetBlockers(db, otherWorkspace.ID); err != nil || len(blockers) != 0 {
+		t.Errorf("expected no blockers from another workspace, got %+v (%v)", blockers, err)
+	}
+}
+
+func TestMoveTaskToProjectIsAtomic(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	work := Workspace{ID: uuid.New().String(), Name: "Work"}
+	home := Workspace{ID: uuid.New().String(), Name: "Home"}
+	for _, workspace := range []Workspace{work, home} {
+		if err := CreateWorkspace(db, workspace); err != nil {
+			t.Fatalf("failed to create workspace: %v", err)
+		}
+	}
+	api := Project{ID: uuid.New().String(), WorkspaceID: work.ID, Name: "API"}
+	garden := Project{ID: uuid.New().String(), WorkspaceID: home.ID, Name: "Garden"}
+	for _, project := range []Project{api, garden} {
+		if err := CreateProject(db, project); err != nil {
+			t.Fatalf("failed to create project: %v", err)
+		}
+	}
+	schema := Task{ID: uuid.New().String(), ProjectID: api.ID, Title: "Schema", Status: "To Do"}
+	endpoint := Task{ID: uuid.New().String(), ProjectID: api.ID, Title: "Endpoint", Status: "To Do"}
+	for _, task := range []Task{schema, endpoint} {
+		if err := CreateTask(db, task); err != nil {
+			t.Fatalf("failed to create task: %v", err)
+		}
+	}
+	if err := AddDependency(db, endpoint.ID, schema.ID); err != nil {
+		t.Fatalf("failed to add dependency: %v", err)
+	}
+
+	// Recording the second event fails, after everything else was written.
+	_, err := db.Exec(`CREATE TRIGGER fail_transfer BEFORE INSERT ON events WHEN NEW.action = 'transfer in'
+		BEGIN SELECT RAISE(ABORT, 'no room in the log'); END`)
+	if err != nil {
+		t.Fatalf("failed to create trigger: %v", err)
+	}
+	if _, err := MoveTaskToProject(db, endpoint.ID, garden.ID); err == nil {
+		t.Fatal("expected the move to fail")
+	}
+
+	if task, _ := GetTask(db, endpoint.ID); task.ProjectID != api.ID {
+		t.Errorf("expected Endpoint to stay in API, got %+v", task)
+	}
+	if blockers, err := GetBlockers(db, endpoint.ID); err != nil || len(blockers) != 1 {
+		t.Errorf("expected the dependency to stay, got %+v (%v)", blockers, err)
+	}
+	history, err := GetEventsForEntity(db, EntityTask, endpoint.ID)
+	if err != nil || len(history) != 1 {
+		t.Errorf("expected only the creation in the history, got %+v (%v)", history, err)
+	}
+}
@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"database/sql"
+	"strconv"
+)
+
+// GetWIPLimits returns the work-in-progress limit of each column of a
+// project's board that has one, keyed by task status.
+func GetWIPLimits(db *sql.DB, projectID string) (map[string]int, error) {
+	rows, err := db.Query("SELECT status, max_tasks FROM wip_limits WHERE project_id = ?", projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	limits := make(map[string]int)
+	for rows.Next() {
+		var status string
+		var limit int
+		if err := rows.Scan(&status, &limit); err != nil {
+			return nil, err
+		}
+		limits[status] = limit
+	}
+	return limits, rows.Err()
+}
+
+// GetWIPRoom returns how many more tasks a column of a project's board can
+// take before it goes over its WIP limit, and whether it has a limit at all.
+func GetWIPRoom(db *sql.DB, projectID, status string) (int, bool, error) {
+	var room int
+	err := db.QueryRow(`
+		SELECT w.max_tasks - (SELECT COUNT(*) FROM tasks t WHERE t.project_id = w.project_id AND t.status = w.status)
+		FROM wip_limits w WHERE w.project_id = ? AND w.status = ?`, projectID, status).Scan(&room)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	return room, err == nil, err
+}
+
+// SetWIPLimit sets how many tasks a column may hold. A limit of 0 or less
+// removes it. The change is logged with the column as Summary and the limit
+// as NewValue.
+func SetWIPLimit(db *sql.DB, projectID, status string, limit int) error {
+	if limit <= 0 {
+		result, err := db.Exec("DELETE FROM wip_limits WHERE project_id = ? AND status = ?", projectID, status)
+		if err != nil {
+			return err
+		}
+		if n, err := result.RowsAffected(); err != nil || n == 0 {
+			return err
+		}
+		return recordEvent(db, Event{ProjectID: projectID, EntityType: EntityWIPLimit, EntityID: status, Action: ActionDelete, Summary: status})
+	}
+
+	stmt, err := db.Prepare(`INSERT INTO wip_limits(project_id, status, max_tasks) VALUES(?, ?, ?)
+		ON CONFLICT(project_id, status) DO UPDATE SET max_tasks = excluded.max_tasks`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(projectID, status, limit)
+	if err != nil {
+		return err
+	}
+	return recordEvent(db, Event{ProjectID: projectID, EntityType: EntityWIPLimit, EntityID: status, Action: ActionUpdate, Summary: status, NewValue: strconv.Itoa(limit)})
+}
+
+// GetWIPStrict reports whether moves over a WIP limit of the project's board
+// are refused rather than confirmed.
+func GetWIPStrict(db *sql.DB, projectID string) (bool, error) {
+	var strict bool
+	err := db.QueryRow("SELECT wip_strict FROM projects WHERE id = ?", projectID).Scan(&strict)
+	return strict, err
+}
+
+// SetWIPStrict sets whether moves over a WIP limit are refused. The change
+// is logged without a Summary, with "strict" or "confirm" as NewValue.
+func SetWIPStrict(db *sql.DB, projectID string, strict bool) error {
+	stmt, err := db.Prepare("UPDATE projects SET wip_strict = ? WHERE id = ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(strict, projectID)
+	if err != nil {
+		return err
+	}
+	mode := "confirm"
+	if strict {
+		mode = "strict"
+	}
+	return recordEvent(db, Event{ProjectID: projectID, EntityType: EntityWIPLimit, EntityID: projectID, Action: ActionUpdate, NewValue: mode})
+}
@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestWIPLimits(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	workspace := Workspace{ID: uuid.New().String(), Name: "Work"}
+	if err := CreateWorkspace(db, workspace); err != nil {
+		t.Fatalf("failed to create workspace: %v", err)
+	}
+	project := Project{ID: uuid.New().String(), WorkspaceID: workspace.ID, Name: "Board"}
+	if err := CreateProject(db, project); err != nil {
+		t.Fatalf("failed to create project: %v", err)
+	}
+
+	for _, limit := range []int{5, 3} {
+		if err := SetWIPLimit(db, project.ID, "In Progress", limit); err != nil {
+			t.Fatalf("failed to set WIP limit: %v", err)
+		}
+	}
+	if limits, err := GetWIPLimits(db, project.ID); err != nil || len(limits) != 1 || limits["In Progress"] != 3 {
+		t.Fatalf("expected In Progress limited to 3, got %v (%v)", limits, err)
+	}
+	if err := CreateTask(db, Task{ID: uuid.New().String(), ProjectID: project.ID, Title: "Write", Status: "In Progress"}); err != nil {
+		t.Fatalf("failed to create task: %v", err)
+	}
+	if room, limited, err := GetWIPRoom(db, project.ID, "In Progress"); err != nil || !limited || room != 2 {
+		t.Errorf("expected room for 2 more tasks, got %d, %v (%v)", room, limited, err)
+	}
+	if _, limited, err := GetWIPRoom(db, project.ID, "Done"); err != nil || limited {
+		t.Errorf("expected Done to have no limit, got %v (%v)", limited, err)
+	}
+	if err := SetWIPLimit(db, project.ID, "In Progress", 0); err != nil {
+		t.Fatalf("failed to remove WIP limit: %v", err)
+	}
+	if limits, _ := GetWIPLimits(db, project.ID); len(limits) != 0 {
+		t.Errorf("expected no limits, got %v", limits)
+	}
+
+	if strict, err := GetWIPStrict(db, project.ID); err != nil || strict {
+		t.Fatalf("expected confirmations by default, got %v (%v)", strict, err)
+	}
+	if err := SetWIPStrict(db, project.ID, true); err != nil {
+		t.Fatalf("failed to set strict WIP limits: %v", err)
+	}
+	if strict, _ := GetWIPStrict(db, project.ID); !strict {
+		t.Error("expected strict WIP limits")
+	}
+}
@@ -3,9 +3,12 @@ package main
 import (
 	"database/sql"
 	"encoding/json"
+	"fmt"
 	"log"
 	"os"
+	"slices"
 	"strings"
+	"time"
 
 	generalview "github.com/Ceinl/Go-dashboard/internal/generalView"
 	"github.com/Ceinl/Go-dashboard/internal/module"
@@ -15,7 +18,9 @@ import (
 )
 
 type AppConfig struct {
-	LastActiveWorkspaceID string `json:"last_active_workspace_id"`
+	LastActiveWorkspaceID string              `json:"last_active_workspace_id"`
+	Timer                 module.TimerConfig  `json:"timer"`
+	Sysmon                module.SysmonConfig `json:"sysmon"`
 }
 
 const (
@@ -32,6 +37,10 @@ const (
 	HelpState
 	ConfirmationState
 	WorkspaceModuleSelectorState
+	SearchState
+	ActivityState
+	OverviewState
+	TimeReportState
 )
 
 type model struct {
@@ -41,6 +50,8 @@ type model struct {
 	projects                    []storage.Project
 	currentModule               module.Module
 	activeModules               []module.Module
+	activeModuleNames           []string
+	persistentModules           map[string]module.Module // survive project and workspace switches
 	currentModuleIndex          int
 	createWorkspaceView         generalview.CreateWorkspaceView
 	deleteWorkspaceView         generalview.DeleteWorkspaceView
@@ -50,6 +61,10 @@ type model struct {
 	workspaceModuleSelectorView generalview.WorkspaceModuleSelectorView
 	helpView                    generalview.HelpView
 	confirmationView            generalview.ConfirmationView
+	searchView                  generalview.SearchView
+	activityView                generalview.ActivityView
+	overviewView                generalview.OverviewView
+	timeReportView              generalview.TimeReportView
 
 	db     *sql.DB
 	config AppConfig
@@ -58,6 +73,9 @@ type model struct {
 	statusBar  generalview.StatusBar
 	body       generalview.Body
 
+	tracking     *storage.TrackedEntry // running time entry, shown in the status bar
+	trackingTick int                   // generation of the status bar refresh tick
+
 	sizeInitialized bool
 	width           int
 	height          int
@@ -82,8 +100,17 @@ func (m *model) Init() tea.Cmd {
 		}
 	}
 
+	// Create the recurring tasks that came due while the dashboard was
+	// closed before the modules load their tasks.
+	startupRecurrence := m.generateRecurringTasks()
+
 	m.reloadProjects()
 
+	// Land on the workspace overview rather than straight in a project.
+	if m.currentWorkspace.ID != "" {
+		m.openOverview()
+	}
+
 	return tea.Batch(
 		m.projectBar.Init(),
 		m.statusBar.Init(),
@@ -91,9 +118,63 @@ func (m *model) Init() tea.Cmd {
 		m.createWorkspaceView.Init(),
 		m.deleteWorkspaceView.Init(),
 		m.swapWorkspaceView.Init(),
+		m.refreshTracking(),
+		startupRecurrence,
+		tickRecurrence(),
 	)
 }
 
+// recurrenceInterval is how often the recurring task scheduler runs, so that
+// a new period's tasks show up on the board without a restart.
+const recurrenceInterval = time.Minute
+
+// recurrenceTickMsg runs the recurring task scheduler again.
+type recurrenceTickMsg struct{}
+
+func tickRecurrence() tea.Cmd {
+	return tea.Tick(recurrenceInterval, func(time.Time) tea.Msg {
+		return recurrenceTickMsg{}
+	})
+}
+
+// generateRecurringTasks creates the occurrences of recurring tasks that are
+// due and, if there are any, tells the modules to reload.
+func (m *model) generateRecurringTasks() tea.Cmd {
+	created, err := module.GenerateRecurringTasks(m.db, time.Now())
+	if err != nil {
+		log.Printf("Error creating recurring tasks: %v", err)
+	}
+	if len(created) == 0 {
+		return nil
+	}
+	return func() tea.Msg { return module.TasksChangedMsg{} }
+}
+
+// trackingTickMsg refreshes the elapsed time of the running time entry.
+type trackingTickMsg struct{ generation int }
+
+// refreshTracking reloads the running time entry and, if there is one,
+// starts ticking so that the status bar stays current.
+func (m *model) refreshTracking() tea.Cmd {
+	running, err := storage.GetRunningTimeEntry(m.db)
+	if err != nil {
+		log.Printf("Error loading running time entry: %v", err)
+	}
+	m.tracking = running
+	m.trackingTick++
+	if m.tracking == nil {
+		return nil
+	}
+	return m.tickTracking()
+}
+
+func (m *model) tickTracking() tea.Cmd {
+	generation := m.trackingTick
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return trackingTickMsg{generation: generation}
+	})
+}
+
 type YesDeleteWorkspaceMsg struct{ ID string }
 type NoDeleteWorkspaceMsg struct{}
 type YesDeleteProjectMsg struct{ ID string }
@@ -123,6 +204,10 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.deleteWorkspaceView, _ = m.deleteWorkspaceView.Update(msg)
 		m.swapWorkspaceView, _ = m.swapWorkspaceView.Update(msg)
 		m.createProjectView, _ = m.createProjectView.Update(msg)
+		m.searchView, _ = m.searchView.Update(msg)
+		m.activityView, _ = m.activityView.Update(msg)
+		m.overviewView, _ = m.overviewView.Update(msg)
+		m.timeReportView, _ = m.timeReportView.Update(msg)
 		if m.currentModule != nil {
 			m.currentModule, cmd = m.currentModule.Update(msg)
 			cmds = append(cmds, cmd)
@@ -139,6 +224,16 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, cmd
 		}
 
+		if m.state == SearchState {
+			m.searchView, cmd = m.searchView.Update(msg)
+			return m, cmd
+		}
+
+		if m.state == TimeReportState {
+			m.timeReportView, cmd = m.timeReportView.Update(msg)
+			return m, cmd
+		}
+
 		// Handle command mode exclusively
 		if m.statusBar.CommandMode {
 			m.statusBar, cmd = m.statusBar.Update(msg)
@@ -191,7 +286,25 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case ConfirmationState:
 			m.confirmationView, cmd = m.confirmationView.Update(msg)
 			cmds = append(cmds, cmd)
+		case ActivityState:
+			m.activityView, cmd = m.activityView.Update(msg)
+			cmds = append(cmds, cmd)
+		case OverviewState:
+			m.overviewView, cmd = m.overviewView.Update(msg)
+			cmds = append(cmds, cmd)
 		default:
+			// Modules typing into an input get every key, so that e.g. ":"
+			// doesn't open command mode halfway through a filter.
+			if m.moduleCapturingInput() {
+				m.currentModule, cmd = m.currentModule.Update(msg)
+				return m, cmd
+			}
+
+			// "/" searches everywhere, unless the module filters its own items.
+			if msg.String() == "/" && !m.moduleFilters() {
+				return m, func() tea.Msg { return generalview.SearchCommandMsg{} }
+			}
+
 			// If not in a wizard state, pass keys to the status bar (for entering command mode)
 			// and the active module.
 			m.statusBar, cmd = m.statusBar.Update(msg)
@@ -257,6 +370,7 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.reloadProjects()
 			cmd = m.reloadActiveModules()
 			cmds = append(cmds, cmd)
+			m.openOverview()
 		}
 		return m, tea.Batch(cmds...)
 	case generalview.DoneCreateProjectMsg:
@@ -316,9 +430,71 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.state = HelpState
 		m.helpView = generalview.NewHelpView()
 		cmds = append(cmds, m.helpView.Init())
+	case generalview.SearchCommandMsg:
+		m.state = SearchState
+		m.searchView = generalview.NewSearchView(m.db)
+		m.searchView, _ = m.searchView.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+		return m, m.searchView.Init()
+	case generalview.DoneSearchMsg:
+		m.state = projectState
+		if msg.Result.ItemID != "" {
+			cmds = append(cmds, m.jumpToSearchResult(msg.Result))
+		}
+		return m, tea.Batch(cmds...)
+	case generalview.ActivityCommandMsg:
+		m.state = ActivityState
+		m.activityView = generalview.NewActivityView(m.db, m.currentWorkspace, m.currentProject)
+		m.activityView, _ = m.activityView.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+		return m, m.activityView.Init()
+	case generalview.DoneActivityMsg:
+		m.state = projectState
+		return m, nil
+	case generalview.OverviewCommandMsg:
+		m.openOverview()
+		return m, m.overviewView.Init()
+	case generalview.DoneOverviewMsg:
+		m.state = projectState
+		if msg.Project.ID != "" && msg.Project.ID != m.currentProject.ID {
+			m.currentProject = msg.Project
+			m.reloadProjects()
+			cmds = append(cmds, m.reloadActiveModules())
+		}
+		return m, tea.Batch(cmds...)
+	case generalview.TimeReportCommandMsg:
+		m.state = TimeReportState
+		m.timeReportView = generalview.NewTimeReportView(m.db)
+		m.timeReportView, _ = m.timeReportView.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+		return m, m.timeReportView.Init()
+	case generalview.DoneTimeReportMsg:
+		m.state = projectState
+		return m, nil
+	case trackingTickMsg:
+		if msg.generation == m.trackingTick && m.tracking != nil {
+			return m, m.tickTracking()
+		}
+		return m, nil
+	case recurrenceTickMsg:
+		return m, tea.Batch(m.generateRecurringTasks(), tickRecurrence())
+	case module.OpenTaskMsg:
+		return m, m.jumpToSearchResult(storage.SearchResult{
+			Kind:        storage.SearchKindTask,
+			ItemID:      msg.TaskID,
+			ProjectID:   msg.ProjectID,
+			WorkspaceID: msg.WorkspaceID,
+		})
+	case module.TimeTrackingMsg:
+		cmds = append(cmds, m.refreshTracking(), m.updateModules(msg))
+		return m, tea.Batch(cmds...)
+	case module.StartFocusMsg:
+		// The timer runs in the background when the workspace doesn't show it.
+		cmds = append(cmds, m.ensurePersistentModule("timer"), m.updateModules(msg))
+	default:
+		// Anything else is the result of a command a module started, such as
+		// an external editor exiting or a timer tick, so hand it to the modules.
+		cmds = append(cmds, m.updateModules(msg))
 	}
 
-	if m.state != CreateWorkspaceState && m.state != DeleteWorkspaceState && m.state != SwapWorkspaceState && m.state != CreateProjectState && m.state != ModuleSelectorState && m.state != HelpState && m.state != ConfirmationState && m.state != WorkspaceModuleSelectorState {
+	if !m.inOverlayState() {
 		var projectBarCmd tea.Cmd
 		m.projectBar, projectBarCmd = m.projectBar.Update(msg)
 		cmds = append(cmds, projectBarCmd)
@@ -327,6 +503,36 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+func (m *model) openOverview() {
+	m.state = OverviewState
+	m.overviewView = generalview.NewOverviewView(m.db, m.currentWorkspace, m.currentProject.ID)
+	m.overviewView, _ = m.overviewView.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+}
+
+// moduleCapturingInput reports whether the active module currently needs
+// every key press for itself.
+func (m *model) moduleCapturingInput() bool {
+	capturer, ok := m.currentModule.(module.InputCapturer)
+	return ok && capturer.CapturingInput()
+}
+
+// moduleFilters reports whether the current module uses "/" itself.
+func (m *model) moduleFilters() bool {
+	_, ok := m.currentModule.(module.Filterer)
+	return ok
+}
+
+// inOverlayState reports whether a wizard or overlay currently owns the screen.
+func (m *model) inOverlayState() bool {
+	switch m.state {
+	case CreateWorkspaceState, DeleteWorkspaceState, SwapWorkspaceState, CreateProjectState,
+		ModuleSelectorState, HelpState, ConfirmationState, WorkspaceModuleSelectorState, SearchState,
+		ActivityState, OverviewState, TimeReportState:
+		return true
+	}
+	return false
+}
+
 func (m *model) View() string {
 	if !m.sizeInitialized {
 		return "Loading..."
@@ -349,11 +555,21 @@ func (m *model) View() string {
 		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.helpView.View())
 	} else if m.state == ConfirmationState {
 		return m.confirmationView.View()
+	} else if m.state == SearchState {
+		return m.searchView.View()
+	} else if m.state == ActivityState {
+		return m.activityView.View()
+	} else if m.state == OverviewState {
+		return m.overviewView.View()
+	} else if m.state == TimeReportState {
+		return m.timeReportView.View()
 	}
 
 	// Regular view layout
 	projectBarView := m.projectBar.View()
-	statusBarView := m.statusBar.View()
+	statusBar := m.statusBar
+	statusBar.Indicators = m.statusIndicators()
+	statusBarView := statusBar.View()
 
 	availableHeight := m.height - lipgloss.Height(projectBarView) - lipgloss.Height(statusBarView)
 
@@ -410,25 +626,153 @@ func (m *model) reloadProjects() {
 	m.statusBar.ActiveProject = m.currentProject.Name
 }
 
+// newModule constructs the module registered under name for the current
+// project, or returns nil for an unknown name.
+func (m *model) newModule(name string) module.Module {
+	switch name {
+	case "linksaver":
+		return module.NewLinkSaver(m.db, m.currentProject.ID)
+	case "placeholder":
+		return module.NewPlaceholder()
+	case "kanban":
+		return module.NewKanban(m.db, m.currentProject.ID)
+	case "twitter":
+		return module.NewTwitter(m.db, m.currentProject.ID)
+	case "notes":
+		return module.NewNotes(m.db, m.currentProject.ID)
+	case "timer":
+		return module.NewTimer(m.db, m.config.Timer)
+	case "calendar":
+		return module.NewCalendar(m.db, m.currentWorkspace.ID, m.currentProject.ID)
+	case "git":
+		return module.NewGit(m.db, m.currentProject.ID)
+	case "runner":
+		return module.NewRunner(m.db, m.currentProject.ID)
+	case "habits":
+		return module.NewHabits(m.db, m.currentWorkspace.ID)
+	case "feeds":
+		return module.NewFeeds(m.db, m.currentProject.ID)
+	case "logtail":
+		return module.NewLogtail(m.db, m.currentProject.ID)
+	case "sysmon":
+		return module.NewSysmon(m.config.Sysmon)
+	case "snippets":
+		return module.NewSnippets(m.db, m.currentProject.ID)
+	case "journal":
+		return module.NewJournal(m.db, m.currentWorkspace.ID)
+	case "countdown":
+		return module.NewCountdown(m.db, m.currentProject.ID)
+	}
+	return nil
+}
+
+// statusIndicators collects the running time entry and the status texts of
+// the active and background modules for the status bar.
+func (m *model) statusIndicators() string {
+	var texts []string
+	if m.tracking != nil {
+		elapsed := time.Since(m.tracking.StartedAt).Round(time.Second)
+		texts = append(texts, fmt.Sprintf("● %s %d:%02d:%02d", m.tracking.TaskTitle,
+			int(elapsed.Hours()), int(elapsed.Minutes())%60, int(elapsed.Seconds())%60))
+	}
+	for _, mod := range slices.Concat(m.activeModules, m.backgroundModules()) {
+		if reporter, ok := mod.(module.StatusReporter); ok {
+			if text := reporter.StatusText(); text != "" {
+				texts = append(texts, text)
+			}
+		}
+	}
+	return strings.Join(texts, "  ")
+}
+
+// updateModules hands msg to the active modules and to the background ones.
+func (m *model) updateModules(msg tea.Msg) tea.Cmd {
+	var cmds []tea.Cmd
+	for i, mod := range m.activeModules {
+		var cmd tea.Cmd
+		m.activeModules[i], cmd = mod.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+	for _, name := range m.backgroundModuleNames() {
+		var cmd tea.Cmd
+		m.persistentModules[name], cmd = m.persistentModules[name].Update(msg)
+		cmds = append(cmds, cmd)
+	}
+	if len(m.activeModules) > 0 {
+		m.currentModule = m.activeModules[m.currentModuleIndex]
+	}
+	return tea.Batch(cmds...)
+}
+
+// backgroundModuleNames returns, in a stable order, the persistent modules
+// that keep running although the current workspace doesn't show them.
+func (m *model) backgroundModuleNames() []string {
+	var names []string
+	for name := range m.persistentModules {
+		if !slices.Contains(m.activeModuleNames, name) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+func (m *model) backgroundModules() []module.Module {
+	var mods []module.Module
+	for _, name := range m.backgroundModuleNames() {
+		mods = append(mods, m.persistentModules[name])
+	}
+	return mods
+}
+
+// ensurePersistentModule creates the persistent module name if it doesn't
+// exist yet, so that it can run in the background.
+func (m *model) ensurePersistentModule(name string) tea.Cmd {
+	if m.persistentModules[name] != nil {
+		return nil
+	}
+	newModule := m.newModule(name)
+	if _, ok := newModule.(module.Persistent); !ok {
+		return nil
+	}
+	if m.persistentModules == nil {
+		m.persistentModules = make(map[string]module.Module)
+	}
+	var cmd tea.Cmd
+	if m.width > 0 && m.height > 0 {
+		newModule, cmd = newModule.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+	}
+	m.persistentModules[name] = newModule
+	return tea.Batch(cmd, newModule.Init())
+}
+
 func (m *model) reloadActiveModules() tea.Cmd {
 	var initCmds []tea.Cmd
+	for _, old := range m.activeModules {
+		if _, ok := old.(module.Persistent); ok {
+			continue
+		}
+		if closer, ok := old.(module.Closer); ok {
+			closer.Close()
+		}
+	}
 	m.activeModules = []module.Module{}
+	m.activeModuleNames = []string{}
 	if m.currentWorkspace.ID != "" && m.currentProject.ID != "" {
 		moduleNames := strings.Split(m.currentWorkspace.ActiveModules, ",")
 		for _, name := range moduleNames {
 			if name == "" {
 				continue
 			}
-			var newModule module.Module
-			switch name {
-			case "linksaver":
-				newModule = module.NewLinkSaver(m.db, m.currentProject.ID)
-			case "placeholder":
-				newModule = module.NewPlaceholder()
-			case "kanban":
-				newModule = module.NewKanban(m.db, m.currentProject.ID)
-			case "twitter":
-				newModule = module.NewTwitter(m.db, m.currentProject.ID)
+			newModule := m.persistentModules[name]
+			if newModule == nil {
+				newModule = m.newModule(name)
+			}
+			if _, ok := newModule.(module.Persistent); ok {
+				if m.persistentModules == nil {
+					m.persistentModules = make(map[string]module.Module)
+				}
+				m.persistentModules[name] = newModule
 			}
 			if newModule != nil {
 				if m.width > 0 && m.height > 0 {
@@ -437,6 +781,7 @@ func (m *model) reloadActiveModules() tea.Cmd {
 					initCmds = append(initCmds, cmd)
 				}
 				m.activeModules = append(m.activeModules, newModule)
+				m.activeModuleNames = append(m.activeModuleNames, name)
 				initCmds = append(initCmds, newModule.Init())
 			}
 		}
@@ -453,6 +798,62 @@ func (m *model) reloadActiveModules() tea.Cmd {
 	return tea.Batch(initCmds...)
 }
 
+// moduleForSearchKind maps a search result kind to the module that shows it.
+func moduleForSearchKind(kind string) string {
+	switch kind {
+	case storage.SearchKindTask:
+		return "kanban"
+	case storage.SearchKindLink:
+		return "linksaver"
+	case storage.SearchKindTweet:
+		return "twitter"
+	}
+	return ""
+}
+
+// jumpToSearchResult switches to the workspace, project and module holding
+// the result and moves the module's cursor onto the matching item.
+func (m *model) jumpToSearchResult(result storage.SearchResult) tea.Cmd {
+	if result.WorkspaceID != m.currentWorkspace.ID {
+		ws, err := storage.GetWorkspace(m.db, result.WorkspaceID)
+		if err != nil {
+			log.Printf("Error getting workspace for search result: %v", err)
+			return nil
+		}
+		m.currentWorkspace = ws
+		m.statusBar.ActiveWorkspace = ws.Name
+		m.config.LastActiveWorkspaceID = ws.ID
+		if err := saveConfig(m.config); err != nil {
+			log.Printf("Error saving config: %v", err)
+		}
+	}
+
+	project, err := storage.GetProject(m.db, result.ProjectID)
+	if err != nil {
+		log.Printf("Error getting project for search result: %v", err)
+		return nil
+	}
+	m.currentProject = project
+	m.reloadProjects()
+	cmd := m.reloadActiveModules()
+
+	target := moduleForSearchKind(result.Kind)
+	for i, name := range m.activeModuleNames {
+		if name != target {
+			continue
+		}
+		m.currentModuleIndex = i
+		m.currentModule = m.activeModules[i]
+		if focuser, ok := m.currentModule.(module.ItemFocuser); ok {
+			focuser.FocusItem(result.ItemID)
+		}
+		return cmd
+	}
+
+	log.Printf("Module %q is not active in workspace %q", target, m.currentWorkspace.Name)
+	return cmd
+}
+
 func loadConfig() (AppConfig, error) {
 	var config AppConfig
 	file, err := os.Open("settings.json")
@@ -484,7 +885,7 @@ func saveConfig(config AppConfig) error {
 func main() {
 	f, err := tea.LogToFile("debug.Log", "debug")
 	if err != nil {
-		log.Fatalf("err: %w", err)
+		log.Fatalf("err: %v", err)
 	}
 	defer f.Close()
 